@@ -2,14 +2,19 @@ package cmd
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/Xanonymous-GitHub/claude-switch/internal/config"
 	"github.com/Xanonymous-GitHub/claude-switch/internal/editor"
+	"github.com/Xanonymous-GitHub/claude-switch/internal/jsonutil"
+	"github.com/Xanonymous-GitHub/claude-switch/internal/output"
 	"github.com/Xanonymous-GitHub/claude-switch/internal/storage"
+	"github.com/Xanonymous-GitHub/claude-switch/internal/validation"
 	"github.com/spf13/cobra"
 )
 
@@ -24,6 +29,37 @@ This command will:
 3. After editing, prompt for a name and description
 4. Save the configuration for future use
 
+--show-changes prints the keys changed in the editor, relative to the
+settings the editor started from, before the configuration is saved.
+
+After editing, add shows a summary of the configuration (top-level keys,
+size and a one-line preview) and asks whether to save it, edit it again or
+cancel. --no-review skips this step; it is also skipped when standard
+input is not a terminal.
+
+Quitting the editor with a non-zero exit (such as :cq in vim) cancels the
+add without saving anything.
+
+With --from-current, the current ~/.claude/settings.json is validated and
+saved as-is, skipping the editor. --file does the same for any file.
+
+With --format toml the configuration is stored as TOML and converted to
+JSON when applied. The editor and --from-current start from the current
+settings converted to TOML; a --file is read as TOML.
+
+A --file ending in .jsonc or .json5 is read as JSONC: comments and
+trailing commas are accepted but not kept, since the configuration is
+stored as strict JSON. 'list --fields source' shows which configurations
+came from JSONC. An explicit --format takes precedence over the
+extension, so --format json reads such a file strictly.
+
+--trim removes keys whose value is null, "", {} or [] (recursively) before
+storing. The trimmed configuration is re-encoded with sorted keys.
+
+--validate-only (with --file and --name) is a strict, non-interactive mode
+for pipelines: the file is stored only if it is valid, otherwise the
+command fails without creating anything and without prompting.
+
 The configuration will be stored in ~/.claude-switch/configs/ and can be
 applied later using the 'apply' command.`,
 	Example: `  # Add a new configuration
@@ -31,68 +67,431 @@ applied later using the 'apply' command.`,
 
   # The command will open your editor, then prompt for:
   # - Configuration name
-  # - Optional description`,
+  # - Optional description
+
+  # Save the current settings as they are, skipping the editor
+  claude-switch add --from-current --name my-setup
+
+  # Author a configuration in TOML
+  claude-switch add --format toml
+
+  # Take a multi-line description from a sidecar file
+  claude-switch add --file team.json --name team --describe-from-file team.md
+
+  # Validate and store a generated file in CI
+  claude-switch add --file generated.json --name ci --validate-only`,
 	RunE: runAdd,
 }
 
 func init() {
 	addCmd.Flags().StringP("name", "n", "", "Configuration name (will prompt if not provided)")
 	addCmd.Flags().StringP("description", "d", "", "Configuration description")
+	addCmd.Flags().String("describe-from-file", "", "Read the description from this file (may span several lines)")
+	addCmd.MarkFlagsMutuallyExclusive("description", "describe-from-file")
+	addCmd.Flags().Bool("from-current", false, "Save the current settings.json as-is without opening the editor")
+	addCmd.Flags().String("file", "", "Save this settings file as-is without opening the editor")
+	addCmd.Flags().String("format", config.FormatJSON, "Format to store the configuration in: json or toml")
+	addCmd.Flags().Bool("validate-only", false, "With --file and --name, store the file only if valid and never prompt")
+	addCmd.Flags().Bool("trim", false, "Remove keys whose value is null, \"\", {} or [] before storing")
+	addCmd.MarkFlagsMutuallyExclusive("from-current", "file")
+	addCmd.Flags().Bool("show-changes", false, "Show the keys changed in the editor before saving")
+	addCmd.MarkFlagsMutuallyExclusive("show-changes", "from-current")
+	addCmd.MarkFlagsMutuallyExclusive("show-changes", "file")
+	addCmd.Flags().BoolP("no-review", "y", false, "Save after editing without reviewing a summary first")
+	addGitCommitFlag(addCmd)
+	addPrereqCheckFlag(addCmd)
+	addCmd.Flags().String("claude-version", "", "Claude Code version this configuration targets (default: detected)")
+	addCmd.Flags().Bool("auto-name", false, "Append a numeric suffix to the name if it is already taken")
+	addMaxSizeFlag(addCmd)
 }
 
 func runAdd(cmd *cobra.Command, args []string) error {
-	// Check prerequisites
-	if err := checkPrerequisites(); err != nil {
+	fromCurrent, _ := cmd.Flags().GetBool("from-current")
+	file, _ := cmd.Flags().GetString("file")
+	validateOnly, _ := cmd.Flags().GetBool("validate-only")
+	formatName, _ := cmd.Flags().GetString("format")
+
+	format, err := config.ParseFormat(formatName)
+	if err != nil {
 		return err
 	}
 
+	if validateOnly {
+		name, _ := cmd.Flags().GetString("name")
+		if file == "" || strings.TrimSpace(name) == "" {
+			return fmt.Errorf("--validate-only requires --file and --name")
+		}
+	}
+
+	// Check prerequisites (a given file does not need Claude Code installed)
+	if file == "" {
+		if err := checkPrerequisitesUnlessSkipped(cmd); err != nil {
+			return err
+		}
+	}
+
 	// Check if editor is available
-	if !editor.IsEditorAvailable() {
+	if !fromCurrent && file == "" && !editor.IsEditorAvailable() {
 		return fmt.Errorf("no editor found. Please set the $EDITOR environment variable or install a default editor")
 	}
 
 	// Create config manager
-	manager, err := config.NewManager()
+	manager, err := newManager(cmd)
 	if err != nil {
 		return fmt.Errorf("failed to initialize config manager: %w", err)
 	}
 
+	setMaxConfigSize(cmd, manager)
+
+	if fromCurrent {
+		return addFromCurrent(cmd, manager, format)
+	}
+	if file != "" {
+		return addFromFile(cmd, manager, file, format)
+	}
+
 	// Create temporary file for editing
-	tempFile, err := createTempConfigFile(manager)
+	tempFile, err := createTempConfigFile(manager, format)
 	if err != nil {
 		return fmt.Errorf("failed to create temporary config file: %w", err)
 	}
 	defer os.Remove(tempFile) // Clean up temp file
 
+	// Keep the starting point to report what the edit changed
+	seed, err := os.ReadFile(tempFile)
+	if err != nil {
+		return fmt.Errorf("failed to read temporary config file: %w", err)
+	}
+
 	// Show instructions
-	fmt.Println("🎯 Creating new Claude Code configuration...")
-	fmt.Printf("📝 Opening editor for file: %s\n", tempFile)
-	fmt.Println("📋 Instructions:")
-	fmt.Println("   • Edit the JSON configuration as needed")
-	fmt.Println("   • Save and close the editor to continue")
-	fmt.Println("   • Press Ctrl+C to cancel")
-	fmt.Println()
-
-	// Open editor
-	if err := editor.OpenEditor(tempFile); err != nil {
+	output.Println("🎯 Creating new Claude Code configuration...")
+	output.Printf("📝 Opening editor for file: %s\n", tempFile)
+	output.Println("📋 Instructions:")
+	output.Printf("   • Edit the %s configuration as needed\n", strings.ToUpper(format))
+	output.Println("   • Save and close the editor to continue")
+	output.Println("   • Press Ctrl+C to cancel")
+	output.Println()
+
+	noReview, _ := cmd.Flags().GetBool("no-review")
+	review := !noReview && output.Interactive()
+
+	for {
+		// Open editor
+		if err := openEditor(cmd, tempFile); err != nil {
+			return err
+		}
+
+		// Validate the edited file
+		if err := validateSettingsFile(tempFile, format); err != nil {
+			output.Fprintf(os.Stderr, "❌ Invalid configuration in edited file: %v\n", err)
+			output.Fprint(os.Stderr, "Do you want to edit again? (y/N): ")
+			reader := bufio.NewReader(os.Stdin)
+			response, _ := reader.ReadString('\n')
+			if strings.ToLower(strings.TrimSpace(response)) == "y" {
+				return runAdd(cmd, args) // Recursively try again
+			}
+			return fmt.Errorf("configuration creation cancelled due to invalid %s", strings.ToUpper(format))
+		}
+
+		if showChanges, _ := cmd.Flags().GetBool("show-changes"); showChanges {
+			if err := printEditChanges(seed, tempFile, format); err != nil {
+				return err
+			}
+		}
+
+		if !review {
+			break
+		}
+
+		choice, err := reviewConfig(tempFile, format)
+		if err != nil {
+			return err
+		}
+		if choice == "c" {
+			output.Println("❌ Operation cancelled")
+			return nil
+		}
+		if choice == "s" {
+			break
+		}
+		// Anything else edits the same file again
+	}
+
+	return saveNewConfig(cmd, manager, tempFile, format, "")
+}
+
+// reviewConfig summarizes the configuration at path, written in format, and
+// asks whether to save it, edit it again or cancel. It returns "s", "e" or
+// "c"; an empty answer saves.
+func reviewConfig(path, format string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read edited file: %w", err)
+	}
+	settings, err := decodeSettings(data, format)
+	if err != nil {
+		return "", err
+	}
+
+	keys := make([]string, 0, len(settings))
+	for key := range settings {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	if len(keys) == 0 {
+		keys = []string{"(none)"}
+	}
+
+	output.Println()
+	output.Println("🔎 Review the configuration before saving:")
+	output.Printf("   Keys: %s\n", strings.Join(keys, ", "))
+	output.Printf("   Size: %s\n", storage.FormatSize(int64(len(data))))
+	output.Printf("   Preview: %s\n", ellipsize(compactJSON(settings), 72))
+	output.Println()
+
+	for {
+		response, err := promptForInput("[s]ave, [e]dit again or [c]ancel? (S/e/c): ")
+		if err != nil {
+			return "", fmt.Errorf("failed to read choice: %w", err)
+		}
+
+		switch strings.ToLower(response) {
+		case "", "s", "save":
+			return "s", nil
+		case "e", "edit":
+			return "e", nil
+		case "c", "cancel":
+			return "c", nil
+		}
+	}
+}
+
+// openEditor opens path in the editor. Quitting the editor with a non-zero
+// exit cancels the command; the caller discards the file.
+func openEditor(cmd *cobra.Command, path string) error {
+	err := editor.OpenEditor(path)
+	if errors.Is(err, editor.ErrCancelled) {
+		cmd.SilenceUsage = true
+		return err
+	}
+	if err != nil {
 		return fmt.Errorf("editor failed: %w", err)
 	}
+	return nil
+}
+
+// printEditChanges prints the key-path changes between the seed settings and
+// the edited file, both written in format
+func printEditChanges(seed []byte, editedPath, format string) error {
+	edited, err := os.ReadFile(editedPath)
+	if err != nil {
+		return fmt.Errorf("failed to read edited file: %w", err)
+	}
+
+	before, err := decodeSettings(seed, format)
+	if err != nil {
+		return err
+	}
+	after, err := decodeSettings(edited, format)
+	if err != nil {
+		return err
+	}
+
+	changes := jsonutil.Diff(before, after)
+	if len(changes) == 0 {
+		output.Println("🔀 No changes from the starting settings")
+		return nil
+	}
+
+	output.Printf("🔀 %d change%s from the starting settings:\n", len(changes), pluralize(len(changes)))
+	printChanges(changes)
+	return nil
+}
+
+// decodeSettings decodes settings written in format into a JSON object
+func decodeSettings(data []byte, format string) (map[string]interface{}, error) {
+	settings, err := config.ToJSON(data, format)
+	if err != nil {
+		return nil, err
+	}
+	return jsonutil.ParseObject(settings)
+}
+
+// addFromCurrent stores the current settings.json as-is, without opening the editor
+func addFromCurrent(cmd *cobra.Command, manager *config.Manager, format string) error {
+	settingsPath, err := manager.GetClaudeSettingsPath()
+	if err != nil {
+		return err
+	}
+
+	if !storage.FileExists(settingsPath) {
+		return fmt.Errorf("no settings.json found at %s", settingsPath)
+	}
+
+	if err := validation.ValidateClaudeSettingsFile(settingsPath); err != nil {
+		return fmt.Errorf("current settings are invalid: %w", err)
+	}
+
+	output.Printf("📸 Capturing current settings from %s\n", settingsPath)
+
+	if format == config.FormatJSON {
+		return saveNewConfig(cmd, manager, settingsPath, format, "")
+	}
 
-	// Validate the edited file
-	if err := storage.IsValidJSON(tempFile); err != nil {
-		fmt.Fprintf(os.Stderr, "❌ Invalid JSON in edited file: %v\n", err)
-		fmt.Print("Do you want to edit again? (y/N): ")
-		reader := bufio.NewReader(os.Stdin)
-		response, _ := reader.ReadString('\n')
-		if strings.ToLower(strings.TrimSpace(response)) == "y" {
-			return runAdd(cmd, args) // Recursively try again
+	// Other formats are stored from a converted copy
+	tempFile, err := convertedTempFile(settingsPath, format)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tempFile)
+
+	return saveNewConfig(cmd, manager, tempFile, format, "")
+}
+
+// addFromFile stores an existing settings file as-is, without opening the
+// editor. Without --format, .jsonc and .json5 files are read tolerantly and
+// stored as strict JSON.
+func addFromFile(cmd *cobra.Command, manager *config.Manager, file, format string) error {
+	sourceFormat := ""
+	if !cmd.Flags().Changed("format") && config.SourceFormat(file) == config.FormatJSONC {
+		strictFile, err := strictTempFile(file)
+		if err != nil {
+			return fmt.Errorf("invalid configuration file %s: %w", file, err)
 		}
-		return fmt.Errorf("configuration creation cancelled due to invalid JSON")
+		defer os.Remove(strictFile)
+
+		output.Printf("📝 Reading %s as JSONC; comments and trailing commas are not stored\n", file)
+		file, sourceFormat = strictFile, config.FormatJSONC
+	}
+
+	if err := validateSettingsFile(file, format); err != nil {
+		return fmt.Errorf("invalid configuration file %s: %w", file, err)
+	}
+
+	return saveNewConfig(cmd, manager, file, format, sourceFormat)
+}
+
+// strictTempFile writes the JSONC file at path as formatted strict JSON to a
+// temporary file
+func strictTempFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read file: %w", err)
+	}
+
+	strict, err := validation.Repair(data)
+	if err != nil {
+		return "", err
+	}
+
+	tempFile := filepath.Join(os.TempDir(), fmt.Sprintf("claude-settings-%d-strict.json", os.Getpid()))
+	if err := os.WriteFile(tempFile, strict, 0644); err != nil {
+		return "", fmt.Errorf("failed to create temporary config file: %w", err)
+	}
+
+	return tempFile, nil
+}
+
+// validateSettingsFile validates a settings file written in format
+func validateSettingsFile(path, format string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read file: %w", err)
+	}
+
+	settings, err := config.ToJSON(data, format)
+	if err != nil {
+		return err
+	}
+
+	return validation.ValidateClaudeSettings(settings)
+}
+
+// trimSettings removes null and empty values from settings written in format
+// and checks that the result is still valid
+func trimSettings(data []byte, format string) ([]byte, error) {
+	settings, err := config.ToJSON(data, format)
+	if err != nil {
+		return nil, err
+	}
+
+	obj, err := jsonutil.ParseObject(settings)
+	if err != nil {
+		return nil, err
+	}
+
+	trimmed, removed := jsonutil.Trim(obj)
+	settings, err = jsonutil.MarshalIndent(trimmed)
+	if err != nil {
+		return nil, err
+	}
+	if err := validation.ValidateClaudeSettings(settings); err != nil {
+		return nil, fmt.Errorf("trimmed configuration is invalid: %w", err)
+	}
+
+	if removed > 0 {
+		output.Printf("✂️  Trimmed %d empty field%s\n", removed, pluralize(removed))
+	}
+	return config.FromJSON(settings, format)
+}
+
+// trimmedTempFile writes a trimmed copy of the settings at path to a temporary file
+func trimmedTempFile(path, format string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read settings: %w", err)
 	}
 
+	trimmed, err := trimSettings(data, format)
+	if err != nil {
+		return "", err
+	}
+
+	tempFile := filepath.Join(os.TempDir(), fmt.Sprintf("claude-settings-trimmed-%d.%s", os.Getpid(), format))
+	if err := os.WriteFile(tempFile, trimmed, 0644); err != nil {
+		return "", fmt.Errorf("failed to create temporary config file: %w", err)
+	}
+
+	return tempFile, nil
+}
+
+// convertedTempFile writes the JSON settings at path to a temporary file in format
+func convertedTempFile(path, format string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read settings: %w", err)
+	}
+
+	converted, err := config.FromJSON(data, format)
+	if err != nil {
+		return "", err
+	}
+
+	tempFile := filepath.Join(os.TempDir(), fmt.Sprintf("claude-settings-%d.%s", os.Getpid(), format))
+	if err := os.WriteFile(tempFile, converted, 0644); err != nil {
+		return "", fmt.Errorf("failed to create temporary config file: %w", err)
+	}
+
+	return tempFile, nil
+}
+
+// saveNewConfig prompts for any missing name and description and stores sourceFile
+// as a new configuration, recording sourceFormat when it was converted from one
+func saveNewConfig(cmd *cobra.Command, manager *config.Manager, sourceFile, format, sourceFormat string) error {
+	var err error
+
 	// Get configuration details
 	name, _ := cmd.Flags().GetString("name")
 	description, _ := cmd.Flags().GetString("description")
+	if descriptionFile, _ := cmd.Flags().GetString("describe-from-file"); descriptionFile != "" {
+		data, err := os.ReadFile(descriptionFile)
+		if err != nil {
+			return fmt.Errorf("failed to read description file: %w", err)
+		}
+		if description = strings.TrimSpace(string(data)); description == "" {
+			return fmt.Errorf("description file %s is empty", descriptionFile)
+		}
+	}
 
 	if name == "" {
 		name, err = promptForInput("Enter configuration name: ")
@@ -101,7 +500,9 @@ func runAdd(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	if description == "" {
+	// --validate-only never prompts; a missing description stays empty
+	validateOnly, _ := cmd.Flags().GetBool("validate-only")
+	if description == "" && !validateOnly {
 		description, _ = promptForInput("Enter description (optional): ")
 	}
 
@@ -110,29 +511,80 @@ func runAdd(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("configuration name cannot be empty")
 	}
 
+	warnDuplicateKeys(sourceFile, format)
+
+	if trim, _ := cmd.Flags().GetBool("trim"); trim {
+		trimmedFile, err := trimmedTempFile(sourceFile, format)
+		if err != nil {
+			return err
+		}
+		defer os.Remove(trimmedFile)
+		sourceFile = trimmedFile
+	}
+
+	warnSameContent(manager, sourceFile, format)
+
 	// Add configuration
-	cfg, err := manager.AddConfig(tempFile, strings.TrimSpace(name), strings.TrimSpace(description))
+	autoName, _ := cmd.Flags().GetBool("auto-name")
+	claudeVersion, _ := cmd.Flags().GetString("claude-version")
+	if claudeVersion == "" {
+		claudeVersion = manager.DetectClaudeVersion()
+	}
+
+	name = strings.TrimSpace(name)
+	cfg, err := manager.AddConfigWithOptions(sourceFile, name, strings.TrimSpace(description), config.AddOptions{
+		AutoName:      autoName,
+		ClaudeVersion: claudeVersion,
+		Format:        format,
+		SourceFormat:  sourceFormat,
+	})
 	if err != nil {
 		return fmt.Errorf("failed to add configuration: %w", err)
 	}
 
 	// Success message
-	fmt.Println()
-	fmt.Printf("✅ Configuration added successfully!\n")
-	fmt.Printf("   ID: %s\n", cfg.ID)
-	fmt.Printf("   Name: %s\n", cfg.Name)
+	output.Println()
+	output.Printf("✅ Configuration added successfully!\n")
+	if cfg.Name != name {
+		output.Printf("   Name '%s' was taken, saved as '%s'\n", name, cfg.Name)
+	}
+	output.Printf("   ID: %s\n", cfg.ID)
+	output.Printf("   Name: %s\n", cfg.Name)
 	if cfg.Description != "" {
-		fmt.Printf("   Description: %s\n", cfg.Description)
+		printDescription(cfg.Description)
+	}
+	output.Printf("   Created: %s\n", cfg.CreatedAt.Format("2006-01-02 15:04:05"))
+	if cfg.ClaudeVersion != "" {
+		output.Printf("   Claude Code: %s\n", cfg.ClaudeVersion)
 	}
-	fmt.Printf("   Created: %s\n", cfg.CreatedAt.Format("2006-01-02 15:04:05"))
-	fmt.Println()
-	fmt.Printf("💡 Use 'claude-switch apply %s' to switch to this configuration\n", cfg.Name)
+	output.Println()
+	output.Printf("💡 Use 'claude-switch apply %s' to switch to this configuration\n", cfg.Name)
+
+	commitStore(cmd, manager, fmt.Sprintf("Add config '%s'", cfg.Name))
 
 	return nil
 }
 
-// createTempConfigFile creates a temporary file with current settings.json content
-func createTempConfigFile(manager *config.Manager) (string, error) {
+// warnSameContent warns when the settings at path, written in format, match an
+// existing configuration apart from key order and formatting
+func warnSameContent(manager *config.Manager, path, format string) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return
+	}
+	settings, err := config.ToJSON(data, format)
+	if err != nil {
+		return
+	}
+
+	if existing := manager.FindByContent(settings); existing != nil {
+		output.Printf("⚠️  Same contents as the existing configuration '%s'\n", existing.Name)
+	}
+}
+
+// createTempConfigFile creates a temporary file with current settings.json content,
+// converted to format
+func createTempConfigFile(manager *config.Manager, format string) (string, error) {
 	// Get current settings path
 	settingsPath, err := manager.GetClaudeSettingsPath()
 	if err != nil {
@@ -163,12 +615,17 @@ func createTempConfigFile(manager *config.Manager) (string, error) {
 		}
 	}
 
-	return tempFile, nil
+	if format == config.FormatJSON {
+		return tempFile, nil
+	}
+
+	defer os.Remove(tempFile)
+	return convertedTempFile(tempFile, format)
 }
 
 // promptForInput prompts the user for input
 func promptForInput(prompt string) (string, error) {
-	fmt.Print(prompt)
+	output.Fprint(os.Stderr, prompt)
 	reader := bufio.NewReader(os.Stdin)
 	input, err := reader.ReadString('\n')
 	if err != nil {
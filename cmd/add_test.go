@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/Xanonymous-GitHub/claude-switch/internal/config"
+	"github.com/Xanonymous-GitHub/claude-switch/internal/editor"
+	"github.com/spf13/cobra"
+)
+
+func TestOpenEditorCancel(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake editor scripts need a POSIX shell")
+	}
+	script := filepath.Join(t.TempDir(), "editor")
+	if err := os.WriteFile(script, []byte("#!/bin/sh\nexit 1\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("EDITOR", script)
+
+	cmd := &cobra.Command{}
+	err := openEditor(cmd, filepath.Join(t.TempDir(), "settings.json"))
+	if !errors.Is(err, editor.ErrCancelled) {
+		t.Fatalf("openEditor error = %v, want ErrCancelled", err)
+	}
+	if strings.Contains(err.Error(), "editor failed") {
+		t.Errorf("openEditor error = %q, want a cancel rather than a failure", err)
+	}
+	if !cmd.SilenceUsage {
+		t.Error("a cancelled edit prints usage")
+	}
+	if code := errorCode(err); code != "editing_cancelled" {
+		t.Errorf("errorCode = %s, want editing_cancelled", code)
+	}
+}
+
+func TestOpenEditorMissing(t *testing.T) {
+	t.Setenv("EDITOR", filepath.Join(t.TempDir(), "missing-editor"))
+
+	err := openEditor(&cobra.Command{}, filepath.Join(t.TempDir(), "settings.json"))
+	if err == nil || errors.Is(err, editor.ErrCancelled) {
+		t.Fatalf("openEditor error = %v, want an editor failure", err)
+	}
+	if !strings.Contains(err.Error(), "editor failed") {
+		t.Errorf("openEditor error = %q, want it to report the editor failure", err)
+	}
+}
+
+func TestSetMaxConfigSize(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	manager, err := config.NewManager()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.SetPreference(config.PrefConfigMaxSize, "10"); err != nil {
+		t.Fatal(err)
+	}
+	data := []byte(`{"model": "opus"}`)
+
+	tests := []struct {
+		name     string
+		args     []string
+		tooLarge bool
+	}{
+		{"preference", nil, true},
+		{"flag raises the limit", []string{"--max-size", "100"}, false},
+		{"flag removes the limit", []string{"--max-size", "0"}, false},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := &cobra.Command{}
+			addMaxSizeFlag(cmd)
+			if err := cmd.ParseFlags(tt.args); err != nil {
+				t.Fatal(err)
+			}
+			setMaxConfigSize(cmd, manager)
+
+			_, err := manager.ImportConfig(fmt.Sprintf("work-%d", i), "", data)
+			if tt.tooLarge != errors.Is(err, config.ErrConfigTooLarge) {
+				t.Errorf("ImportConfig error = %v, want too large: %v", err, tt.tooLarge)
+			}
+			if !tt.tooLarge && err != nil {
+				t.Errorf("ImportConfig: %v", err)
+			}
+		})
+	}
+}
@@ -2,11 +2,19 @@ package cmd
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/Xanonymous-GitHub/claude-switch/internal/config"
+	"github.com/Xanonymous-GitHub/claude-switch/internal/diff"
+	"github.com/Xanonymous-GitHub/claude-switch/internal/jsonutil"
+	"github.com/Xanonymous-GitHub/claude-switch/internal/output"
+	"github.com/Xanonymous-GitHub/claude-switch/internal/process"
 	"github.com/Xanonymous-GitHub/claude-switch/internal/storage"
 	"github.com/Xanonymous-GitHub/claude-switch/internal/validation"
 	"github.com/spf13/cobra"
@@ -19,107 +27,604 @@ var applyCmd = &cobra.Command{
 
 This command will:
 1. Create a backup of your current ~/.claude/settings.json
-2. Replace it with the specified configuration
+2. Replace it with the specified configuration (or deep-merge it with --merge)
 3. Provide rollback information in case of issues
 
-The backup is saved as ~/.claude/settings.json.backup and can be
-restored manually if needed.`,
+The new settings are written atomically and read back. If the written file
+does not hold exactly the expected, valid settings, apply rolls back to the
+backup (or removes a file that did not exist before) and fails.
+
+With --merge, objects are merged by key and the configuration wins on
+conflicts; scalars are replaced. --merge-strategy sets how an array in the
+configuration combines with an array at the same key, at any depth:
+replace (the default) uses the configuration's array, concat appends its
+items to the current ones, and union does the same but drops items equal
+to an earlier one. --only-keys and --drop-keys restrict which top-level
+keys of the configuration are merged.
+
+--interactive asks, for each key where the merge would replace a different
+current value, whether to keep the current value, take the configuration's
+or enter a new one, then shows the merged settings before writing them.
+Other keys merge automatically. Without a terminal, or with --yes, every
+conflict takes the configuration's value as usual.
+
+--settings-key replaces a single key of the current settings (a dotted path
+such as "permissions" or "env.API_URL") with the configuration's value at
+that key, leaving everything else in settings.json untouched.
+
+If ~/.claude/settings.json is a symlink (e.g. into a dotfiles repository),
+the configuration is written through the link to its target. Use
+--replace-symlink to replace the link with a regular file instead. Either
+way the backup records the link so 'restore' can recreate it.
+
+Hooks given with --hook-pre and --hook-post run through the system shell
+with CLAUDE_SWITCH_CONFIG, CLAUDE_SWITCH_CONFIG_ID and CLAUDE_SWITCH_SETTINGS
+set. A failing pre-apply hook aborts the apply; a failing post-apply hook
+only warns unless --hook-rollback is given.
+
+The backup is saved as ~/.claude/backups/settings-<timestamp>.json (or
+.json.gz with --backup-compress or the backup.compress preference) and
+can be restored with 'claude-switch restore'. Old backups are removed
+with 'backup prune'.
+
+--backup-max-bytes (or the backup.maxBytes preference) limits the size of
+the settings file that is backed up. A larger file aborts the apply, or
+with --backup-oversize skip is replaced without a backup. There is no
+limit by default.
+
+--post-message (or the apply.postMessage preference) prints a custom note
+after a successful apply, such as a link to a team runbook. "\n" starts a
+new line, and {config}, {id}, {settings} and {backup} are replaced with the
+applied configuration's name and ID, the settings file written and the
+backup path.
+
+--force-validate re-runs the checks of 'validate' on the stored
+configuration before anything is written, including the JSON Schema set
+by the validate.schemaUrl preference, rather than trusting that it passed
+when it was added. Problems, such as ones flagged by a newer schema, are
+warnings; --abort-on-issues (which implies --force-validate) makes them
+abort the apply.
+
+The apply.deniedKeys preference lists keys, such as
+dangerouslySkipPermissions, that a configuration may not set at any depth.
+apply refuses such configurations and reports where the keys are;
+--allow-unsafe applies them anyway with a warning.
+
+--confirm prompts before applying. Set the apply.confirmDefault preference
+to prompt by default; --no-confirm then skips the prompt for one apply.
+--force always skips it.
+
+--check-claude-running looks for a running Claude Code process, which may
+overwrite or ignore the new settings until it restarts, and asks before
+applying (or only warns with --force or without a terminal). Detection is
+best-effort and is skipped where processes cannot be listed.
+
+When run interactively without --force, apply warns and asks before
+overwriting a settings.json whose contents match no saved configuration,
+so hand edits are not lost silently.
+
+Configurations may contain Go template placeholders such as
+{{.API_URL}}. They are rendered only when --env-file, --var or
+--allow-missing is given; otherwise the configuration is applied as
+stored, so a setting that contains {{ for another tool is left alone.
+A placeholder without a value is an error unless --allow-missing is
+given. Values are escaped for the JSON string they are placed in. The
+stored file keeps its placeholders and only the applied settings are
+rendered.
+
+--targets applies the configuration to <dir>/.claude/settings.json in each
+of the given project directories at once, backing up each file. A failing
+target does not stop the others; the command reports every target and
+fails at the end if any of them failed.
+
+--if-changed does nothing, successfully, when the live settings already
+match what would be written (ignoring key order and formatting), so
+repeated provisioning runs are idempotent. With --verbose it prints a
+"no change" line.
+
+--settings-file applies to an arbitrary file instead of
+~/.claude/settings.json, such as a staging copy. The file is backed up and
+written like settings.json, and ~/.claude does not need to exist. Otherwise
+apply requires ~/.claude unless --no-prereq-check is given, in which case
+it is created.
+
+--track records the configuration as the source of ~/.claude/settings.json,
+so later hand edits can be written back into it with 'claude-switch capture'.
+Any apply without --track clears the record. --track cannot be combined
+with options that write more or less than the whole configuration.
+
+--snapshot-name saves the settings about to be replaced as a new
+configuration with the given name, as 'add --from-current' does, before
+applying: a named restore point alongside the backup. A name that is
+invalid or taken fails the apply before anything changes.
+
+--backup-git (or the backup.git preference) also commits the settings
+about to be replaced to the git repository holding them, such as a
+~/.claude kept under git, so every earlier state stays in its history.
+Only settings.json is committed. Outside a work tree, or without git
+installed, the step is skipped.
+
+--then-open starts Claude Code in the foreground once the apply succeeds,
+with the claude.launchCmd preference or the claude executable on PATH,
+and falls back to the restart hint when neither is found. A running
+Claude Code keeps the old settings, so apply warns about it; add
+--kill-running to stop it before relaunching.
+
+--print-diff-after prints, once the apply succeeds, the key-path changes
+from the previous settings (read back from the backup) to the new ones.
+
+--stdout prints the settings that would be written, after templating,
+merging and key selection, and changes nothing.
+
+Without an argument (or with --default), the configuration marked with
+'claude-switch default set' is applied.`,
 	Example: `  # Apply configuration by name
   claude-switch apply my-work-setup
 
   # Apply configuration by ID
   claude-switch apply a1b2c3d4-e5f6-7890-abcd-ef1234567890
 
+  # Apply the default configuration
+  claude-switch apply
+
+  # Apply an earlier revision
+  claude-switch apply my-config --revision 2
+
+  # Render template placeholders for an environment
+  claude-switch apply my-config --env-file staging.env --var WORKSPACE=/src/app
+
   # Apply with confirmation prompt
-  claude-switch apply my-config --confirm`,
-	Args: cobra.ExactArgs(1),
+  claude-switch apply my-config --confirm
+
+  # Always confirm unless --no-confirm or --force is given
+  claude-switch config set apply.confirmDefault true
+
+  # Merge only the MCP servers into the current settings
+  claude-switch apply my-config --merge --only-keys mcpServers
+
+  # Swap only the permissions block
+  claude-switch apply my-config --settings-key permissions
+
+  # Restart a local MCP server around the switch
+  claude-switch apply my-config --hook-pre "mcp-server stop" --hook-post "mcp-server start"
+
+  # Apply and keep the saved configuration in step with later edits
+  claude-switch apply my-config --track
+  claude-switch capture
+
+  # Apply to several project roots at once
+  claude-switch apply my-config --targets ~/src/app,~/src/api
+
+  # Apply to a staging copy instead of ~/.claude/settings.json
+  claude-switch apply my-config --settings-file ./staging/settings.json
+
+  # Preview the exact merged settings without touching ~/.claude
+  claude-switch apply my-config --merge --only-keys mcpServers --stdout
+
+  # Apply silently and capture the settings path
+  settings=$(claude-switch apply my-config --print-path --quiet)`,
+	Args: cobra.MaximumNArgs(1),
 	RunE: runApply,
 }
 
 func init() {
 	applyCmd.Flags().BoolP("confirm", "c", false, "Prompt for confirmation before applying")
+	applyCmd.Flags().Bool("no-confirm", false, "Do not prompt for confirmation, overriding the apply.confirmDefault preference")
+	applyCmd.MarkFlagsMutuallyExclusive("confirm", "no-confirm")
 	applyCmd.Flags().BoolP("force", "f", false, "Force apply without backup confirmation")
 	applyCmd.Flags().BoolP("dry-run", "n", false, "Show what would be done without making changes")
+	applyCmd.Flags().Bool("replace-symlink", false, "Replace a symlinked settings.json with a regular file instead of writing through it")
+	applyCmd.Flags().BoolP("merge", "m", false, "Deep-merge the configuration into the current settings instead of replacing them")
+	applyCmd.Flags().StringSlice("only-keys", nil, "With --merge, apply only these top-level keys")
+	applyCmd.Flags().StringSlice("drop-keys", nil, "With --merge, skip these top-level keys")
+	applyCmd.Flags().String("settings-key", "", "Replace only this key (dotted path) of the current settings with the configuration's value")
+	applyCmd.MarkFlagsMutuallyExclusive("settings-key", "merge")
+	applyCmd.Flags().String("merge-strategy", jsonutil.ArraysReplace, "How --merge combines arrays: replace, concat, or union (concat without duplicates)")
+	applyCmd.Flags().Bool("interactive", false, "With --merge, choose the value for each key the configuration would change")
+	applyCmd.Flags().BoolP("yes", "y", false, "With --merge --interactive, take the configuration's value for every conflict without asking")
+	applyCmd.Flags().String("hook-pre", "", "Command to run before applying; a non-zero exit aborts the apply")
+	applyCmd.Flags().String("hook-post", "", "Command to run after applying; a non-zero exit only warns")
+	applyCmd.Flags().Bool("hook-rollback", false, "Roll back the apply when the post-apply hook fails")
+	applyCmd.Flags().Bool("print-diff-after", false, "After applying, print the changes from the previous settings")
+	applyCmd.Flags().Bool("print-path", false, "Print the settings.json path after applying, before --then-open launches Claude Code")
+	applyCmd.Flags().String("env-file", "", "File of KEY=VALUE pairs for template placeholders")
+	applyCmd.Flags().StringArray("var", nil, "Template variable as key=value (repeatable, overrides --env-file)")
+	applyCmd.Flags().Bool("allow-missing", false, "Render template placeholders without a value as empty instead of failing")
+	applyCmd.Flags().StringSlice("targets", nil, "Apply to <dir>/.claude/settings.json in each of these directories instead of ~/.claude")
+	applyCmd.Flags().Bool("if-changed", false, "Do nothing when the settings already match the configuration")
+	applyCmd.MarkFlagsMutuallyExclusive("if-changed", "targets")
+	applyCmd.Flags().String("settings-file", "", "Apply to this file instead of ~/.claude/settings.json")
+	applyCmd.MarkFlagsMutuallyExclusive("settings-file", "targets")
+	applyCmd.MarkFlagsMutuallyExclusive("interactive", "targets")
+	applyCmd.Flags().Bool("track", false, "Record this configuration as the source of settings.json for 'capture'")
+	applyCmd.Flags().Bool("stdout", false, "Print the settings that would be written to stdout instead of applying them")
+	applyCmd.Flags().Bool("check-claude-running", false, "Warn and ask before applying while Claude Code is running")
+	applyCmd.Flags().Int("revision", 0, "Apply this stored revision instead of the current contents (see 'revisions')")
+	applyCmd.Flags().Bool("default", false, "Apply the default configuration")
+	applyCmd.Flags().Bool("backup-compress", false, "Store the backup of the current settings gzip-compressed (default: backup.compress preference)")
+	applyCmd.Flags().Int64("backup-max-bytes", 0, "Largest settings file to back up, in bytes (default: backup.maxBytes preference; 0 for no limit)")
+	applyCmd.Flags().String("backup-oversize", "abort", "When settings exceed the backup limit: abort, or skip the backup")
+	applyCmd.Flags().Bool("allow-unsafe", false, "Apply even if the configuration sets keys denied by the apply.deniedKeys preference")
+	applyCmd.Flags().Bool("force-validate", false, "Re-run 'validate' checks on the configuration and warn before applying")
+	applyCmd.Flags().Bool("abort-on-issues", false, "Re-run 'validate' checks like --force-validate and abort the apply on any issue")
+	applyCmd.Flags().String("post-message", "", "Message to print after a successful apply (default: apply.postMessage preference)")
+	addPrereqCheckFlag(applyCmd)
+	applyCmd.Flags().String("snapshot-name", "", "Save the current settings as a new configuration with this name before applying")
+	applyCmd.MarkFlagsMutuallyExclusive("snapshot-name", "stdout")
+	applyCmd.MarkFlagsMutuallyExclusive("snapshot-name", "targets")
+	applyCmd.Flags().Bool("backup-git", false, "Also commit the current settings.json to its git repository before applying (or set backup.git)")
+	applyCmd.MarkFlagsMutuallyExclusive("backup-git", "stdout")
+	applyCmd.MarkFlagsMutuallyExclusive("backup-git", "targets")
+	applyCmd.Flags().Bool("then-open", false, "Launch Claude Code after a successful apply (command from the claude.launchCmd preference)")
+	applyCmd.Flags().Bool("kill-running", false, "With --then-open, stop a running Claude Code before launching it")
+	applyCmd.MarkFlagsMutuallyExclusive("then-open", "stdout")
+	applyCmd.MarkFlagsMutuallyExclusive("then-open", "targets")
+
+	// Tracking needs the whole configuration written to ~/.claude/settings.json
+	for _, flag := range []string{"merge", "settings-key", "targets", "settings-file", "revision", "if-changed"} {
+		applyCmd.MarkFlagsMutuallyExclusive("track", flag)
+	}
 }
 
 func runApply(cmd *cobra.Command, args []string) error {
-	identifier := args[0]
+	useDefault, _ := cmd.Flags().GetBool("default")
+	if useDefault && len(args) > 0 {
+		return fmt.Errorf("--default cannot be combined with a configuration name")
+	}
 
-	// Check prerequisites
-	if err := checkPrerequisites(); err != nil {
-		return err
+	// An explicit settings file or target directories may live anywhere, so
+	// ~/.claude is not required
+	settingsFile, _ := cmd.Flags().GetString("settings-file")
+	targetDirs, _ := cmd.Flags().GetStringSlice("targets")
+	if settingsFile == "" && len(targetDirs) == 0 {
+		if err := checkPrerequisitesUnlessSkipped(cmd); err != nil {
+			return err
+		}
 	}
 
 	// Create config manager
-	manager, err := config.NewManager()
+	manager, err := newManager(cmd)
 	if err != nil {
 		return fmt.Errorf("failed to initialize config manager: %w", err)
 	}
 
-	// Get the configuration
-	cfg, err := manager.GetConfig(identifier)
-	if err != nil {
-		return fmt.Errorf("configuration not found: %w", err)
+	// Get the configuration, falling back to the default
+	var cfg *config.Config
+	if len(args) == 0 {
+		cfg, err = manager.DefaultConfig()
+		if err != nil {
+			return fmt.Errorf("no configuration given: %w (use 'claude-switch default set <name>')", err)
+		}
+	} else {
+		cfg, err = manager.GetConfig(args[0])
+		if err != nil {
+			return fmt.Errorf("configuration not found: %w", err)
+		}
+	}
+
+	// Fail before any change when the snapshot could not be saved
+	snapshotName, _ := cmd.Flags().GetString("snapshot-name")
+	snapshotName = strings.TrimSpace(snapshotName)
+	if cmd.Flags().Changed("snapshot-name") {
+		if err := manager.CheckNewName(snapshotName); err != nil {
+			return fmt.Errorf("cannot save the snapshot: %w", err)
+		}
 	}
 
 	// Get flags
-	confirm, _ := cmd.Flags().GetBool("confirm")
+	// --confirm and --no-confirm override the apply.confirmDefault preference;
+	// --force skips the prompt either way
+	confirm := manager.BoolPreference(config.PrefApplyConfirm)
+	if cmd.Flags().Changed("confirm") {
+		confirm, _ = cmd.Flags().GetBool("confirm")
+	}
+	if noConfirm, _ := cmd.Flags().GetBool("no-confirm"); noConfirm {
+		confirm = false
+	}
 	force, _ := cmd.Flags().GetBool("force")
 	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	compressBackup := manager.BoolPreference(config.PrefBackupCompress)
+	if cmd.Flags().Changed("backup-compress") {
+		compressBackup, _ = cmd.Flags().GetBool("backup-compress")
+	}
+	printPath, _ := cmd.Flags().GetBool("print-path")
+	merge, _ := cmd.Flags().GetBool("merge")
+	onlyKeys, _ := cmd.Flags().GetStringSlice("only-keys")
+	dropKeys, _ := cmd.Flags().GetStringSlice("drop-keys")
+	hookPre, _ := cmd.Flags().GetString("hook-pre")
+	hookPost, _ := cmd.Flags().GetString("hook-post")
+	hookRollback, _ := cmd.Flags().GetBool("hook-rollback")
+	replaceSymlink, _ := cmd.Flags().GetBool("replace-symlink")
+	revision, _ := cmd.Flags().GetInt("revision")
+	envFile, _ := cmd.Flags().GetString("env-file")
+	varArgs, _ := cmd.Flags().GetStringArray("var")
+	allowMissing, _ := cmd.Flags().GetBool("allow-missing")
+	settingsKey, _ := cmd.Flags().GetString("settings-key")
+	track, _ := cmd.Flags().GetBool("track")
 
-	// Get paths
-	settingsPath, err := manager.GetClaudeSettingsPath()
-	if err != nil {
+	thenOpen, _ := cmd.Flags().GetBool("then-open")
+	killRunning, _ := cmd.Flags().GetBool("kill-running")
+	if killRunning && !thenOpen {
+		return fmt.Errorf("--kill-running requires --then-open")
+	}
+	if (len(onlyKeys) > 0 || len(dropKeys) > 0) && !merge {
+		return fmt.Errorf("--only-keys and --drop-keys require --merge")
+	}
+	mergeStrategy, _ := cmd.Flags().GetString("merge-strategy")
+	if !slices.Contains(jsonutil.ArrayStrategies, mergeStrategy) {
+		return fmt.Errorf("invalid --merge-strategy '%s' (valid: %s)", mergeStrategy, strings.Join(jsonutil.ArrayStrategies, ", "))
+	}
+	if cmd.Flags().Changed("merge-strategy") && !merge {
+		return fmt.Errorf("--merge-strategy requires --merge")
+	}
+	interactive, _ := cmd.Flags().GetBool("interactive")
+	yes, _ := cmd.Flags().GetBool("yes")
+	if interactive && !merge {
+		return fmt.Errorf("--interactive requires --merge")
+	}
+	if yes && !interactive {
+		return fmt.Errorf("--yes requires --interactive")
+	}
+
+	backupMaxBytes := int64(manager.IntPreference(config.PrefBackupMaxBytes))
+	if cmd.Flags().Changed("backup-max-bytes") {
+		backupMaxBytes, _ = cmd.Flags().GetInt64("backup-max-bytes")
+	}
+	oversize, _ := cmd.Flags().GetString("backup-oversize")
+	if oversize != "abort" && oversize != "skip" {
+		return fmt.Errorf("invalid --backup-oversize '%s' (valid: abort, skip)", oversize)
+	}
+	if revision < 0 {
+		return fmt.Errorf("--revision must not be negative")
+	}
+	applyOpts := config.ApplyOptions{
+		CompressBackup: compressBackup,
+		ReplaceSymlink: replaceSymlink,
+		Merge:          merge,
+		MergeStrategy:  mergeStrategy,
+		OnlyKeys:       onlyKeys,
+		DropKeys:       dropKeys,
+		Revision:       revision,
+		AllowMissing:   allowMissing,
+		SettingsKey:    settingsKey,
+		SettingsPath:   settingsFile,
+		Track:          track,
+
+		BackupMaxBytes:     backupMaxBytes,
+		SkipOversizeBackup: oversize == "skip",
+	}
+	applyOpts.AllowUnsafe, _ = cmd.Flags().GetBool("allow-unsafe")
+
+	if applyOpts.Vars, err = templateVars(envFile, varArgs); err != nil {
 		return err
 	}
+	applyOpts.Render = envFile != "" || len(varArgs) > 0 || allowMissing
+
+	forceValidate, _ := cmd.Flags().GetBool("force-validate")
+	abortOnIssues, _ := cmd.Flags().GetBool("abort-on-issues")
+	if forceValidate || abortOnIssues {
+		if err := revalidateConfig(cmd, manager, cfg, abortOnIssues); err != nil {
+			return err
+		}
+	}
+
+	// Denied keys are a policy: they block the apply unless explicitly allowed
+	checkOpts := applyOpts
+	checkOpts.AllowUnsafe = false
+	if _, err := manager.RenderConfig(cfg.ID, checkOpts); errors.Is(err, config.ErrDeniedKey) {
+		if !applyOpts.AllowUnsafe {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("refusing to apply '%s': %w (use --allow-unsafe to override)", cfg.Name, err)
+		}
+		output.Fprintf(os.Stderr, "⚠️  %v; applying anyway because of --allow-unsafe\n", err)
+	}
+
+	// Conflicts go to the configuration without a terminal or with --yes
+	toStdout, _ := cmd.Flags().GetBool("stdout")
+	if interactive && !yes && output.Interactive() {
+		if applyOpts.MergeChoices, err = resolveMergeConflicts(manager, cfg, applyOpts); err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+
+		// --stdout prints the result anyway, and --dry-run writes nothing
+		if !toStdout && !dryRun {
+			merged, err := manager.RenderConfig(cfg.ID, applyOpts)
+			if err != nil {
+				return fmt.Errorf("failed to render configuration: %w", err)
+			}
+			output.Println("📄 Merged settings to write:")
+			output.Println(string(merged))
+
+			response, err := promptForInput("Write these settings? (y/N): ")
+			if err != nil {
+				return fmt.Errorf("failed to read confirmation: %w", err)
+			}
+			if strings.ToLower(response) != "y" {
+				output.Println("❌ Operation cancelled")
+				return nil
+			}
+		}
+	}
+
+	// --stdout ends the pipeline at the rendered settings: no prompts,
+	// hooks, backup or writes
+	if toStdout {
+		data, err := manager.RenderConfig(cfg.ID, applyOpts)
+		if err != nil {
+			return fmt.Errorf("failed to render configuration: %w", err)
+		}
+		return printSettings(data, config.FormatJSON)
+	}
+
+	if targets, _ := cmd.Flags().GetStringSlice("targets"); len(targets) > 0 {
+		if hookPre != "" || hookPost != "" {
+			return fmt.Errorf("--targets cannot be combined with hooks")
+		}
+		// Per-target failures are already reported; usage would bury them
+		cmd.SilenceUsage = true
+		return applyToTargets(manager, cfg, targets, applyOpts, confirm && !force, dryRun)
+	}
+
+	// Idempotent provisioning: nothing to do when the result is already live
+	if ifChanged, _ := cmd.Flags().GetBool("if-changed"); ifChanged {
+		data, err := manager.RenderConfig(cfg.ID, applyOpts)
+		if err != nil {
+			return fmt.Errorf("failed to render configuration: %w", err)
+		}
+		unchanged, err := manager.SettingsEqual(data, applyOpts)
+		if err != nil {
+			return err
+		}
+		if unchanged {
+			if verbose, _ := cmd.Flags().GetBool("verbose"); verbose {
+				output.Printf("✅ Settings already match '%s'; no change\n", cfg.Name)
+			}
+			return nil
+		}
+	}
+
+	// The file to apply: the current contents or a stored revision
+	sourcePath := cfg.FilePath
+	if revision > 0 {
+		_, rev, err := manager.Revision(cfg.ID, revision)
+		if err != nil {
+			return err
+		}
+		sourcePath = rev.Path
+	}
+
+	// Get paths
+	settingsPath := settingsFile
+	if settingsPath == "" {
+		if settingsPath, err = manager.GetClaudeSettingsPath(); err != nil {
+			return err
+		}
+	}
+	// The backup file is named when it is written; only its directory is known now
+	backupDir := config.BackupDir(settingsPath)
 
 	// Check if settings.json exists
 	currentExists := storage.FileExists(settingsPath)
 
 	// Show what will happen
-	fmt.Printf("🎯 Applying configuration: %s\n", cfg.Name)
-	fmt.Printf("   ID: %s\n", cfg.ID)
+	output.Printf("🎯 Applying configuration: %s\n", cfg.Name)
+	output.Printf("   ID: %s\n", cfg.ID)
 	if cfg.Description != "" {
-		fmt.Printf("   Description: %s\n", cfg.Description)
+		printDescription(cfg.Description)
+	}
+	if revision > 0 {
+		output.Printf("   Revision: %d\n", revision)
 	}
-	fmt.Printf("   Target: %s\n", settingsPath)
+	output.Printf("   Target: %s\n", settingsPath)
 
+	// A settings file over the backup limit is either refused or not backed up
+	backupTooLarge := false
 	if currentExists {
-		fmt.Printf("   Backup: %s.backup\n", settingsPath)
+		info, err := os.Stat(settingsPath)
+		backupTooLarge = err == nil && backupMaxBytes > 0 && info.Size() > backupMaxBytes
+		switch {
+		case !backupTooLarge:
+			output.Printf("   Backup: new file in %s\n", backupDir)
+		case applyOpts.SkipOversizeBackup:
+			output.Printf("   Backup: skipped, settings exceed the limit of %s\n", storage.FormatSize(backupMaxBytes))
+		default:
+			output.Printf("   Backup: refused, settings exceed the limit of %s\n", storage.FormatSize(backupMaxBytes))
+		}
 
 		// Show current file info
-		if info, err := os.Stat(settingsPath); err == nil {
-			fmt.Printf("   Current file: %d bytes, modified %s\n",
+		if err == nil {
+			output.Printf("   Current file: %d bytes, modified %s\n",
 				info.Size(), info.ModTime().Format("2006-01-02 15:04:05"))
 		}
 	} else {
-		fmt.Printf("   Current: No existing settings.json found\n")
+		output.Printf("   Current: No existing settings.json found\n")
+	}
+
+	if target, err := os.Readlink(settingsPath); err == nil {
+		if replaceSymlink {
+			output.Printf("   Symlink: -> %s (will be replaced with a regular file)\n", target)
+		} else {
+			output.Printf("   Symlink: -> %s (writing through; use --replace-symlink to replace it)\n", target)
+		}
 	}
 
 	// Show new file info
-	if info, err := os.Stat(cfg.FilePath); err == nil {
-		fmt.Printf("   New file: %d bytes, created %s\n",
+	if info, err := os.Stat(sourcePath); err == nil {
+		output.Printf("   New file: %d bytes, created %s\n",
 			info.Size(), cfg.CreatedAt.Format("2006-01-02 15:04:05"))
 	}
 
-	fmt.Println()
+	// Warn when the config was made for a different Claude Code version
+	if cfg.ClaudeVersion != "" {
+		if installed := manager.DetectClaudeVersion(); installed != "" && installed != cfg.ClaudeVersion {
+			output.Printf("⚠️  Configuration was created for Claude Code %s, but %s is installed\n",
+				cfg.ClaudeVersion, installed)
+		}
+	}
+
+	output.Println()
 
 	// Dry run mode
 	if dryRun {
-		fmt.Println("🔍 DRY RUN MODE - No changes will be made")
-		if currentExists {
-			fmt.Printf("Would create backup: %s.backup\n", settingsPath)
+		output.Println("🔍 DRY RUN MODE - No changes will be made")
+		if currentExists && !backupTooLarge {
+			output.Printf("Would create a backup in %s\n", backupDir)
+		}
+		if snapshotName != "" && currentExists {
+			output.Printf("Would save the current settings as '%s'\n", snapshotName)
+		}
+		if backupGit, _ := cmd.Flags().GetBool("backup-git"); currentExists && (backupGit || manager.BoolPreference(config.PrefBackupGit)) {
+			output.Printf("Would commit the current settings to git in %s\n", filepath.Dir(settingsPath))
+		}
+		if backupTooLarge && !applyOpts.SkipOversizeBackup {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("%w: would abort (use --backup-oversize skip to apply without a backup)", config.ErrBackupTooLarge)
+		}
+		if _, err := manager.RenderConfig(cfg.ID, applyOpts); err != nil {
+			return fmt.Errorf("failed to render configuration: %w", err)
+		}
+		if settingsKey != "" {
+			output.Printf("Would replace '%s': %s -> %s\n", settingsKey, sourcePath, settingsPath)
+		} else if merge {
+			output.Printf("Would merge: %s -> %s\n", sourcePath, settingsPath)
+		} else {
+			output.Printf("Would copy: %s -> %s\n", sourcePath, settingsPath)
 		}
-		fmt.Printf("Would copy: %s -> %s\n", cfg.FilePath, settingsPath)
 		return nil
 	}
 
+	// Guard hand edits that no saved configuration holds; scripts (no TTY)
+	// and --force proceed silently. Only ~/.claude/settings.json is tracked
+	if !force && settingsFile == "" && output.Interactive() {
+		unmanaged, err := manager.UnmanagedSettings()
+		if err != nil {
+			return err
+		}
+		if unmanaged {
+			output.Fprintln(os.Stderr, "⚠️  The current settings.json has changes that are not saved in any configuration.")
+			output.Fprintln(os.Stderr, "💡 Save them first with 'claude-switch add --from-current'")
+			output.Fprint(os.Stderr, "Overwrite them anyway? (y/N): ")
+
+			reader := bufio.NewReader(os.Stdin)
+			response, err := reader.ReadString('\n')
+			if err != nil {
+				return fmt.Errorf("failed to read confirmation: %w", err)
+			}
+
+			if strings.ToLower(strings.TrimSpace(response)) != "y" {
+				output.Println("❌ Operation cancelled")
+				return nil
+			}
+		}
+	}
+
 	// Confirmation prompt
 	if confirm && !force {
 		if !currentExists {
-			fmt.Print("No existing settings.json found. Continue? (y/N): ")
+			output.Fprint(os.Stderr, "No existing settings.json found. Continue? (y/N): ")
 		} else {
-			fmt.Print("This will replace your current Claude Code settings. Continue? (y/N): ")
+			output.Fprint(os.Stderr, "This will replace your current Claude Code settings. Continue? (y/N): ")
 		}
 
 		reader := bufio.NewReader(os.Stdin)
@@ -129,33 +634,293 @@ func runApply(cmd *cobra.Command, args []string) error {
 		}
 
 		if strings.ToLower(strings.TrimSpace(response)) != "y" {
-			fmt.Println("❌ Operation cancelled")
+			output.Println("❌ Operation cancelled")
 			return nil
 		}
 	}
 
-	// Validate the configuration file before applying
-	if err := validation.ValidateClaudeSettingsFile(cfg.FilePath); err != nil {
+	if checkRunning, _ := cmd.Flags().GetBool("check-claude-running"); checkRunning {
+		proceed, err := confirmClaudeNotRunning(force)
+		if err != nil {
+			return err
+		}
+		if !proceed {
+			output.Println("❌ Operation cancelled")
+			return nil
+		}
+	}
+
+	// Validate the configuration before applying
+	if _, err := manager.RenderConfig(cfg.ID, applyOpts); err != nil {
 		return fmt.Errorf("configuration file is invalid: %w", err)
 	}
 
+	if hookPre != "" {
+		if err := runHook("pre-apply", hookPre, cfg, settingsPath); err != nil {
+			return fmt.Errorf("apply aborted: %w", err)
+		}
+	}
+
+	if snapshotName != "" {
+		if err := snapshotSettings(cmd, manager, settingsPath, snapshotName, cfg); err != nil {
+			return err
+		}
+	}
+
+	if currentExists {
+		commitSettings(cmd, manager, settingsPath, cfg)
+	}
+
 	// Apply the configuration
-	fmt.Println("🔄 Applying configuration...")
+	output.Println("🔄 Applying configuration...")
 
-	if err := manager.ApplyConfig(identifier); err != nil {
+	result, err := manager.ApplyConfigWithOptions(cfg.ID, applyOpts)
+	if err != nil {
+		// The size limit is a policy, and a rolled-back write a runtime
+		// failure, not usage mistakes
+		if errors.Is(err, config.ErrBackupTooLarge) || errors.Is(err, config.ErrWriteCheck) {
+			cmd.SilenceUsage = true
+		}
 		return fmt.Errorf("failed to apply configuration: %w", err)
 	}
 
+	if hookPost != "" {
+		if err := runHook("post-apply", hookPost, cfg, settingsPath); err != nil {
+			if !hookRollback {
+				output.Printf("⚠️  %v (configuration remains applied)\n", err)
+			} else {
+				if err := rollbackApply(manager, result); err != nil {
+					return fmt.Errorf("post-apply hook failed and rollback failed: %w", err)
+				}
+				return fmt.Errorf("configuration rolled back: %w", err)
+			}
+		}
+	}
+
 	// Success message
-	fmt.Println("✅ Configuration applied successfully!")
-	fmt.Println()
+	output.Println("✅ Configuration applied successfully!")
+	output.Println()
 
-	if currentExists {
-		fmt.Printf("💾 Backup saved: %s.backup\n", settingsPath)
-		fmt.Println("💡 To rollback: mv ~/.claude/settings.json.backup ~/.claude/settings.json")
+	if result.BackupPath != "" {
+		output.Printf("💾 Backup saved: %s\n", result.BackupPath)
+		output.Println("💡 To rollback: claude-switch restore")
+	}
+	if result.BackupSkipped {
+		output.Printf("⚠️  No backup was made: the previous settings exceeded the limit of %s\n", storage.FormatSize(backupMaxBytes))
+	}
+
+	if printDiffAfter, _ := cmd.Flags().GetBool("print-diff-after"); printDiffAfter {
+		output.Println()
+		printAppliedChanges(result)
+		output.Println()
+	}
+
+	if !thenOpen {
+		output.Println("🔄 Restart Claude Code to see the changes")
+	}
+
+	postMessage, _, _ := manager.GetPreference(config.PrefApplyPostMessage)
+	if cmd.Flags().Changed("post-message") {
+		postMessage, _ = cmd.Flags().GetString("post-message")
 	}
+	if postMessage != "" {
+		output.Println()
+		output.Println(expandPostMessage(postMessage, result))
+	}
+
+	// Printed unconditionally so it survives --quiet for use in scripts, and
+	// before --then-open hands the terminal to Claude Code
+	if printPath {
+		fmt.Println(result.SettingsPath)
+	}
+
+	if thenOpen {
+		// The apply succeeded; a failed launch is not a usage mistake
+		cmd.SilenceUsage = true
+		output.Println()
+		return launchClaude(manager, killRunning)
+	}
+
+	return nil
+}
 
-	fmt.Println("🔄 Restart Claude Code to see the changes")
+// snapshotSettings saves the settings about to be replaced as a new
+// configuration named name, as 'add --from-current' would. Missing settings
+// only warn, since there is nothing to lose.
+func snapshotSettings(cmd *cobra.Command, manager *config.Manager, settingsPath, name string, applying *config.Config) error {
+	if !storage.FileExists(settingsPath) {
+		output.Printf("⚠️  No settings at %s to snapshot; '%s' was not saved\n", settingsPath, name)
+		return nil
+	}
+	if err := validation.ValidateClaudeSettingsFile(settingsPath); err != nil {
+		cmd.SilenceUsage = true
+		return fmt.Errorf("cannot snapshot the current settings: %w (apply aborted)", err)
+	}
+
+	snapshot, err := manager.AddConfigWithOptions(settingsPath, name,
+		fmt.Sprintf("Snapshot taken before applying '%s'", applying.Name),
+		config.AddOptions{ClaudeVersion: manager.DetectClaudeVersion()})
+	if err != nil {
+		return fmt.Errorf("failed to save the snapshot: %w", err)
+	}
 
+	output.Printf("📸 Saved the current settings as '%s' (ID %s)\n", snapshot.Name, snapshot.ID[:8])
+	commitStore(cmd, manager, fmt.Sprintf("Add config '%s'", snapshot.Name))
 	return nil
 }
+
+// confirmClaudeNotRunning warns when Claude Code is running and asks whether
+// to apply anyway. It only warns with force or without a terminal, and
+// proceeds silently when processes cannot be listed.
+func confirmClaudeNotRunning(force bool) (bool, error) {
+	pids, err := process.FindClaude()
+	if err != nil || len(pids) == 0 {
+		return true, nil
+	}
+
+	ids := make([]string, len(pids))
+	for i, pid := range pids {
+		ids[i] = strconv.Itoa(pid)
+	}
+	output.Fprintf(os.Stderr, "⚠️  Claude Code appears to be running (pid %s); it may overwrite or ignore the new settings until restarted.\n", strings.Join(ids, ", "))
+
+	if force || !output.Interactive() {
+		return true, nil
+	}
+
+	output.Fprint(os.Stderr, "Apply anyway? (y/N): ")
+	reader := bufio.NewReader(os.Stdin)
+	response, err := reader.ReadString('\n')
+	if err != nil {
+		return false, fmt.Errorf("failed to read confirmation: %w", err)
+	}
+	return strings.ToLower(strings.TrimSpace(response)) == "y", nil
+}
+
+// templateVars collects template variables from an env file and key=value
+// arguments; arguments override values from the file
+func templateVars(envFile string, args []string) (map[string]string, error) {
+	vars := map[string]string{}
+	if envFile != "" {
+		loaded, err := config.LoadEnvFile(envFile)
+		if err != nil {
+			return nil, err
+		}
+		vars = loaded
+	}
+
+	for _, arg := range args {
+		key, value, ok := strings.Cut(arg, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid --var '%s', expected key=value", arg)
+		}
+		vars[key] = value
+	}
+
+	return vars, nil
+}
+
+// printAppliedChanges prints the key-path changes an apply made, comparing the
+// backup of the previous settings with the settings now written
+func printAppliedChanges(result *config.ApplyResult) {
+	if result.BackupSkipped {
+		output.Println("🔀 No backup of the previous settings to compare with")
+		return
+	}
+	if result.BackupPath == "" {
+		output.Println("🔀 Created new settings")
+		return
+	}
+
+	before, err := storage.ReadFile(result.BackupPath)
+	if err != nil {
+		output.Printf("⚠️  Cannot read the backup to compare with: %v\n", err)
+		return
+	}
+	after, err := os.ReadFile(result.SettingsPath)
+	if err != nil {
+		output.Printf("⚠️  Cannot read the new settings: %v\n", err)
+		return
+	}
+
+	old, oldErr := jsonutil.ParseObject(before)
+	applied, newErr := jsonutil.ParseObject(after)
+	if oldErr != nil || newErr != nil {
+		// Previous settings that were not valid JSON can only be compared as text
+		fmt.Print(diff.Unified(result.BackupPath, result.SettingsPath, string(before), string(after), 3))
+		return
+	}
+
+	changes := jsonutil.Diff(old, applied)
+	if len(changes) == 0 {
+		output.Println("🔀 No changes from the previous settings")
+		return
+	}
+	output.Printf("🔀 %d change%s from the previous settings:\n", len(changes), pluralize(len(changes)))
+	printChanges(changes)
+}
+
+// rollbackApply undoes an apply by restoring the backup to the file it
+// wrote, or removing that file when there was none before
+func rollbackApply(manager *config.Manager, result *config.ApplyResult) error {
+	if result.BackupSkipped {
+		return fmt.Errorf("no backup of the previous settings was made")
+	}
+	if result.BackupPath == "" {
+		if err := os.Remove(result.SettingsPath); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove settings: %w", err)
+		}
+		return nil
+	}
+
+	return manager.RestoreBackupTo(result.BackupPath, result.SettingsPath)
+}
+
+// revalidateConfig runs the checks of 'validate' on cfg before it is applied.
+// Problems are printed as warnings, or fail the apply when abort is set.
+func revalidateConfig(cmd *cobra.Command, manager *config.Manager, cfg *config.Config, abort bool) error {
+	if schemaURL, _, _ := manager.GetPreference(config.PrefValidateSchemaURL); schemaURL != "" {
+		ttl, _ := parseAge(defaultSchemaTTL)
+		useSettingsSchema(manager, schemaURL, ttl, false)
+	}
+
+	var issues []string
+	if err := manager.ValidateConfig(cfg.ID); err != nil {
+		issues = append(issues, err.Error())
+	}
+	issues = append(issues, duplicateKeyWarnings(cfg.FilePath, cfg.StoredFormat())...)
+	if _, settings, err := manager.LoadSettings(cfg.ID); err == nil {
+		for _, key := range manager.MissingRequiredKeys(settings) {
+			issues = append(issues, fmt.Sprintf("Missing required key '%s' (%s)", key, config.PrefValidateRequiredKeys))
+		}
+	}
+	if len(issues) == 0 {
+		return nil
+	}
+
+	for _, issue := range issues {
+		output.Fprintf(os.Stderr, "⚠️  %s\n", issue)
+	}
+	if abort {
+		cmd.SilenceUsage = true
+		return fmt.Errorf("configuration '%s' failed validation with %d issue(s) (--abort-on-issues)", cfg.Name, len(issues))
+	}
+	output.Fprintln(os.Stderr, "💡 Run 'claude-switch validate' for details; --abort-on-issues makes these block the apply")
+	return nil
+}
+
+// expandPostMessage turns a literal \n into a newline and fills in the placeholders
+// of a post-apply message from the apply result
+func expandPostMessage(message string, result *config.ApplyResult) string {
+	backup := result.BackupPath
+	if backup == "" {
+		backup = "(none)"
+	}
+	return strings.NewReplacer(
+		`\n`, "\n",
+		"{config}", result.Config.Name,
+		"{id}", result.Config.ID,
+		"{settings}", result.SettingsPath,
+		"{backup}", backup,
+	).Replace(message)
+}
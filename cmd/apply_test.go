@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/Xanonymous-GitHub/claude-switch/internal/config"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// setArgs sets up the next rootCmd.Execute to run command with args, and
+// resets its flags afterwards so they do not carry over to other tests
+func setArgs(t *testing.T, command *cobra.Command, args ...string) {
+	t.Helper()
+
+	rootCmd.SetArgs(append([]string{command.Name()}, args...))
+	t.Cleanup(func() {
+		rootCmd.SetArgs(nil)
+		command.Flags().VisitAll(func(flag *pflag.Flag) {
+			if slice, ok := flag.Value.(pflag.SliceValue); ok {
+				slice.Replace(nil)
+			} else {
+				flag.Value.Set(flag.DefValue)
+			}
+			flag.Changed = false
+		})
+	})
+}
+
+func TestApplyPrintPathBeforeLaunchAndRealBackup(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the launch command runs through sh")
+	}
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	settingsPath := filepath.Join(home, ".claude", "settings.json")
+	if err := os.MkdirAll(filepath.Dir(settingsPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(settingsPath, []byte(`{"model": "sonnet"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	manager, err := config.NewManager()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := manager.ImportConfig("work", "", []byte(`{"model": "opus"}`)); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.SetPreference(config.PrefClaudeLaunchCmd, "echo launched"); err != nil {
+		t.Fatal(err)
+	}
+
+	setArgs(t, applyCmd, "work", "--force", "--print-path", "--then-open")
+	out := captureStdout(t, func() {
+		if err := rootCmd.Execute(); err != nil {
+			t.Errorf("apply: %v", err)
+		}
+	})
+
+	pathAt := strings.Index(out, settingsPath+"\n")
+	launchedAt := strings.Index(out, "launched\n")
+	if pathAt < 0 || launchedAt < 0 || pathAt > launchedAt {
+		t.Errorf("settings path not printed before the launch:\n%s", out)
+	}
+
+	match := regexp.MustCompile(`Backup saved: (\S+)`).FindStringSubmatch(out)
+	if match == nil {
+		t.Fatalf("no backup reported:\n%s", out)
+	}
+	if data, err := os.ReadFile(match[1]); err != nil || string(data) != `{"model": "sonnet"}` {
+		t.Errorf("reported backup %s holds %q (%v), want the previous settings", match[1], data, err)
+	}
+}
+
+func TestApplyHookRollbackRestoresSettingsFile(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the hook runs through sh")
+	}
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	defaultPath := filepath.Join(home, ".claude", "settings.json")
+	if err := os.MkdirAll(filepath.Dir(defaultPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(defaultPath, []byte(`{"model": "haiku"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	target := filepath.Join(t.TempDir(), "settings.json")
+	if err := os.WriteFile(target, []byte(`{"model": "sonnet"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	manager, err := config.NewManager()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := manager.ImportConfig("work", "", []byte(`{"model": "opus"}`)); err != nil {
+		t.Fatal(err)
+	}
+
+	setArgs(t, applyCmd, "work", "--force", "--settings-file", target, "--hook-post", "exit 1", "--hook-rollback")
+	captureStdout(t, func() {
+		if err := rootCmd.Execute(); err == nil {
+			t.Error("apply succeeded despite the failing hook")
+		}
+	})
+
+	if data, err := os.ReadFile(target); err != nil || string(data) != `{"model": "sonnet"}` {
+		t.Errorf("%s holds %q (%v), want it rolled back", target, data, err)
+	}
+	if data, err := os.ReadFile(defaultPath); err != nil || string(data) != `{"model": "haiku"}` {
+		t.Errorf("%s holds %q (%v), want it untouched", defaultPath, data, err)
+	}
+}
+
+func TestApplyRejectsNegativeRevision(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	manager, err := config.NewManager()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := manager.ImportConfig("work", "", []byte(`{"model": "opus"}`)); err != nil {
+		t.Fatal(err)
+	}
+	settingsPath := filepath.Join(t.TempDir(), "settings.json")
+
+	setArgs(t, applyCmd, "work", "--force", "--settings-file", settingsPath, "--revision", "-3")
+	captureStdout(t, func() {
+		if err := rootCmd.Execute(); err == nil || !strings.Contains(err.Error(), "--revision") {
+			t.Errorf("apply error = %v, want a --revision error", err)
+		}
+	})
+	if _, err := os.Stat(settingsPath); !os.IsNotExist(err) {
+		t.Errorf("%s was written despite the invalid revision", settingsPath)
+	}
+}
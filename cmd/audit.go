@@ -0,0 +1,177 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/Xanonymous-GitHub/claude-switch/internal/jsonutil"
+	"github.com/Xanonymous-GitHub/claude-switch/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// errAuditFailed is returned when at least one configuration violates a requirement
+var errAuditFailed = errors.New("audit failed")
+
+var auditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Check that every configuration meets key requirements",
+	Long: `Check all stored configurations against key requirements, e.g. an
+organization policy that every configuration must disable telemetry.
+
+--require-key demands that a key is present and --require-value that it
+has a specific value. Keys may be dot-separated paths into nested
+objects. Values are parsed as JSON when possible (false, 3, "x"), and
+compared as strings otherwise. Both flags can be repeated.
+
+The command exits with status 1 if any configuration violates a
+requirement.`,
+	Example: `  # Every configuration must set telemetry to false
+  claude-switch audit --require-value telemetry=false
+
+  # Several requirements, reported as JSON
+  claude-switch audit --require-key permissions --require-key model --json`,
+	Args: cobra.NoArgs,
+	RunE: runAudit,
+}
+
+func init() {
+	auditCmd.Flags().StringArray("require-key", nil, "Key that every configuration must set (repeatable)")
+	auditCmd.Flags().StringArray("require-value", nil, "key=value every configuration must match (repeatable)")
+	auditCmd.Flags().BoolP("json", "j", false, "Output a JSON audit report")
+}
+
+// auditRequirement is a single key (and optionally value) every config must have
+type auditRequirement struct {
+	key      string
+	value    interface{}
+	hasValue bool
+}
+
+// String renders the requirement as given on the command line
+func (r auditRequirement) String() string {
+	if !r.hasValue {
+		return r.key
+	}
+	return r.key + "=" + compactJSON(r.value)
+}
+
+// auditViolation is a configuration failing a requirement
+type auditViolation struct {
+	Config      string `json:"config"`
+	ID          string `json:"id"`
+	Requirement string `json:"requirement"`
+	Problem     string `json:"problem"`
+}
+
+// auditReport is the JSON form of an audit
+type auditReport struct {
+	Passed     bool             `json:"passed"`
+	Checked    int              `json:"checked"`
+	Violations []auditViolation `json:"violations"`
+}
+
+func runAudit(cmd *cobra.Command, args []string) error {
+	requiredKeys, _ := cmd.Flags().GetStringArray("require-key")
+	requiredValues, _ := cmd.Flags().GetStringArray("require-value")
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+
+	requirements, err := parseAuditRequirements(requiredKeys, requiredValues)
+	if err != nil {
+		return err
+	}
+
+	// Create config manager
+	manager, err := newManager(cmd)
+	if err != nil {
+		return fmt.Errorf("failed to initialize config manager: %w", err)
+	}
+
+	configs := manager.GetConfigs()
+	report := auditReport{Checked: len(configs), Violations: []auditViolation{}}
+
+	for _, cfg := range configs {
+		violation := auditViolation{Config: cfg.Name, ID: cfg.ID}
+
+		data, err := manager.ConfigJSON(cfg.ID)
+		var settings map[string]interface{}
+		if err == nil {
+			settings, err = jsonutil.ParseObject(data)
+		}
+		if err != nil {
+			violation.Requirement = "valid configuration"
+			violation.Problem = err.Error()
+			report.Violations = append(report.Violations, violation)
+			continue
+		}
+
+		for _, req := range requirements {
+			value, ok := jsonutil.Lookup(settings, req.key)
+			switch {
+			case !ok:
+				violation.Problem = "missing"
+			case req.hasValue && !reflect.DeepEqual(value, req.value):
+				violation.Problem = "is " + compactJSON(value)
+			default:
+				continue
+			}
+			violation.Requirement = req.String()
+			report.Violations = append(report.Violations, violation)
+		}
+	}
+	report.Passed = len(report.Violations) == 0
+
+	if jsonOutput {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal audit report: %w", err)
+		}
+		fmt.Println(string(data))
+	} else if report.Passed {
+		output.Printf("✅ All %d configuration%s meet %d requirement%s\n",
+			report.Checked, pluralize(report.Checked), len(requirements), pluralize(len(requirements)))
+	} else {
+		output.Printf("❌ %d violation%s:\n", len(report.Violations), pluralize(len(report.Violations)))
+		for _, violation := range report.Violations {
+			output.Printf("   %s: %s %s\n", violation.Config, violation.Requirement, violation.Problem)
+		}
+	}
+
+	if !report.Passed {
+		// A failed audit is a result, not a usage mistake
+		cmd.SilenceUsage = true
+		return fmt.Errorf("%w: %d violation%s", errAuditFailed, len(report.Violations), pluralize(len(report.Violations)))
+	}
+	return nil
+}
+
+// parseAuditRequirements builds requirements from --require-key and
+// --require-value arguments
+func parseAuditRequirements(keys, values []string) ([]auditRequirement, error) {
+	var requirements []auditRequirement
+	for _, key := range keys {
+		requirements = append(requirements, auditRequirement{key: key})
+	}
+
+	for _, arg := range values {
+		key, raw, ok := strings.Cut(arg, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid --require-value '%s', expected key=value", arg)
+		}
+
+		// Values that aren't JSON are compared as plain strings
+		var value interface{}
+		if err := json.Unmarshal([]byte(raw), &value); err != nil {
+			value = raw
+		}
+		requirements = append(requirements, auditRequirement{key: key, value: value, hasValue: true})
+	}
+
+	if len(requirements) == 0 {
+		return nil, fmt.Errorf("specify at least one --require-key or --require-value")
+	}
+
+	return requirements, nil
+}
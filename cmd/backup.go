@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/Xanonymous-GitHub/claude-switch/internal/config"
+	"github.com/Xanonymous-GitHub/claude-switch/internal/output"
+	"github.com/Xanonymous-GitHub/claude-switch/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+var backupCmd = &cobra.Command{
+	Use:   "backup",
+	Short: "Manage backups of Claude Code settings",
+	Long: `Manage the backups 'apply' takes of ~/.claude/settings.json.
+
+Each apply stores a timestamped backup in ~/.claude/backups. Use
+'backup prune' to remove old ones.`,
+}
+
+var backupPruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Remove backups outside a retention policy",
+	Long: `Remove timestamped backups that are older than --older-than or beyond
+the --keep newest. A backup outside either limit is removed, together
+with its metadata sidecar.
+
+The age of a backup is taken from the timestamp in its file name, not its
+modification time. Backups from older versions (settings.json.backup) are
+never pruned.`,
+	Example: `  # Remove backups older than 30 days
+  claude-switch backup prune --older-than 30d
+
+  # Keep only the 10 newest backups, previewing first
+  claude-switch backup prune --keep 10 --dry-run`,
+	Args: cobra.NoArgs,
+	RunE: runBackupPrune,
+}
+
+func init() {
+	backupPruneCmd.Flags().String("older-than", "", "Remove backups older than this age (e.g. 30d, 2w, 12h)")
+	backupPruneCmd.Flags().Int("keep", 0, "Keep only this many of the newest backups")
+	backupPruneCmd.Flags().BoolP("dry-run", "n", false, "Show what would be removed without removing anything")
+
+	backupCmd.AddCommand(backupPruneCmd)
+}
+
+func runBackupPrune(cmd *cobra.Command, args []string) error {
+	olderThan, _ := cmd.Flags().GetString("older-than")
+	keep, _ := cmd.Flags().GetInt("keep")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+	if olderThan == "" && keep <= 0 {
+		return fmt.Errorf("specify --older-than and/or --keep")
+	}
+
+	opts := config.PruneOptions{Keep: keep, DryRun: dryRun}
+	if olderThan != "" {
+		age, err := parseAge(olderThan)
+		if err != nil {
+			return err
+		}
+		opts.OlderThan = age
+	}
+
+	// Create config manager
+	manager, err := newManager(cmd)
+	if err != nil {
+		return fmt.Errorf("failed to initialize config manager: %w", err)
+	}
+
+	removed, err := manager.PruneBackups(opts)
+	if err != nil {
+		return err
+	}
+
+	if len(removed) == 0 {
+		output.Println("✅ No backups to prune")
+		return nil
+	}
+
+	verb := "Removed"
+	if dryRun {
+		output.Println("🔍 DRY RUN MODE - No changes will be made")
+		verb = "Would remove"
+	}
+
+	var freed int64
+	for _, backup := range removed {
+		output.Printf("🗑️  %s (%s)\n", backup.Path, backup.CreatedAt.Format("2006-01-02 15:04:05"))
+		freed += backup.Size
+	}
+
+	output.Printf("\n%s %d backup%s, freeing %s\n", verb, len(removed), pluralize(len(removed)), storage.FormatSize(freed))
+	return nil
+}
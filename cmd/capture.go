@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Xanonymous-GitHub/claude-switch/internal/config"
+	"github.com/Xanonymous-GitHub/claude-switch/internal/output"
+	"github.com/Xanonymous-GitHub/claude-switch/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+var captureCmd = &cobra.Command{
+	Use:   "capture [config-name-or-id]",
+	Short: "Write the live settings back into the tracked configuration",
+	Long: `Save edits made to ~/.claude/settings.json into the configuration it
+came from, as a new revision.
+
+Without an argument, the configuration last applied with 'apply --track'
+is used. The changes are shown as a key-path diff and confirmed before
+anything is written; --yes skips the confirmation and --dry-run only shows
+the diff. Configurations containing template placeholders cannot be
+captured, since their placeholders would be replaced by rendered values.
+
+Earlier contents stay available through 'claude-switch revisions'.`,
+	Example: `  # Apply with tracking, tweak settings.json, then save the tweaks
+  claude-switch apply work --track
+  claude-switch capture
+
+  # Capture into a named configuration without prompting
+  claude-switch capture work --yes`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runCapture,
+}
+
+func init() {
+	captureCmd.Flags().BoolP("yes", "y", false, "Write the changes without confirmation")
+	captureCmd.Flags().BoolP("dry-run", "n", false, "Show the changes without writing them")
+	addGitCommitFlag(captureCmd)
+}
+
+func runCapture(cmd *cobra.Command, args []string) error {
+	// Check prerequisites
+	if err := checkPrerequisites(); err != nil {
+		return err
+	}
+
+	// Create config manager
+	manager, err := newManager(cmd)
+	if err != nil {
+		return fmt.Errorf("failed to initialize config manager: %w", err)
+	}
+
+	var cfg *config.Config
+	if len(args) == 0 {
+		cfg, err = manager.TrackedConfig()
+		if err != nil {
+			return fmt.Errorf("no configuration given: %w (use 'claude-switch apply <name> --track' or name one)", err)
+		}
+	} else {
+		cfg, err = manager.GetConfig(args[0])
+		if err != nil {
+			return fmt.Errorf("configuration not found: %w", err)
+		}
+	}
+
+	settingsPath, err := manager.GetClaudeSettingsPath()
+	if err != nil {
+		return err
+	}
+	if !storage.FileExists(settingsPath) {
+		return fmt.Errorf("no settings.json found at %s", settingsPath)
+	}
+
+	// Settings that merely contain {{ do not parse as a template
+	if _, vars, err := manager.TemplateVars(cfg.ID); err == nil && len(vars) > 0 {
+		return fmt.Errorf("'%s' is a template; capturing would replace its placeholders with rendered values", cfg.Name)
+	}
+
+	_, changes, err := manager.CaptureChanges(cfg.ID)
+	if err != nil {
+		return err
+	}
+	if len(changes) == 0 {
+		output.Printf("✅ Live settings already match '%s'; nothing to capture\n", cfg.Name)
+		return nil
+	}
+
+	output.Printf("🔀 %d change%s from '%s' to the live settings:\n", len(changes), pluralize(len(changes)), cfg.Name)
+	printChanges(changes)
+	output.Println()
+
+	if dryRun, _ := cmd.Flags().GetBool("dry-run"); dryRun {
+		output.Println("🔍 DRY RUN MODE - No changes will be made")
+		output.Printf("Would record a new revision of '%s'\n", cfg.Name)
+		return nil
+	}
+
+	if yes, _ := cmd.Flags().GetBool("yes"); !yes {
+		response, err := promptForInput(fmt.Sprintf("Write these changes into '%s'? (y/N): ", cfg.Name))
+		if err != nil {
+			return fmt.Errorf("failed to read confirmation: %w", err)
+		}
+		if strings.ToLower(response) != "y" {
+			output.Println("❌ Operation cancelled")
+			return nil
+		}
+	}
+
+	_, revision, err := manager.CaptureSettings(cfg.ID)
+	if err != nil {
+		return fmt.Errorf("failed to capture settings: %w", err)
+	}
+
+	output.Printf("✅ Captured live settings into '%s' (revision %d)\n", cfg.Name, revision)
+
+	commitStore(cmd, manager, fmt.Sprintf("Capture settings into '%s' (revision %d)", cfg.Name, revision))
+	return nil
+}
@@ -0,0 +1,46 @@
+package cmd
+
+import "github.com/spf13/cobra"
+
+func init() {
+	for _, c := range []*cobra.Command{
+		applyCmd, captureCmd, editCmd, removeCmd, renameCmd, revisionsCmd,
+		showCmd, statusCmd, templateVarsCmd, validateCmd, defaultSetCmd,
+		noteEditCmd, noteShowCmd,
+	} {
+		c.ValidArgsFunction = completeConfigNames
+	}
+
+	// diff compares with a second configuration
+	diffCmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 1 {
+			return completeConfigNames(cmd, nil, toComplete)
+		}
+		return completeConfigNames(cmd, args, toComplete)
+	}
+}
+
+// completeConfigNames completes the first argument with saved configuration
+// names. Each name carries its description, which zsh and fish show next to
+// the suggestion.
+func completeConfigNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	manager, err := newManager(cmd)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	var names []string
+	for _, cfg := range manager.GetConfigs() {
+		// A newline would end the suggestion early in the shell script
+		if description := singleLine(cfg.Description); description != "" {
+			names = append(names, cfg.Name+"\t"+description)
+		} else {
+			names = append(names, cfg.Name)
+		}
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
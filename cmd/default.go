@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Xanonymous-GitHub/claude-switch/internal/config"
+	"github.com/Xanonymous-GitHub/claude-switch/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var defaultCmd = &cobra.Command{
+	Use:   "default",
+	Short: "Manage the default configuration",
+	Long: `Manage the configuration applied by a bare 'claude-switch apply'.
+
+At most one configuration is the default. It is marked in 'list', and
+removing it clears the marker.`,
+	Example: `  # Mark a configuration as the default
+  claude-switch default set work
+
+  # Show the current default
+  claude-switch default show
+
+  # Clear the default
+  claude-switch default unset`,
+	Args: cobra.NoArgs,
+	RunE: runDefaultShow,
+}
+
+var defaultSetCmd = &cobra.Command{
+	Use:   "set [config-name-or-id]",
+	Short: "Mark a configuration as the default",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runDefaultSet,
+}
+
+var defaultUnsetCmd = &cobra.Command{
+	Use:   "unset",
+	Short: "Clear the default configuration",
+	Args:  cobra.NoArgs,
+	RunE:  runDefaultUnset,
+}
+
+var defaultShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Print the name of the default configuration",
+	Args:  cobra.NoArgs,
+	RunE:  runDefaultShow,
+}
+
+func init() {
+	defaultCmd.AddCommand(defaultSetCmd)
+	defaultCmd.AddCommand(defaultUnsetCmd)
+	defaultCmd.AddCommand(defaultShowCmd)
+}
+
+func runDefaultSet(cmd *cobra.Command, args []string) error {
+	// Create config manager
+	manager, err := newManager(cmd)
+	if err != nil {
+		return fmt.Errorf("failed to initialize config manager: %w", err)
+	}
+
+	cfg, err := manager.SetDefault(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to set default configuration: %w", err)
+	}
+
+	output.Printf("✅ '%s' is now the default configuration\n", cfg.Name)
+	return nil
+}
+
+func runDefaultUnset(cmd *cobra.Command, args []string) error {
+	// Create config manager
+	manager, err := newManager(cmd)
+	if err != nil {
+		return fmt.Errorf("failed to initialize config manager: %w", err)
+	}
+
+	cfg, err := manager.UnsetDefault()
+	if errors.Is(err, config.ErrNoDefault) {
+		output.Println("No default configuration set.")
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to clear default configuration: %w", err)
+	}
+
+	output.Printf("✅ '%s' is no longer the default configuration\n", cfg.Name)
+	return nil
+}
+
+func runDefaultShow(cmd *cobra.Command, args []string) error {
+	// Create config manager
+	manager, err := newManager(cmd)
+	if err != nil {
+		return fmt.Errorf("failed to initialize config manager: %w", err)
+	}
+
+	cfg, err := manager.DefaultConfig()
+	if err != nil {
+		return err
+	}
+
+	// The bare name keeps the output usable in scripts
+	fmt.Println(cfg.Name)
+	return nil
+}
@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/Xanonymous-GitHub/claude-switch/internal/diff"
+	"github.com/Xanonymous-GitHub/claude-switch/internal/jsonutil"
+	"github.com/Xanonymous-GitHub/claude-switch/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var diffCmd = &cobra.Command{
+	Use:   "diff <config> [other-config]",
+	Short: "Show how a configuration differs from the live settings or another configuration",
+	Long: `Show the changes applying a configuration would make to
+~/.claude/settings.json. Given two configurations, show how the second
+differs from the first instead; the live settings are not read.
+
+By default the diff lists changed key paths, one per line, which is easy
+to script against. With --unified, both files are pretty-printed with
+sorted keys and compared line by line like 'git diff', with --context
+lines of surrounding context.`,
+	Example: `  # Key-path diff
+  claude-switch diff work
+
+  # Unified diff with 5 lines of context
+  claude-switch diff work --unified --context 5
+
+  # Compare two saved configurations
+  claude-switch diff work work-experimental`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runDiff,
+}
+
+func init() {
+	diffCmd.Flags().BoolP("unified", "u", false, "Show a line-based unified diff of the pretty-printed files")
+	diffCmd.Flags().IntP("context", "U", 3, "Lines of context around changes in --unified mode")
+}
+
+func runDiff(cmd *cobra.Command, args []string) error {
+	unified, _ := cmd.Flags().GetBool("unified")
+	context, _ := cmd.Flags().GetInt("context")
+
+	if context < 0 {
+		return fmt.Errorf("--context must not be negative")
+	}
+
+	// Create config manager
+	manager, err := newManager(cmd)
+	if err != nil {
+		return fmt.Errorf("failed to initialize config manager: %w", err)
+	}
+
+	// One configuration is compared with the live settings, two with each other
+	var before, after map[string]interface{}
+	var fromLabel, toLabel, sameMessage string
+	if len(args) == 2 {
+		from, fromSettings, err := manager.LoadSettings(args[0])
+		if err != nil {
+			return err
+		}
+		to, toSettings, err := manager.LoadSettings(args[1])
+		if err != nil {
+			return err
+		}
+		before, after = fromSettings, toSettings
+		fromLabel, toLabel = from.Name, to.Name
+		sameMessage = fmt.Sprintf("✅ '%s' and '%s' have the same settings", from.Name, to.Name)
+	} else {
+		cfg, live, configured, err := manager.LoadWithSettings(args[0])
+		if err != nil {
+			return err
+		}
+		settingsPath, err := manager.GetClaudeSettingsPath()
+		if err != nil {
+			return err
+		}
+		before, after = live, configured
+		fromLabel, toLabel = settingsPath, cfg.Name
+		sameMessage = fmt.Sprintf("✅ Live settings match '%s'", cfg.Name)
+	}
+
+	if !unified {
+		changes := jsonutil.Diff(before, after)
+		if len(changes) == 0 {
+			output.Println(sameMessage)
+			return nil
+		}
+		printChanges(changes)
+		return nil
+	}
+
+	// Maps marshal with sorted keys, so key order never shows up as a change
+	beforeText, err := jsonutil.MarshalIndent(before)
+	if err != nil {
+		return err
+	}
+	afterText, err := jsonutil.MarshalIndent(after)
+	if err != nil {
+		return err
+	}
+
+	text := diff.Unified(fromLabel, toLabel, string(beforeText), string(afterText), context)
+	if text == "" {
+		output.Println(sameMessage)
+		return nil
+	}
+	fmt.Print(text)
+	return nil
+}
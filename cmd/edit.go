@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Xanonymous-GitHub/claude-switch/internal/editor"
+	"github.com/Xanonymous-GitHub/claude-switch/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var editCmd = &cobra.Command{
+	Use:   "edit [config-name-or-id]",
+	Short: "Edit a saved configuration in your editor",
+	Long: `Open a saved configuration in your editor and store the result as a
+new revision.
+
+Configurations stored as TOML are edited as TOML. The edited file is
+validated before it is saved; invalid JSON can be
+re-edited or discarded. Quitting the editor with a non-zero exit (such as
+:cq in vim) cancels the edit without saving anything. Earlier revisions stay available through
+'claude-switch revisions' and 'apply --revision'.
+
+With --dry-run the editor still opens and the result is validated, but
+the new revision is only reported, not saved.
+
+--trim removes keys whose value is null, "", {} or [] (recursively) from
+the edited configuration before it is saved.
+
+--max-size (or the config.maxSize preference, 5 MB by default) limits the
+size of the saved file; 0 removes the limit.`,
+	Example: `  # Edit a configuration
+  claude-switch edit work`,
+	Args: cobra.ExactArgs(1),
+	RunE: runEdit,
+}
+
+func init() {
+	editCmd.Flags().BoolP("dry-run", "n", false, "Edit and validate, but only report the update instead of saving it")
+	editCmd.Flags().Bool("trim", false, "Remove keys whose value is null, \"\", {} or [] before saving")
+	addGitCommitFlag(editCmd)
+	addMaxSizeFlag(editCmd)
+}
+
+func runEdit(cmd *cobra.Command, args []string) error {
+	// Check if editor is available
+	if !editor.IsEditorAvailable() {
+		return fmt.Errorf("no editor found. Please set the $EDITOR environment variable or install a default editor")
+	}
+
+	// Create config manager
+	manager, err := newManager(cmd)
+	if err != nil {
+		return fmt.Errorf("failed to initialize config manager: %w", err)
+	}
+	setMaxConfigSize(cmd, manager)
+
+	cfg, err := manager.GetConfig(args[0])
+	if err != nil {
+		return fmt.Errorf("configuration not found: %w", err)
+	}
+
+	original, err := manager.ExportConfig(cfg.ID)
+	if err != nil {
+		return err
+	}
+
+	// Edit a copy so an abandoned edit never touches the stored file
+	tempFile := filepath.Join(os.TempDir(), fmt.Sprintf("claude-settings-%s-%d.%s", cfg.ID, os.Getpid(), cfg.StoredFormat()))
+	if err := os.WriteFile(tempFile, original, 0644); err != nil {
+		return fmt.Errorf("failed to create temporary config file: %w", err)
+	}
+	defer os.Remove(tempFile) // Clean up temp file
+
+	output.Printf("📝 Editing configuration '%s' in %s\n", cfg.Name, tempFile)
+
+	for {
+		if err := openEditor(cmd, tempFile); err != nil {
+			return err
+		}
+
+		err = validateSettingsFile(tempFile, cfg.StoredFormat())
+		if err == nil {
+			break
+		}
+
+		output.Fprintf(os.Stderr, "❌ Invalid configuration in edited file: %v\n", err)
+		output.Fprint(os.Stderr, "Do you want to edit again? (y/N): ")
+		reader := bufio.NewReader(os.Stdin)
+		response, _ := reader.ReadString('\n')
+		if strings.ToLower(strings.TrimSpace(response)) != "y" {
+			return fmt.Errorf("edit cancelled due to invalid %s", strings.ToUpper(cfg.StoredFormat()))
+		}
+	}
+
+	edited, err := os.ReadFile(tempFile)
+	if err != nil {
+		return fmt.Errorf("failed to read edited file: %w", err)
+	}
+
+	if trim, _ := cmd.Flags().GetBool("trim"); trim {
+		if edited, err = trimSettings(edited, cfg.StoredFormat()); err != nil {
+			return err
+		}
+	}
+
+	if bytes.Equal(edited, original) {
+		output.Println("No changes made.")
+		return nil
+	}
+
+	warnDuplicateKeys(tempFile, cfg.StoredFormat())
+
+	if dryRun, _ := cmd.Flags().GetBool("dry-run"); dryRun {
+		output.Println("🔍 DRY RUN MODE - No changes will be made")
+		output.Printf("Would update: %s (%d bytes -> %d bytes)\n", cfg.FilePath, len(original), len(edited))
+		output.Printf("Would record a new revision of '%s'\n", cfg.Name)
+		return nil
+	}
+
+	_, revision, err := manager.UpdateConfig(cfg.ID, edited)
+	if err != nil {
+		return fmt.Errorf("failed to update configuration: %w", err)
+	}
+
+	output.Printf("✅ Updated '%s' (revision %d)\n", cfg.Name, revision)
+
+	commitStore(cmd, manager, fmt.Sprintf("Edit config '%s' (revision %d)", cfg.Name, revision))
+	return nil
+}
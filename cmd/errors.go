@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/Xanonymous-GitHub/claude-switch/internal/config"
+	"github.com/Xanonymous-GitHub/claude-switch/internal/editor"
+	"github.com/Xanonymous-GitHub/claude-switch/internal/validation"
+)
+
+// ErrReported is returned by Execute when the failure has already been
+// written to stderr and the caller only needs to set the exit code
+var ErrReported = errors.New("error already reported")
+
+// exitDrift is the exit status of status when the settings have drifted,
+// so monitoring can tell drift from a failure to check (status 1)
+const exitDrift = 2
+
+// exitError gives an error an exit status other than the default 1
+type exitError struct {
+	err  error
+	code int
+}
+
+func (e *exitError) Error() string { return e.err.Error() }
+
+func (e *exitError) Unwrap() error { return e.err }
+
+// ExitCode returns the exit status for an error returned by Execute: 1
+// unless the command chose another
+func ExitCode(err error) int {
+	var exit *exitError
+	if errors.As(err, &exit) {
+		return exit.code
+	}
+	return 1
+}
+
+// jsonError is the machine-readable error object emitted with --json-errors
+type jsonError struct {
+	Error string `json:"error"`
+	Code  string `json:"code"`
+}
+
+// errorCode maps an error to a stable, machine-readable code
+func errorCode(err error) string {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+
+	switch {
+	case errors.Is(err, config.ErrConfigNotFound):
+		return "config_not_found"
+	case errors.Is(err, config.ErrAmbiguousID):
+		return "ambiguous_identifier"
+	case errors.Is(err, config.ErrConfigExists):
+		return "config_exists"
+	case errors.Is(err, config.ErrConfigTooLarge):
+		return "config_too_large"
+	case errors.Is(err, config.ErrBackupTooLarge):
+		return "backup_too_large"
+	case errors.Is(err, config.ErrStoreNotFound):
+		return "store_not_found"
+	case errors.Is(err, config.ErrRevisionNotFound):
+		return "revision_not_found"
+	case errors.Is(err, config.ErrUnknownPreference):
+		return "unknown_preference"
+	case errors.Is(err, config.ErrNoDefault):
+		return "no_default"
+	case errors.Is(err, config.ErrSchemaTooNew):
+		return "schema_unsupported"
+	case errors.Is(err, config.ErrNoActive):
+		return "no_active_config"
+	case errors.Is(err, config.ErrNoTracked):
+		return "no_tracked_config"
+	case errors.Is(err, config.ErrDeniedKey):
+		return "denied_key"
+	case errors.Is(err, config.ErrWriteCheck):
+		return "write_check_failed"
+	case errors.Is(err, config.ErrMissingRequiredKey):
+		return "missing_required_key"
+	case errors.Is(err, config.ErrInvalidSelector):
+		return "invalid_selector"
+	case errors.Is(err, errAuditFailed):
+		return "audit_failed"
+	case errors.Is(err, errSelfcheckFailed):
+		return "selfcheck_failed"
+	case errors.Is(err, errDrift):
+		return "settings_drift"
+	case errors.Is(err, editor.ErrCancelled):
+		return "editing_cancelled"
+	case errors.Is(err, config.ErrEmptyName), errors.Is(err, config.ErrInvalidName):
+		return "invalid_name"
+	case errors.As(err, &syntaxErr), errors.As(err, &typeErr):
+		return "invalid_json"
+	case errors.Is(err, validation.ErrUTF16):
+		return "invalid_encoding"
+	default:
+		return "error"
+	}
+}
+
+// writeJSONError writes err to w as a single-line JSON object
+func writeJSONError(w io.Writer, err error) {
+	data, marshalErr := json.Marshal(jsonError{Error: err.Error(), Code: errorCode(err)})
+	if marshalErr != nil {
+		fmt.Fprintf(w, "{\"error\": %q, \"code\": \"error\"}\n", err.Error())
+		return
+	}
+	fmt.Fprintln(w, string(data))
+}
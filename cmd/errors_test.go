@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestExitCode(t *testing.T) {
+	drift := &exitError{err: fmt.Errorf("%w from 'work'", errDrift), code: exitDrift}
+
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"plain error", errors.New("failed"), 1},
+		{"drift", drift, exitDrift},
+		{"wrapped drift", fmt.Errorf("status: %w", drift), exitDrift},
+		{"reported drift", &exitError{err: ErrReported, code: ExitCode(drift)}, exitDrift},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExitCode(tt.err); got != tt.want {
+				t.Errorf("ExitCode = %d, want %d", got, tt.want)
+			}
+		})
+	}
+
+	if code := errorCode(drift); code != "settings_drift" {
+		t.Errorf("errorCode(drift) = %s, want settings_drift", code)
+	}
+}
@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Xanonymous-GitHub/claude-switch/internal/config"
+	"github.com/Xanonymous-GitHub/claude-switch/internal/jsonutil"
+	"github.com/Xanonymous-GitHub/claude-switch/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+// shortHashLength is how many hex digits of a content hash are shown
+const shortHashLength = 12
+
+var findCmd = &cobra.Command{
+	Use:   "find",
+	Short: "Find configurations by content hash",
+	Long: `Find saved configurations by the hash of their contents.
+
+The hash is the SHA-256 of the configuration in canonical JSON form, so
+key order, formatting and storage format do not affect it. 'list
+--detailed' shows a prefix of each configuration's hash.
+
+--hash matches configurations whose hash starts with the given prefix.
+--file hashes a settings file, such as one shared by a teammate, and finds
+the configurations with the same contents, answering whether it is
+already saved.
+
+Each match is printed as its short hash and name. The command fails when
+nothing matches.`,
+	Example: `  # Look up a hash prefix from 'list --detailed'
+  claude-switch find --hash 3fa9c2
+
+  # Is this settings file already saved?
+  claude-switch find --file ~/Downloads/settings.json`,
+	Args: cobra.NoArgs,
+	RunE: runFind,
+}
+
+func init() {
+	findCmd.Flags().String("hash", "", "Find configurations whose content hash starts with this prefix")
+	findCmd.Flags().String("file", "", "Find configurations with the same contents as this settings file")
+	findCmd.MarkFlagsMutuallyExclusive("hash", "file")
+	findCmd.MarkFlagsOneRequired("hash", "file")
+}
+
+func runFind(cmd *cobra.Command, args []string) error {
+	prefix, _ := cmd.Flags().GetString("hash")
+	file, _ := cmd.Flags().GetString("file")
+
+	if file == "" {
+		prefix = strings.TrimSpace(prefix)
+		if prefix == "" || strings.Trim(strings.ToLower(prefix), "0123456789abcdef") != "" {
+			return fmt.Errorf("invalid hash prefix '%s' (expected hexadecimal digits)", prefix)
+		}
+	}
+
+	// Create config manager
+	manager, err := newManager(cmd)
+	if err != nil {
+		return fmt.Errorf("failed to initialize config manager: %w", err)
+	}
+
+	if file != "" {
+		data, err := storage.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", file, err)
+		}
+		if data, err = config.ToJSON(data, config.FormatJSON); err != nil {
+			return fmt.Errorf("invalid settings file %s: %w", file, err)
+		}
+		if prefix, err = jsonutil.Hash(data); err != nil {
+			return fmt.Errorf("invalid settings file %s: %w", file, err)
+		}
+	}
+
+	matches := manager.FindByHash(prefix)
+	if len(matches) == 0 {
+		// Not finding anything is an answer, not a usage mistake
+		cmd.SilenceUsage = true
+		if file != "" {
+			return fmt.Errorf("%w: no configuration has the contents of %s", config.ErrConfigNotFound, file)
+		}
+		return fmt.Errorf("%w: no configuration hash starts with '%s'", config.ErrConfigNotFound, prefix)
+	}
+
+	for _, cfg := range matches {
+		hash, _ := manager.ContentHash(&cfg)
+		fmt.Printf("%s  %s\n", shortHash(hash), cfg.Name)
+	}
+	return nil
+}
+
+// shortHash abbreviates a content hash for display
+func shortHash(hash string) string {
+	if len(hash) > shortHashLength {
+		return hash[:shortHashLength]
+	}
+	return hash
+}
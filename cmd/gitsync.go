@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/Xanonymous-GitHub/claude-switch/internal/config"
+	"github.com/Xanonymous-GitHub/claude-switch/internal/git"
+	"github.com/Xanonymous-GitHub/claude-switch/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// addGitCommitFlag registers --git-commit on a command that changes the store
+func addGitCommitFlag(cmd *cobra.Command) {
+	cmd.Flags().Bool("git-commit", false, "Commit the change when the store is a git work tree (or set git.autoCommit)")
+}
+
+// commitStore commits the store after a change when --git-commit is given or
+// the git.autoCommit preference is set. Failures only warn, since the change
+// itself has already been made.
+func commitStore(cmd *cobra.Command, manager *config.Manager, message string) {
+	explicit, _ := cmd.Flags().GetBool("git-commit")
+	if !explicit && !manager.BoolPreference(config.PrefGitAutoCommit) {
+		return
+	}
+
+	dir := manager.GetConfigDir()
+	if !git.IsWorkTree(dir) {
+		// The preference applies everywhere, so only an explicit request warns
+		if explicit {
+			output.Printf("⚠️  %s is not a git work tree (or git is not installed); nothing committed\n", dir)
+		}
+		return
+	}
+
+	committed, err := git.CommitAll(dir, message)
+	if err != nil {
+		output.Printf("⚠️  Failed to commit the change: %v\n", err)
+		return
+	}
+	if committed {
+		output.Printf("📝 Committed: %s\n", message)
+	}
+}
+
+// commitSettings commits the settings file about to be overwritten to the
+// git repository holding it, when --backup-git is given or the backup.git
+// preference is set. Like commitStore it only warns on failure.
+func commitSettings(cmd *cobra.Command, manager *config.Manager, settingsPath string, cfg *config.Config) {
+	explicit, _ := cmd.Flags().GetBool("backup-git")
+	if !explicit && !manager.BoolPreference(config.PrefBackupGit) {
+		return
+	}
+
+	dir := filepath.Dir(settingsPath)
+	if !git.IsWorkTree(dir) {
+		if explicit {
+			output.Printf("⚠️  %s is not a git work tree (or git is not installed); settings not committed\n", dir)
+		}
+		return
+	}
+
+	message := fmt.Sprintf("claude-switch: settings before applying '%s'", cfg.Name)
+	committed, err := git.CommitPath(dir, filepath.Base(settingsPath), message)
+	if err != nil {
+		output.Printf("⚠️  Failed to commit the current settings: %v\n", err)
+		return
+	}
+	if committed {
+		output.Printf("📝 Committed the current settings to git in %s\n", dir)
+	}
+}
@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+
+	"github.com/Xanonymous-GitHub/claude-switch/internal/config"
+	"github.com/Xanonymous-GitHub/claude-switch/internal/output"
+)
+
+// runHook runs a user-supplied hook command through the platform shell,
+// exposing the configuration being applied through environment variables
+func runHook(stage, command string, cfg *config.Config, settingsPath string) error {
+	output.Printf("🪝 Running %s hook: %s\n", stage, command)
+
+	var hook *exec.Cmd
+	if runtime.GOOS == "windows" {
+		hook = exec.Command("cmd", "/C", command)
+	} else {
+		hook = exec.Command("sh", "-c", command)
+	}
+
+	hook.Env = append(os.Environ(),
+		"CLAUDE_SWITCH_HOOK="+stage,
+		"CLAUDE_SWITCH_CONFIG="+cfg.Name,
+		"CLAUDE_SWITCH_CONFIG_ID="+cfg.ID,
+		"CLAUDE_SWITCH_SETTINGS="+settingsPath,
+	)
+	hook.Stdin = os.Stdin
+	hook.Stdout = os.Stdout
+	hook.Stderr = os.Stderr
+
+	if err := hook.Run(); err != nil {
+		return fmt.Errorf("%s hook failed: %w", stage, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Xanonymous-GitHub/claude-switch/internal/output"
+)
+
+// relativeTime renders t relative to now, e.g. "just now", "5 minutes ago", "3 weeks ago"
+func relativeTime(t, now time.Time) string {
+	d := now.Sub(t)
+	if d < 0 {
+		return "in the future"
+	}
+
+	switch {
+	case d < 10*time.Second:
+		return "just now"
+	case d < time.Minute:
+		return pluralUnit(int(d/time.Second), "second") + " ago"
+	case d < time.Hour:
+		return pluralUnit(int(d/time.Minute), "minute") + " ago"
+	case d < 24*time.Hour:
+		return pluralUnit(int(d/time.Hour), "hour") + " ago"
+	case d < 7*24*time.Hour:
+		return pluralUnit(int(d/(24*time.Hour)), "day") + " ago"
+	default:
+		return pluralUnit(int(d/(7*24*time.Hour)), "week") + " ago"
+	}
+}
+
+// pluralUnit formats a count with its unit, pluralizing the unit as needed
+func pluralUnit(count int, unit string) string {
+	return fmt.Sprintf("%d %s%s", count, unit, pluralize(count))
+}
+
+// parseAge parses a duration such as "30d", "2w" or "12h". Days and weeks
+// are accepted in addition to the units understood by time.ParseDuration.
+func parseAge(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	for suffix, unit := range map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour} {
+		if count, ok := strings.CutSuffix(s, suffix); ok {
+			n, err := strconv.Atoi(count)
+			if err != nil || n < 0 {
+				return 0, fmt.Errorf("invalid duration '%s'", s)
+			}
+			return time.Duration(n) * unit, nil
+		}
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid duration '%s' (use e.g. 30d, 2w or 12h)", s)
+	}
+	return d, nil
+}
+
+// singleLine collapses all whitespace in s, including newlines, to single
+// spaces so it fits in one table cell
+func singleLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// ellipsize shortens s to at most max runes, ending in "..." when cut
+func ellipsize(s string, max int) string {
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	return string(runes[:max-3]) + "..."
+}
+
+// printDescription prints a labeled description, aligning continuation lines
+// of a multi-line description under the first
+func printDescription(description string) {
+	const label = "   Description: "
+	lines := strings.Split(description, "\n")
+	output.Printf("%s%s\n", label, lines[0])
+	for _, line := range lines[1:] {
+		output.Printf("%s%s\n", strings.Repeat(" ", len(label)), line)
+	}
+}
@@ -0,0 +1,175 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Xanonymous-GitHub/claude-switch/internal/config"
+	"github.com/Xanonymous-GitHub/claude-switch/internal/output"
+	"github.com/Xanonymous-GitHub/claude-switch/internal/validation"
+	"github.com/spf13/cobra"
+)
+
+var importCmd = &cobra.Command{
+	Use:   "import --dir <path>",
+	Short: "Import a directory of settings files",
+	Long: `Import every *.json, *.jsonc and *.json5 file in a directory as a new
+configuration.
+
+Each file is validated and saved under its file name without the
+extension. .jsonc and .json5 files are read as JSONC: comments and
+trailing commas are accepted, and the configuration is stored as strict
+JSON. Files whose name is already taken are skipped unless
+--auto-name is given, and invalid files are reported as failed.
+A summary of imported, skipped and failed files is printed at the end.`,
+	Example: `  # Import a folder of settings files
+  claude-switch import --dir ./team-settings
+
+  # Include subdirectories and preview first
+  claude-switch import --dir ./team-settings --recursive --dry-run`,
+	Args: cobra.NoArgs,
+	RunE: runImport,
+}
+
+func init() {
+	importCmd.Flags().String("dir", "", "Directory containing *.json, *.jsonc or *.json5 settings files")
+	importCmd.Flags().BoolP("recursive", "r", false, "Descend into subdirectories")
+	importCmd.Flags().BoolP("dry-run", "n", false, "Show what would be imported without making changes")
+	importCmd.Flags().Bool("auto-name", false, "Add a numeric suffix instead of skipping when a name is taken")
+	addMaxSizeFlag(importCmd)
+	_ = importCmd.MarkFlagRequired("dir")
+}
+
+func runImport(cmd *cobra.Command, args []string) error {
+	dir, _ := cmd.Flags().GetString("dir")
+	recursive, _ := cmd.Flags().GetBool("recursive")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	autoName, _ := cmd.Flags().GetBool("auto-name")
+
+	files, err := findJSONFiles(dir, recursive)
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		output.Printf("No .json, .jsonc or .json5 files found in %s\n", dir)
+		return nil
+	}
+
+	// Create config manager
+	manager, err := newManager(cmd)
+	if err != nil {
+		return fmt.Errorf("failed to initialize config manager: %w", err)
+	}
+	setMaxConfigSize(cmd, manager)
+
+	// Names claimed by earlier files of a dry run, which stores nothing
+	claimed := make(map[string]bool)
+
+	var imported, skipped, failed int
+	for _, path := range files {
+		name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+
+		if (manager.Exists(name) || claimed[name]) && !autoName {
+			output.Printf("⏭️  %s: skipped, name '%s' already exists\n", path, name)
+			skipped++
+			continue
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			output.Printf("❌ %s: %v\n", path, err)
+			failed++
+			continue
+		}
+
+		sourceFormat := config.SourceFormat(path)
+		if sourceFormat == config.FormatJSONC {
+			if data, err = validation.Repair(data); err != nil {
+				output.Printf("❌ %s: %v\n", path, err)
+				failed++
+				continue
+			}
+		}
+
+		opts := config.AddOptions{AutoName: autoName, SourceFormat: sourceFormat}
+		if dryRun {
+			// The same checks and naming as a real import, counting the
+			// names earlier files would take
+			name, err := manager.CheckImport(name, data, opts, func(name string) bool { return claimed[name] })
+			if err != nil {
+				output.Printf("❌ %s: %v\n", path, err)
+				failed++
+				continue
+			}
+			output.Printf("Would import %s as '%s'\n", path, name)
+			claimed[name] = true
+			imported++
+			continue
+		}
+
+		cfg, err := manager.ImportConfigWithOptions(name, "", data, opts)
+		if errors.Is(err, config.ErrConfigExists) {
+			output.Printf("⏭️  %s: skipped, name '%s' already exists\n", path, name)
+			skipped++
+			continue
+		}
+		if err != nil {
+			output.Printf("❌ %s: %v\n", path, err)
+			failed++
+			continue
+		}
+
+		output.Printf("✅ %s: imported as '%s'\n", path, cfg.Name)
+		imported++
+	}
+
+	output.Println()
+	verb := "Imported"
+	if dryRun {
+		verb = "Would import"
+	}
+	output.Printf("%s %d, skipped %d, failed %d\n", verb, imported, skipped, failed)
+
+	if failed > 0 {
+		return fmt.Errorf("%d file%s failed to import", failed, pluralize(failed))
+	}
+	return nil
+}
+
+// findJSONFiles lists the *.json, *.jsonc and *.json5 files in dir in lexical
+// order, descending into subdirectories only when recursive is set
+func findJSONFiles(dir string, recursive bool) ([]string, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read directory: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory", dir)
+	}
+
+	var files []string
+	err = filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			if path != dir && !recursive {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if entry.Type().IsRegular() && (strings.EqualFold(filepath.Ext(path), ".json") || config.SourceFormat(path) == config.FormatJSONC) {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read directory: %w", err)
+	}
+
+	return files, nil
+}
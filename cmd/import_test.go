@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Xanonymous-GitHub/claude-switch/internal/config"
+)
+
+func TestImportDryRunMatchesImport(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		args  []string
+		want  []string
+	}{
+		{
+			name:  "auto-name",
+			files: map[string]string{"a/work.json": `{"model": "opus"}`, "b/work.json": `{"model": "haiku"}`},
+			args:  []string{"--recursive", "--auto-name"},
+			want: []string{
+				filepath.Join("a", "work.json") + " as 'work (2)'",
+				filepath.Join("b", "work.json") + " as 'work (3)'",
+			},
+		},
+		{
+			name:  "name pattern",
+			files: map[string]string{"Client_Work.json": `{"model": "opus"}`},
+			want:  []string{"Client_Work.json: invalid config name: 'Client_Work' does not match"},
+		},
+		{
+			name:  "max size",
+			files: map[string]string{"big.json": `{"model": "opus"}`},
+			args:  []string{"--max-size", "5"},
+			want:  []string{"big.json: config file too large"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			home := t.TempDir()
+			t.Setenv("HOME", home)
+			t.Setenv("USERPROFILE", home)
+			manager, err := config.NewManager()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := manager.ImportConfig("work", "", []byte(`{"model": "sonnet"}`)); err != nil {
+				t.Fatal(err)
+			}
+			if err := manager.SetPreference(config.PrefNamePattern, `^[a-z0-9 ()]+$`); err != nil {
+				t.Fatal(err)
+			}
+
+			dir := t.TempDir()
+			for name, data := range tt.files {
+				path := filepath.Join(dir, name)
+				if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			setArgs(t, importCmd, append([]string{"--dir", dir, "--dry-run"}, tt.args...)...)
+			out := captureStdout(t, func() { rootCmd.Execute() })
+
+			for _, want := range tt.want {
+				if !strings.Contains(out, want) {
+					t.Errorf("dry run output lacks %q:\n%s", want, out)
+				}
+			}
+
+			reloaded, err := config.NewManager()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if configs := reloaded.GetConfigs(); len(configs) != 1 {
+				t.Errorf("dry run left %d configurations, want 1", len(configs))
+			}
+		})
+	}
+}
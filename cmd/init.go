@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var initCmd = &cobra.Command{
+	Use:   "init <bash|zsh|fish>",
+	Short: "Print shell setup for aliases and a default profile",
+	Long: `Print a shell snippet that defines convenience aliases for claude-switch.
+
+The output is meant to be evaluated from your shell startup file. It is
+separate from 'completion', which only sets up tab-completion.
+
+With --profile, the snippet exports CLAUDE_SWITCH_PROFILE. Adding --apply
+also applies that configuration each time a shell starts.`,
+	Example: `  # bash (~/.bashrc)
+  eval "$(claude-switch init bash)"
+
+  # zsh (~/.zshrc), applying a default configuration on start
+  eval "$(claude-switch init zsh --profile work --apply)"
+
+  # fish (~/.config/fish/config.fish)
+  claude-switch init fish | source`,
+	Args:      cobra.ExactArgs(1),
+	ValidArgs: []string{"bash", "zsh", "fish"},
+	RunE:      runInit,
+}
+
+func init() {
+	initCmd.Flags().String("alias", "cs", "Name of the short alias for claude-switch (empty to skip)")
+	initCmd.Flags().String("profile", "", "Configuration to export as CLAUDE_SWITCH_PROFILE")
+	initCmd.Flags().Bool("apply", false, "Apply the --profile configuration when the shell starts")
+}
+
+func runInit(cmd *cobra.Command, args []string) error {
+	alias, _ := cmd.Flags().GetString("alias")
+	profile, _ := cmd.Flags().GetString("profile")
+	apply, _ := cmd.Flags().GetBool("apply")
+
+	if apply && profile == "" {
+		return fmt.Errorf("--apply requires --profile")
+	}
+
+	var snippet string
+	switch args[0] {
+	case "bash", "zsh":
+		snippet = posixInitScript(alias, profile, apply)
+	case "fish":
+		snippet = fishInitScript(alias, profile, apply)
+	default:
+		return fmt.Errorf("unsupported shell '%s' (valid: bash, zsh, fish)", args[0])
+	}
+
+	fmt.Print(snippet)
+	return nil
+}
+
+// posixInitScript renders the init snippet for bash and zsh
+func posixInitScript(alias, profile string, apply bool) string {
+	var b strings.Builder
+	b.WriteString("# claude-switch shell setup\n")
+	if alias != "" {
+		fmt.Fprintf(&b, "alias %s='claude-switch'\n", alias)
+	}
+	if profile != "" {
+		fmt.Fprintf(&b, "export CLAUDE_SWITCH_PROFILE=%s\n", posixQuote(profile))
+	}
+	if apply {
+		b.WriteString("claude-switch apply \"$CLAUDE_SWITCH_PROFILE\" --force --quiet >/dev/null\n")
+	}
+	return b.String()
+}
+
+// fishInitScript renders the init snippet for fish
+func fishInitScript(alias, profile string, apply bool) string {
+	var b strings.Builder
+	b.WriteString("# claude-switch shell setup\n")
+	if alias != "" {
+		fmt.Fprintf(&b, "alias %s 'claude-switch'\n", alias)
+	}
+	if profile != "" {
+		fmt.Fprintf(&b, "set -gx CLAUDE_SWITCH_PROFILE %s\n", fishQuote(profile))
+	}
+	if apply {
+		b.WriteString("claude-switch apply \"$CLAUDE_SWITCH_PROFILE\" --force --quiet >/dev/null\n")
+	}
+	return b.String()
+}
+
+// posixQuote single-quotes s for bash and zsh
+func posixQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// fishQuote single-quotes s for fish
+func fishQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return "'" + strings.ReplaceAll(s, "'", `\'`) + "'"
+}
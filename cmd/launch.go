@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Xanonymous-GitHub/claude-switch/internal/config"
+	"github.com/Xanonymous-GitHub/claude-switch/internal/output"
+	"github.com/Xanonymous-GitHub/claude-switch/internal/process"
+)
+
+// terminateTimeout is how long --kill-running waits for Claude Code to exit
+const terminateTimeout = 5 * time.Second
+
+// launchClaude starts Claude Code in the foreground after an apply, using the
+// claude.launchCmd preference or the claude executable on PATH. When no
+// launcher is found it falls back to the restart hint. Running instances are
+// stopped first with killRunning, and otherwise only reported.
+func launchClaude(manager *config.Manager, killRunning bool) error {
+	command, _, _ := manager.GetPreference(config.PrefClaudeLaunchCmd)
+	launch, err := launchCommand(command)
+	if err != nil {
+		output.Fprintf(os.Stderr, "⚠️  Cannot launch Claude Code: %v\n", err)
+		output.Println("🔄 Restart Claude Code to see the changes")
+		return nil
+	}
+
+	if pids, err := process.FindClaude(); err == nil && len(pids) > 0 {
+		ids := make([]string, len(pids))
+		for i, pid := range pids {
+			ids[i] = strconv.Itoa(pid)
+		}
+		if !killRunning {
+			output.Fprintf(os.Stderr, "⚠️  Claude Code is already running (pid %s) and keeps the old settings until restarted; use --kill-running to stop it first.\n", strings.Join(ids, ", "))
+		} else {
+			output.Printf("🛑 Stopping running Claude Code (pid %s)...\n", strings.Join(ids, ", "))
+			if err := process.Terminate(pids, terminateTimeout); err != nil {
+				return fmt.Errorf("failed to stop Claude Code: %w", err)
+			}
+		}
+	}
+
+	if command == "" {
+		command = launch.Path
+	}
+	output.Printf("🚀 Launching Claude Code: %s\n", command)
+	launch.Stdin = os.Stdin
+	launch.Stdout = os.Stdout
+	launch.Stderr = os.Stderr
+	if err := launch.Run(); err != nil {
+		return fmt.Errorf("failed to launch Claude Code: %w", err)
+	}
+	return nil
+}
+
+// launchCommand builds the command that starts Claude Code. A configured
+// command runs through the platform shell; without one the claude
+// executable is looked up on PATH.
+func launchCommand(command string) (*exec.Cmd, error) {
+	if command == "" {
+		path, err := exec.LookPath("claude")
+		if err != nil {
+			return nil, fmt.Errorf("'claude' not found on PATH (set %s to the command that starts it)", config.PrefClaudeLaunchCmd)
+		}
+		return exec.Command(path), nil
+	}
+
+	fields := strings.Fields(command)
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("%s is blank", config.PrefClaudeLaunchCmd)
+	}
+	if _, err := exec.LookPath(fields[0]); err != nil {
+		return nil, fmt.Errorf("'%s' from %s not found", fields[0], config.PrefClaudeLaunchCmd)
+	}
+
+	if runtime.GOOS == "windows" {
+		return exec.Command("cmd", "/C", command), nil
+	}
+	return exec.Command("sh", "-c", command), nil
+}
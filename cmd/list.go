@@ -1,18 +1,25 @@
 package cmd
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/Xanonymous-GitHub/claude-switch/internal/config"
+	"github.com/Xanonymous-GitHub/claude-switch/internal/git"
+	"github.com/Xanonymous-GitHub/claude-switch/internal/output"
+	"github.com/Xanonymous-GitHub/claude-switch/internal/storage"
 	"github.com/olekukonko/tablewriter"
 	"github.com/spf13/cobra"
 )
 
 var listCmd = &cobra.Command{
 	Use:     "list",
-	Aliases: []string{"ls", "show"},
+	Aliases: []string{"ls"},
 	Short:   "List all saved configurations",
 	Long: `List all saved Claude Code configurations with details.
 
@@ -22,25 +29,100 @@ This command displays a table showing:
 - Description
 - Creation date
 - File size
+- Claude Code version (with --detailed)
+- Content hash, a SHA-256 prefix of the canonical JSON (with --detailed)
+- Last git commit touching the file (with --detailed, when the store
+  directory is a git work tree)
 
-Use the configuration name or full ID with other commands.`,
+Use --fields to choose which columns appear and in what order. --detailed
+shows every column with full IDs and descriptions.
+
+Use the configuration name, full ID, or a unique ID prefix with other commands.
+
+The table layout may change between versions. For scripts, --porcelain
+prints one tab-separated line per configuration (full ID, name, creation
+time in RFC 3339 UTC, size in bytes) with no header or decoration; this
+format is stable and will not change.
+
+--active-only lists just the configuration whose contents match the current
+~/.claude/settings.json and fails when none does.
+
+--stale-after marks configurations created longer ago than the given age
+(such as 90d, 8w or 720h) as stale in the table, as a nudge to review or
+prune them. --stale-only lists just those configurations.
+
+--watch redraws the table whenever configurations are added, changed or
+removed, or the live settings change, marking the configuration they match
+as active, until interrupted. It is ignored when output is not a terminal.
+
+` + selectHelp,
 	Example: `  # List all configurations
   claude-switch list
 
-  # Alternative commands
-  claude-switch ls
-  claude-switch show`,
+  # Choose columns
+  claude-switch list --fields name,created
+
+  # Stable tab-separated output for scripts
+  claude-switch list --porcelain | cut -f2
+
+  # Stream one JSON object per line
+  claude-switch list -o ndjson
+
+  # Show creation dates as "3 days ago"
+  claude-switch list --relative-time
+
+  # Flag configurations older than three months
+  claude-switch list --stale-after 90d
+
+  # Review only the stale ones
+  claude-switch list --stale-after 90d --stale-only
+
+  # Filter with an expression
+  claude-switch list --select 'name~work and created<30d and size>1kb'
+
+  # Find configurations that fail validation
+  claude-switch list --select 'not valid'
+
+  # Keep the table up to date while you work
+  claude-switch list --watch
+
+  # Show only the configuration the current settings match
+  claude-switch list --active-only --porcelain | cut -f2
+
+  # Alternative command
+  claude-switch ls`,
 	RunE: runList,
 }
 
 func init() {
 	listCmd.Flags().BoolP("detailed", "d", false, "Show detailed information including full IDs")
-	listCmd.Flags().BoolP("json", "j", false, "Output in JSON format")
+	listCmd.Flags().BoolP("json", "j", false, "Output in JSON format (same as --output json)")
+	listCmd.Flags().StringSlice("fields", nil, "Columns to show, in order (id, name, description, created, size, claude, source, hash, commit)")
+	listCmd.Flags().Bool("porcelain", false, "Stable tab-separated output for scripts (same as --output porcelain)")
+	listCmd.Flags().StringP("output", "o", "table", "Output format: table, json, ndjson, or porcelain")
+	listCmd.Flags().Bool("active-only", false, "List only the configuration that matches the current settings")
+	listCmd.Flags().Bool("relative-time", false, "Show dates relative to now (e.g. \"3 days ago\")")
+	listCmd.Flags().Bool("absolute-time", false, "Show absolute dates (default)")
+	listCmd.MarkFlagsMutuallyExclusive("relative-time", "absolute-time")
+	listCmd.Flags().String("stale-after", "", "Mark configurations older than this age as stale (e.g. 90d, 8w)")
+	listCmd.Flags().Bool("stale-only", false, "With --stale-after, list only stale configurations")
+	listCmd.Flags().BoolP("watch", "w", false, "Redraw the table as configurations or the live settings change, until interrupted")
+	listCmd.Flags().String("select", "", "List only configurations matching a filter expression (e.g. 'name~work and created<30d')")
 }
 
 func runList(cmd *cobra.Command, args []string) error {
+	// Redrawing in place needs a terminal; otherwise list once
+	if watch, _ := cmd.Flags().GetBool("watch"); watch && output.Terminal() {
+		return watchList(cmd)
+	}
+	return listConfigs(cmd, false)
+}
+
+// listConfigs prints the configurations once. markActive marks the one the
+// live settings match in the table.
+func listConfigs(cmd *cobra.Command, markActive bool) error {
 	// Create config manager
-	manager, err := config.NewManager()
+	manager, err := newManager(cmd)
 	if err != nil {
 		return fmt.Errorf("failed to initialize config manager: %w", err)
 	}
@@ -48,56 +130,256 @@ func runList(cmd *cobra.Command, args []string) error {
 	// Get all configurations
 	configs := manager.GetConfigs()
 
-	// Check if any configurations exist
-	if len(configs) == 0 {
-		fmt.Println("📋 No configurations found.")
-		fmt.Println()
-		fmt.Println("💡 Use 'claude-switch add' to create your first configuration")
-		return nil
+	activeOnly, _ := cmd.Flags().GetBool("active-only")
+	var activeErr error
+	if activeOnly {
+		active, err := manager.ActiveConfig()
+		switch {
+		case err == nil:
+			configs = []config.Config{*active}
+		case errors.Is(err, config.ErrNoActive):
+			// Machine formats still print their empty result before failing
+			configs, activeErr = nil, err
+			cmd.SilenceUsage = true
+		default:
+			return err
+		}
+	}
+
+	var staleBefore time.Time
+	if staleAfter, _ := cmd.Flags().GetString("stale-after"); staleAfter != "" {
+		age, err := parseAge(staleAfter)
+		if err != nil {
+			return err
+		}
+		staleBefore = time.Now().Add(-age)
+	}
+	if staleOnly, _ := cmd.Flags().GetBool("stale-only"); staleOnly {
+		if staleBefore.IsZero() {
+			return fmt.Errorf("--stale-only requires --stale-after")
+		}
+		configs = slices.DeleteFunc(configs, func(cfg config.Config) bool {
+			return !cfg.CreatedAt.Before(staleBefore)
+		})
+	}
+
+	if expr, _ := cmd.Flags().GetString("select"); expr != "" {
+		selected, err := selectConfigs(cmd, manager, expr)
+		if err != nil {
+			return err
+		}
+		configs = slices.DeleteFunc(configs, func(cfg config.Config) bool {
+			return !slices.ContainsFunc(selected, func(s config.Config) bool { return s.ID == cfg.ID })
+		})
 	}
 
 	// Check flags
 	detailed, _ := cmd.Flags().GetBool("detailed")
 	jsonOutput, _ := cmd.Flags().GetBool("json")
+	format, _ := cmd.Flags().GetString("output")
+	relative, _ := cmd.Flags().GetBool("relative-time")
+	fieldNames, _ := cmd.Flags().GetStringSlice("fields")
+
+	porcelain, _ := cmd.Flags().GetBool("porcelain")
 
 	if jsonOutput {
-		return outputJSON(configs)
+		format = "json"
+	}
+	if porcelain {
+		format = "porcelain"
+	}
+
+	// --detailed is a preset for every field unless --fields picks them explicitly
+	if len(fieldNames) == 0 {
+		fieldNames = defaultListFields
+		if detailed {
+			fieldNames = nil
+			for _, field := range listFields {
+				fieldNames = append(fieldNames, field.name)
+			}
+		}
+	}
+	fields, err := selectListFields(fieldNames)
+	if err != nil {
+		return err
+	}
+
+	if activeErr != nil && format == "table" {
+		return activeErr
+	}
+
+	// Check if any configurations exist (machine formats print an empty result)
+	if len(configs) == 0 && format == "table" {
+		output.Println("📋 No configurations found.")
+		output.Println()
+		output.Println("💡 Use 'claude-switch add' to create your first configuration")
+		return nil
+	}
+
+	switch format {
+	case "table":
+		opts := tableOptions{detailed: detailed, relative: relative, staleBefore: staleBefore}
+		opts.manager = manager
+		if markActive {
+			if active, err := manager.ActiveConfig(); err == nil {
+				opts.activeID = active.ID
+			}
+		}
+		if slices.ContainsFunc(fields, func(f listField) bool { return f.name == "commit" }) {
+			opts.storeDir = manager.GetConfigDir()
+			opts.git = git.IsWorkTree(opts.storeDir)
+		}
+		return outputTable(configs, fields, opts)
+	case "json":
+		err = outputJSON(configs)
+	case "ndjson":
+		err = outputNDJSON(configs)
+	case "porcelain":
+		outputPorcelain(configs)
+	default:
+		return fmt.Errorf("unknown output format '%s' (valid: table, json, ndjson, porcelain)", format)
+	}
+	if err != nil {
+		return err
+	}
+	return activeErr
+}
+
+// tableOptions controls how list table cells are rendered
+type tableOptions struct {
+	detailed    bool            // show full IDs and descriptions
+	relative    bool            // show dates relative to now
+	storeDir    string          // configuration store directory
+	git         bool            // store directory is a git work tree
+	staleBefore time.Time       // configurations created earlier are stale; zero disables
+	activeID    string          // ID of the configuration marked active; empty marks none
+	manager     *config.Manager // reads contents for the hash column
+}
+
+// listField is a selectable column of the list table
+type listField struct {
+	name   string
+	header string
+	value  func(cfg config.Config, opts tableOptions) string
+}
+
+// listFields are the columns available to --fields; --detailed shows all of them
+var listFields = []listField{
+	{"id", "ID", func(cfg config.Config, opts tableOptions) string {
+		if !opts.detailed && len(cfg.ID) > 8 {
+			return cfg.ID[:8] + "..."
+		}
+		return cfg.ID
+	}},
+	{"name", "Name", func(cfg config.Config, opts tableOptions) string {
+		name := cfg.Name
+		if cfg.Default {
+			name += " (default)"
+		}
+		if opts.activeID != "" && cfg.ID == opts.activeID {
+			name += " (active)"
+		}
+		if !opts.staleBefore.IsZero() && cfg.CreatedAt.Before(opts.staleBefore) {
+			name += " ⚠ stale"
+		}
+		return name
+	}},
+	{"description", "Description", func(cfg config.Config, opts tableOptions) string {
+		// Newlines would break the row, so descriptions always fit one line
+		description := singleLine(cfg.Description)
+		if description == "" {
+			return "-"
+		}
+		if !opts.detailed {
+			return ellipsize(description, 40)
+		}
+		return description
+	}},
+	{"created", "Created", func(cfg config.Config, opts tableOptions) string {
+		if opts.relative {
+			return relativeTime(cfg.CreatedAt, time.Now())
+		}
+		return cfg.CreatedAt.Format("2006-01-02 15:04")
+	}},
+	{"size", "Size", func(cfg config.Config, opts tableOptions) string {
+		return getFileSize(cfg.FilePath)
+	}},
+	{"claude", "Claude", func(cfg config.Config, opts tableOptions) string {
+		if cfg.ClaudeVersion == "" {
+			return "-"
+		}
+		return cfg.ClaudeVersion
+	}},
+	{"source", "Source", func(cfg config.Config, opts tableOptions) string {
+		if cfg.SourceFormat == "" {
+			return "-"
+		}
+		return cfg.SourceFormat
+	}},
+	{"hash", "Hash", func(cfg config.Config, opts tableOptions) string {
+		hash, err := opts.manager.ContentHash(&cfg)
+		if err != nil {
+			return "-"
+		}
+		return shortHash(hash)
+	}},
+	{"commit", "Commit", func(cfg config.Config, opts tableOptions) string {
+		if !opts.git {
+			return "-"
+		}
+		commit, err := git.LastCommit(opts.storeDir, cfg.FilePath)
+		if err != nil || commit == "" {
+			return "-"
+		}
+		return commit
+	}},
+}
+
+// defaultListFields are shown when neither --fields nor --detailed is given
+var defaultListFields = []string{"id", "name", "description", "created", "size"}
+
+// selectListFields resolves field names to columns, in the order given
+func selectListFields(names []string) ([]listField, error) {
+	valid := make([]string, len(listFields))
+	for i, field := range listFields {
+		valid[i] = field.name
+	}
+
+	selected := make([]listField, 0, len(names))
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		index := slices.Index(valid, name)
+		if index < 0 {
+			return nil, fmt.Errorf("unknown field '%s' (valid: %s)", name, strings.Join(valid, ", "))
+		}
+		selected = append(selected, listFields[index])
 	}
 
-	return outputTable(configs, detailed)
+	return selected, nil
 }
 
 // outputTable displays configurations in a formatted table
-func outputTable(configs []config.Config, detailed bool) error {
-	fmt.Printf("📋 Found %d configuration%s:\n\n", len(configs), pluralize(len(configs)))
+func outputTable(configs []config.Config, fields []listField, opts tableOptions) error {
+	output.Printf("📋 Found %d configuration%s:\n\n", len(configs), pluralize(len(configs)))
 
 	// Create table with new API
 	table := tablewriter.NewWriter(os.Stdout)
 
 	// Set table headers using the new API
-	table.Header("ID", "Name", "Description", "Created", "Size")
+	headers := make([]any, len(fields))
+	for i, field := range fields {
+		headers[i] = field.header
+	}
+	table.Header(headers...)
 
 	// Add rows
 	for _, cfg := range configs {
-		id := cfg.ID
-		if !detailed && len(id) > 8 {
-			id = id[:8] + "..."
+		row := make([]any, len(fields))
+		for i, field := range fields {
+			row[i] = field.value(cfg, opts)
 		}
 
-		description := cfg.Description
-		if description == "" {
-			description = "-"
-		} else if !detailed && len(description) > 40 {
-			description = description[:37] + "..."
-		}
-
-		// Get file size
-		size := getFileSize(cfg.FilePath)
-
-		// Format creation date
-		created := cfg.CreatedAt.Format("2006-01-02 15:04")
-
-		err := table.Append(id, cfg.Name, description, created, size)
+		err := table.Append(row...)
 		if err != nil {
 			return fmt.Errorf("failed to add row to table: %w", err)
 		}
@@ -109,54 +391,67 @@ func outputTable(configs []config.Config, detailed bool) error {
 		return fmt.Errorf("failed to render table: %w", err)
 	}
 
-	fmt.Println()
-	fmt.Printf("💡 Use 'claude-switch apply <name>' to switch to a configuration\n")
-	fmt.Printf("💡 Use 'claude-switch remove <name>' to delete a configuration\n")
+	output.Println()
+	output.Printf("💡 Use 'claude-switch apply <name>' to switch to a configuration\n")
+	output.Printf("💡 Use 'claude-switch remove <name>' to delete a configuration\n")
 
-	if !detailed {
-		fmt.Printf("💡 Use '--detailed' flag to see full IDs and descriptions\n")
+	if !opts.detailed {
+		output.Printf("💡 Use '--detailed' flag to see full IDs and descriptions\n")
 	}
 
 	return nil
 }
 
-// outputJSON displays configurations in JSON format
+// outputJSON displays configurations as a JSON array
 func outputJSON(configs []config.Config) error {
-	// This would use the json package to marshal and output
-	// For brevity, showing simplified version
-	fmt.Println("[")
-	for i, cfg := range configs {
-		fmt.Printf("  {\n")
-		fmt.Printf("    \"id\": \"%s\",\n", cfg.ID)
-		fmt.Printf("    \"name\": \"%s\",\n", cfg.Name)
-		fmt.Printf("    \"description\": \"%s\",\n", cfg.Description)
-		fmt.Printf("    \"created_at\": \"%s\",\n", cfg.CreatedAt.Format(time.RFC3339))
-		fmt.Printf("    \"file_path\": \"%s\"\n", cfg.FilePath)
-		if i < len(configs)-1 {
-			fmt.Printf("  },\n")
-		} else {
-			fmt.Printf("  }\n")
-		}
-	}
-	fmt.Println("]")
+	if configs == nil {
+		configs = []config.Config{}
+	}
+
+	data, err := json.MarshalIndent(configs, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal configurations: %w", err)
+	}
+	fmt.Println(string(data))
 	return nil
 }
 
+// outputNDJSON displays configurations as newline-delimited JSON, one compact object per line
+func outputNDJSON(configs []config.Config) error {
+	encoder := json.NewEncoder(os.Stdout)
+	for _, cfg := range configs {
+		if err := encoder.Encode(cfg); err != nil {
+			return fmt.Errorf("failed to marshal configuration: %w", err)
+		}
+	}
+	return nil
+}
+
+// outputPorcelain prints one tab-separated line per configuration:
+// id, name, created (RFC 3339, UTC) and size in bytes. This format is a
+// stable contract for scripts; do not change it.
+func outputPorcelain(configs []config.Config) {
+	// Tabs and newlines in names would break the line format
+	sanitize := strings.NewReplacer("\t", " ", "\n", " ", "\r", " ")
+
+	for _, cfg := range configs {
+		size, err := storage.GetFileSize(cfg.FilePath)
+		if err != nil {
+			size = 0
+		}
+
+		fmt.Printf("%s\t%s\t%s\t%d\n", cfg.ID, sanitize.Replace(cfg.Name),
+			cfg.CreatedAt.UTC().Format(time.RFC3339), size)
+	}
+}
+
 // getFileSize returns a human-readable file size
 func getFileSize(filePath string) string {
-	info, err := os.Stat(filePath)
+	size, err := storage.GetFileSize(filePath)
 	if err != nil {
 		return "unknown"
 	}
-
-	size := info.Size()
-	if size < 1024 {
-		return fmt.Sprintf("%d B", size)
-	} else if size < 1024*1024 {
-		return fmt.Sprintf("%.1f KB", float64(size)/1024)
-	} else {
-		return fmt.Sprintf("%.1f MB", float64(size)/(1024*1024))
-	}
+	return storage.FormatSize(size)
 }
 
 // pluralize returns "s" if count is not 1
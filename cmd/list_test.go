@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Xanonymous-GitHub/claude-switch/internal/config"
+)
+
+// captureStdout returns what fn writes to standard output
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	done := make(chan string)
+	go func() {
+		data, _ := io.ReadAll(r)
+		done <- string(data)
+	}()
+
+	fn()
+	w.Close()
+	return <-done
+}
+
+func TestOutputTableMultiLineDescription(t *testing.T) {
+	configs := []config.Config{
+		{ID: "a1b2c3d4e5f6", Name: "work", Description: "First line\nsecond line", CreatedAt: time.Now()},
+		{ID: "f6e5d4c3b2a1", Name: "home", Description: "Single line", CreatedAt: time.Now()},
+	}
+	fields, err := selectListFields([]string{"name", "description"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, detailed := range []bool{false, true} {
+		out := captureStdout(t, func() {
+			if err := outputTable(configs, fields, tableOptions{detailed: detailed}); err != nil {
+				t.Fatalf("outputTable: %v", err)
+			}
+		})
+
+		var rows []string
+		for _, line := range strings.Split(out, "\n") {
+			if strings.Contains(line, "work") || strings.Contains(line, "second line") {
+				rows = append(rows, line)
+			}
+		}
+		if len(rows) != 1 || !strings.Contains(rows[0], "First line second line") {
+			t.Errorf("detailed=%v: description rendered on rows %q, want one row", detailed, rows)
+		}
+	}
+}
+
+func TestEllipsize(t *testing.T) {
+	tests := []struct {
+		s    string
+		max  int
+		want string
+	}{
+		{"short", 10, "short"},
+		{"exactly ten", 11, "exactly ten"},
+		{"a longer description", 10, "a longe..."},
+		{"ünïcödé text", 8, "ünïcö..."},
+	}
+
+	for _, tt := range tests {
+		if got := ellipsize(tt.s, tt.max); got != tt.want {
+			t.Errorf("ellipsize(%q, %d) = %q, want %q", tt.s, tt.max, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"github.com/Xanonymous-GitHub/claude-switch/internal/config"
+	"github.com/Xanonymous-GitHub/claude-switch/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var migrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Move the config store to a new location",
+	Long: `Move the config store to where current conventions expect it.
+
+--store moves ~/.claude-switch to $XDG_CONFIG_HOME/claude-switch (or
+~/.config/claude-switch when XDG_CONFIG_HOME is unset), updating the file
+paths recorded in its metadata. Afterwards claude-switch finds the store
+there automatically. Git history and backups inside the store move with it.
+
+The store is looked up in this order: $CLAUDE_SWITCH_DIR, the XDG
+location if it exists, then ~/.claude-switch. Windows always uses
+~/.claude-switch.`,
+	Example: `  # Preview, then move the store to ~/.config/claude-switch
+  claude-switch migrate --store --dry-run
+  claude-switch migrate --store`,
+	Args: cobra.NoArgs,
+	RunE: runMigrate,
+}
+
+func init() {
+	migrateCmd.Flags().Bool("store", false, "Move ~/.claude-switch to the XDG config directory")
+	migrateCmd.Flags().BoolP("dry-run", "n", false, "Show where the store would move without moving it")
+	migrateCmd.MarkFlagRequired("store")
+}
+
+func runMigrate(cmd *cobra.Command, args []string) error {
+	if dryRun, _ := cmd.Flags().GetBool("dry-run"); dryRun {
+		from, err := config.LegacyDir()
+		if err != nil {
+			return err
+		}
+		to, err := config.XDGDir()
+		if err != nil {
+			return err
+		}
+		output.Println("🔍 DRY RUN MODE - No changes will be made")
+		output.Printf("Would move: %s -> %s\n", from, to)
+		return nil
+	}
+
+	from, to, err := config.MigrateStore()
+	if err != nil {
+		// Nothing was moved; explain rather than show usage
+		cmd.SilenceUsage = true
+		return err
+	}
+
+	output.Printf("✅ Moved the config store: %s -> %s\n", from, to)
+	return nil
+}
@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/Xanonymous-GitHub/claude-switch/internal/editor"
+	"github.com/Xanonymous-GitHub/claude-switch/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var noteCmd = &cobra.Command{
+	Use:   "note",
+	Short: "Keep freeform notes about a configuration",
+	Long: `Keep Markdown notes about a configuration, such as why it exists or who
+uses it, beyond its one-line description.
+
+Notes are stored next to the configuration as configs/<id>.notes.md. They
+are not part of the settings, are never applied or validated, and are
+deleted when the configuration is removed.`,
+	Example: `  # Write notes in your editor
+  claude-switch note edit work
+
+  # Print them
+  claude-switch note show work`,
+	Args: cobra.NoArgs,
+}
+
+var noteEditCmd = &cobra.Command{
+	Use:   "edit [config-name-or-id]",
+	Short: "Edit the notes of a configuration in your editor",
+	Long: `Open the notes of a configuration in your editor. Saving an empty file
+removes the notes; quitting the editor with a non-zero exit discards the edit.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runNoteEdit,
+}
+
+var noteShowCmd = &cobra.Command{
+	Use:   "show [config-name-or-id]",
+	Short: "Print the notes of a configuration",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runNoteShow,
+}
+
+func init() {
+	noteCmd.AddCommand(noteEditCmd)
+	noteCmd.AddCommand(noteShowCmd)
+	addGitCommitFlag(noteEditCmd)
+}
+
+func runNoteEdit(cmd *cobra.Command, args []string) error {
+	// Check if editor is available
+	if !editor.IsEditorAvailable() {
+		return fmt.Errorf("no editor found. Please set the $EDITOR environment variable or install a default editor")
+	}
+
+	// Create config manager
+	manager, err := newManager(cmd)
+	if err != nil {
+		return fmt.Errorf("failed to initialize config manager: %w", err)
+	}
+
+	cfg, notes, err := manager.Notes(args[0])
+	if err != nil {
+		return fmt.Errorf("configuration not found: %w", err)
+	}
+
+	// Edit a copy so an abandoned edit never touches the stored notes
+	tempFile := filepath.Join(os.TempDir(), fmt.Sprintf("claude-notes-%s-%d.md", cfg.ID, os.Getpid()))
+	if err := os.WriteFile(tempFile, []byte(notes), 0644); err != nil {
+		return fmt.Errorf("failed to create temporary notes file: %w", err)
+	}
+	defer os.Remove(tempFile) // Clean up temp file
+
+	if err := openEditor(cmd, tempFile); err != nil {
+		return err
+	}
+
+	edited, err := os.ReadFile(tempFile)
+	if err != nil {
+		return fmt.Errorf("failed to read edited file: %w", err)
+	}
+	if string(edited) == notes {
+		output.Println("No changes made.")
+		return nil
+	}
+
+	if _, err := manager.SetNotes(cfg.ID, string(edited)); err != nil {
+		return err
+	}
+
+	if len(edited) == 0 {
+		output.Printf("🗑️  Removed the notes of '%s'\n", cfg.Name)
+	} else {
+		output.Printf("✅ Saved the notes of '%s'\n", cfg.Name)
+	}
+
+	commitStore(cmd, manager, fmt.Sprintf("Edit notes of '%s'", cfg.Name))
+	return nil
+}
+
+func runNoteShow(cmd *cobra.Command, args []string) error {
+	// Create config manager
+	manager, err := newManager(cmd)
+	if err != nil {
+		return fmt.Errorf("failed to initialize config manager: %w", err)
+	}
+
+	cfg, notes, err := manager.Notes(args[0])
+	if err != nil {
+		return fmt.Errorf("configuration not found: %w", err)
+	}
+
+	if notes == "" {
+		output.Printf("📭 '%s' has no notes; add them with 'claude-switch note edit %s'\n", cfg.Name, cfg.Name)
+		return nil
+	}
+
+	fmt.Print(notes)
+	return nil
+}
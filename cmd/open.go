@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/Xanonymous-GitHub/claude-switch/internal/editor"
+	"github.com/Xanonymous-GitHub/claude-switch/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var openCmd = &cobra.Command{
+	Use:   "open",
+	Short: "Open the configuration store in your file manager",
+	Long: `Open a claude-switch directory in the system file manager.
+
+By default the store (~/.claude-switch) is opened. Use --configs for the
+directory holding the configuration files or --claude for ~/.claude.
+The platform opener is used: 'open' on macOS, 'explorer' on Windows and
+'xdg-open' elsewhere.`,
+	Example: `  # Open the store
+  claude-switch open
+
+  # Open the Claude Code settings directory
+  claude-switch open --claude`,
+	Args: cobra.NoArgs,
+	RunE: runOpen,
+}
+
+func init() {
+	openCmd.Flags().Bool("configs", false, "Open the directory holding the configuration files")
+	openCmd.Flags().Bool("claude", false, "Open the Claude Code directory (~/.claude)")
+	openCmd.MarkFlagsMutuallyExclusive("configs", "claude")
+}
+
+func runOpen(cmd *cobra.Command, args []string) error {
+	configs, _ := cmd.Flags().GetBool("configs")
+	claude, _ := cmd.Flags().GetBool("claude")
+
+	// Create config manager
+	manager, err := newManager(cmd)
+	if err != nil {
+		return fmt.Errorf("failed to initialize config manager: %w", err)
+	}
+
+	dir := manager.GetConfigDir()
+	switch {
+	case configs:
+		dir = filepath.Join(dir, "configs")
+	case claude:
+		if err := checkPrerequisites(); err != nil {
+			return err
+		}
+		if dir, err = manager.GetClaudeDir(); err != nil {
+			return err
+		}
+	}
+
+	if err := editor.OpenPath(dir); err != nil {
+		return err
+	}
+
+	output.Printf("📂 Opened %s\n", dir)
+	return nil
+}
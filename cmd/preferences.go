@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/Xanonymous-GitHub/claude-switch/internal/config"
+	"github.com/Xanonymous-GitHub/claude-switch/internal/output"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "View and change claude-switch preferences",
+	Long: `View and change claude-switch preferences.
+
+Preferences are stored in ~/.claude-switch/preferences.json. Unset
+preferences use their default value. Run 'claude-switch config list'
+to see every supported preference.`,
+	Example: `  # Show all preferences
+  claude-switch config list
+
+  # Keep 5 revisions per configuration
+  claude-switch config set revisions.keep 5
+
+  # Restore the default
+  claude-switch config unset revisions.keep`,
+}
+
+var configGetCmd = &cobra.Command{
+	Use:   "get <key>",
+	Short: "Print the value of a preference",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runConfigGet,
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set <key> <value>",
+	Short: "Set a preference",
+	Args:  cobra.ExactArgs(2),
+	RunE:  runConfigSet,
+}
+
+var configUnsetCmd = &cobra.Command{
+	Use:   "unset <key>",
+	Short: "Restore a preference to its default",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runConfigUnset,
+}
+
+var configListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List all preferences and their values",
+	Args:    cobra.NoArgs,
+	RunE:    runConfigList,
+}
+
+func init() {
+	configCmd.AddCommand(configGetCmd)
+	configCmd.AddCommand(configSetCmd)
+	configCmd.AddCommand(configUnsetCmd)
+	configCmd.AddCommand(configListCmd)
+}
+
+func runConfigGet(cmd *cobra.Command, args []string) error {
+	// Create config manager
+	manager, err := newManager(cmd)
+	if err != nil {
+		return fmt.Errorf("failed to initialize config manager: %w", err)
+	}
+
+	value, _, err := manager.GetPreference(args[0])
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(value)
+	return nil
+}
+
+func runConfigSet(cmd *cobra.Command, args []string) error {
+	// Create config manager
+	manager, err := newManager(cmd)
+	if err != nil {
+		return fmt.Errorf("failed to initialize config manager: %w", err)
+	}
+
+	if err := manager.SetPreference(args[0], args[1]); err != nil {
+		return err
+	}
+
+	output.Printf("✅ %s = %s\n", args[0], args[1])
+	return nil
+}
+
+func runConfigUnset(cmd *cobra.Command, args []string) error {
+	// Create config manager
+	manager, err := newManager(cmd)
+	if err != nil {
+		return fmt.Errorf("failed to initialize config manager: %w", err)
+	}
+
+	if err := manager.UnsetPreference(args[0]); err != nil {
+		return err
+	}
+
+	value, _, _ := manager.GetPreference(args[0])
+	output.Printf("✅ %s restored to default (%s)\n", args[0], value)
+	return nil
+}
+
+func runConfigList(cmd *cobra.Command, args []string) error {
+	// Create config manager
+	manager, err := newManager(cmd)
+	if err != nil {
+		return fmt.Errorf("failed to initialize config manager: %w", err)
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.Header("Key", "Value", "Type", "Description")
+
+	for _, pref := range config.KnownPreferences() {
+		value, set, _ := manager.GetPreference(pref.Key)
+		if !set {
+			value += " (default)"
+		}
+
+		if err := table.Append(pref.Key, value, pref.Kind.String(), pref.Description); err != nil {
+			return fmt.Errorf("failed to add row to table: %w", err)
+		}
+	}
+
+	if err := table.Render(); err != nil {
+		return fmt.Errorf("failed to render table: %w", err)
+	}
+
+	return nil
+}
@@ -4,23 +4,35 @@ import (
 	"bufio"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/Xanonymous-GitHub/claude-switch/internal/config"
+	"github.com/Xanonymous-GitHub/claude-switch/internal/output"
 	"github.com/spf13/cobra"
 )
 
 var removeCmd = &cobra.Command{
-	Use:     "remove [config-name-or-id]",
+	Use:     "remove [config-name-or-id | --select expression]",
 	Aliases: []string{"rm", "delete", "del"},
 	Short:   "Remove a saved configuration",
 	Long: `Remove a saved Claude Code configuration.
 
-This command will permanently delete the configuration file and
-remove it from the configuration list. This action cannot be undone.
+This command will delete the configuration file and remove it from the
+configuration list.
 
-The configuration file will be removed from ~/.claude-switch/configs/
-and the metadata will be updated.`,
+Before deleting, a copy of the file is saved to
+~/.claude-switch/removed/<id>-<timestamp>.json so a mistaken removal can
+be recovered. Use --no-backup to delete permanently.
+
+Use --keep-file to stop managing a configuration without deleting its
+file: only the metadata entry is dropped, and the file, its revisions and
+its notes stay on disk.
+
+With --select, every configuration matching the expression is removed
+after a single confirmation.
+
+` + selectHelp,
 	Example: `  # Remove configuration by name
   claude-switch remove my-old-config
 
@@ -30,27 +42,55 @@ and the metadata will be updated.`,
   # Remove without confirmation prompt
   claude-switch remove my-config --force
 
+  # Remove permanently without keeping a copy
+  claude-switch remove my-config --no-backup
+
+  # Unregister the configuration but leave its file on disk
+  claude-switch remove my-config --keep-file
+
+  # Remove every configuration that fails validation
+  claude-switch remove --select 'not valid'
+
+  # Preview removing old, small configurations
+  claude-switch remove --select 'created>90d and size<100' --dry-run
+
   # Alternative commands
   claude-switch rm my-config
   claude-switch delete my-config`,
-	Args: cobra.ExactArgs(1),
+	Args: cobra.MaximumNArgs(1),
 	RunE: runRemove,
 }
 
 func init() {
 	removeCmd.Flags().BoolP("force", "f", false, "Remove without confirmation prompt")
 	removeCmd.Flags().BoolP("dry-run", "n", false, "Show what would be removed without making changes")
+	removeCmd.Flags().Bool("no-backup", false, "Do not keep a copy of the removed configuration file")
+	removeCmd.Flags().Bool("keep-file", false, "Only remove the metadata entry, leaving the configuration file, revisions and notes on disk")
+	removeCmd.Flags().String("select", "", "Remove every configuration matching a filter expression")
+	removeCmd.MarkFlagsMutuallyExclusive("keep-file", "no-backup")
+	addGitCommitFlag(removeCmd)
 }
 
 func runRemove(cmd *cobra.Command, args []string) error {
-	identifier := args[0]
+	expr, _ := cmd.Flags().GetString("select")
+	switch {
+	case expr != "" && len(args) > 0:
+		return fmt.Errorf("cannot combine a configuration name with --select")
+	case expr == "" && len(args) == 0:
+		return fmt.Errorf("requires a configuration name or --select")
+	}
 
 	// Create config manager
-	manager, err := config.NewManager()
+	manager, err := newManager(cmd)
 	if err != nil {
 		return fmt.Errorf("failed to initialize config manager: %w", err)
 	}
 
+	if expr != "" {
+		return removeSelected(cmd, manager, expr)
+	}
+	identifier := args[0]
+
 	// Get the configuration to be removed
 	cfg, err := manager.GetConfig(identifier)
 	if err != nil {
@@ -60,40 +100,51 @@ func runRemove(cmd *cobra.Command, args []string) error {
 	// Get flags
 	force, _ := cmd.Flags().GetBool("force")
 	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	noBackup, _ := cmd.Flags().GetBool("no-backup")
+	keepFile, _ := cmd.Flags().GetBool("keep-file")
 
 	// Show configuration details
-	fmt.Printf("🗑️  Configuration to remove:\n")
-	fmt.Printf("   ID: %s\n", cfg.ID)
-	fmt.Printf("   Name: %s\n", cfg.Name)
+	output.Printf("🗑️  Configuration to remove:\n")
+	output.Printf("   ID: %s\n", cfg.ID)
+	output.Printf("   Name: %s\n", cfg.Name)
 	if cfg.Description != "" {
-		fmt.Printf("   Description: %s\n", cfg.Description)
+		printDescription(cfg.Description)
 	}
-	fmt.Printf("   Created: %s\n", cfg.CreatedAt.Format("2006-01-02 15:04:05"))
-	fmt.Printf("   File: %s\n", cfg.FilePath)
+	output.Printf("   Created: %s\n", cfg.CreatedAt.Format("2006-01-02 15:04:05"))
+	output.Printf("   File: %s\n", cfg.FilePath)
 
 	// Show file size if exists
 	if info, err := os.Stat(cfg.FilePath); err == nil {
-		fmt.Printf("   Size: %d bytes\n", info.Size())
+		output.Printf("   Size: %d bytes\n", info.Size())
 	}
 
-	fmt.Println()
+	output.Println()
 
 	// Dry run mode
 	if dryRun {
-		fmt.Println("🔍 DRY RUN MODE - No changes will be made")
-		fmt.Printf("Would remove file: %s\n", cfg.FilePath)
-		fmt.Printf("Would remove from configuration list: %s\n", cfg.Name)
+		output.Println("🔍 DRY RUN MODE - No changes will be made")
+		if keepFile {
+			output.Printf("Would keep file: %s\n", cfg.FilePath)
+		} else {
+			if !noBackup {
+				output.Printf("Would back up file to: %s\n", filepath.Dir(manager.RemovedPath(cfg)))
+			}
+			output.Printf("Would remove file: %s\n", cfg.FilePath)
+		}
+		output.Printf("Would remove from configuration list: %s\n", cfg.Name)
 		return nil
 	}
 
 	// Warning message
-	fmt.Printf("⚠️  Warning: This action cannot be undone!\n")
-	fmt.Printf("   The configuration file will be permanently deleted.\n")
-	fmt.Println()
+	if noBackup {
+		output.Printf("⚠️  Warning: This action cannot be undone!\n")
+		output.Printf("   The configuration file will be permanently deleted.\n")
+		output.Println()
+	}
 
 	// Confirmation prompt (unless forced)
 	if !force {
-		fmt.Printf("Are you sure you want to remove '%s'? (y/N): ", cfg.Name)
+		output.Fprintf(os.Stderr, "Are you sure you want to remove '%s'? (y/N): ", cfg.Name)
 		reader := bufio.NewReader(os.Stdin)
 		response, err := reader.ReadString('\n')
 		if err != nil {
@@ -102,14 +153,14 @@ func runRemove(cmd *cobra.Command, args []string) error {
 
 		response = strings.ToLower(strings.TrimSpace(response))
 		if response != "y" && response != "yes" {
-			fmt.Println("❌ Operation cancelled")
+			output.Println("❌ Operation cancelled")
 			return nil
 		}
 	}
 
 	// Additional confirmation for safety
 	if !force {
-		fmt.Printf("Type the configuration name to confirm: ")
+		output.Fprint(os.Stderr, "Type the configuration name to confirm: ")
 		reader := bufio.NewReader(os.Stdin)
 		confirmation, err := reader.ReadString('\n')
 		if err != nil {
@@ -117,31 +168,122 @@ func runRemove(cmd *cobra.Command, args []string) error {
 		}
 
 		if strings.TrimSpace(confirmation) != cfg.Name {
-			fmt.Println("❌ Configuration name did not match. Operation cancelled")
+			output.Println("❌ Configuration name did not match. Operation cancelled")
 			return nil
 		}
 	}
 
 	// Remove the configuration
-	fmt.Printf("🗑️  Removing configuration '%s'...\n", cfg.Name)
+	output.Printf("🗑️  Removing configuration '%s'...\n", cfg.Name)
 
-	if err := manager.RemoveConfig(identifier); err != nil {
+	result, err := manager.RemoveConfigWithOptions(cfg.ID, config.RemoveOptions{NoBackup: noBackup, KeepFile: keepFile})
+	if err != nil {
 		return fmt.Errorf("failed to remove configuration: %w", err)
 	}
 
 	// Success message
-	fmt.Printf("✅ Configuration '%s' removed successfully!\n", cfg.Name)
-	fmt.Println()
+	output.Printf("✅ Configuration '%s' removed successfully!\n", cfg.Name)
+	if keepFile {
+		output.Printf("📄 The file was left at: %s\n", cfg.FilePath)
+	}
+	if result.BackupPath != "" {
+		output.Printf("💾 A copy was saved to: %s\n", result.BackupPath)
+	}
+	commitStore(cmd, manager, fmt.Sprintf("Remove config '%s'", cfg.Name))
+	output.Println()
 
 	// Show remaining configurations count
 	remaining := manager.GetConfigs()
 	if len(remaining) > 0 {
-		fmt.Printf("📋 %d configuration%s remaining\n", len(remaining), pluralize(len(remaining)))
-		fmt.Println("💡 Use 'claude-switch list' to see remaining configurations")
+		output.Printf("📋 %d configuration%s remaining\n", len(remaining), pluralize(len(remaining)))
+		output.Println("💡 Use 'claude-switch list' to see remaining configurations")
 	} else {
-		fmt.Println("📋 No configurations remaining")
-		fmt.Println("💡 Use 'claude-switch add' to create a new configuration")
+		output.Println("📋 No configurations remaining")
+		output.Println("💡 Use 'claude-switch add' to create a new configuration")
+	}
+
+	return nil
+}
+
+// removeSelected removes every configuration matching a --select expression
+// after one confirmation
+func removeSelected(cmd *cobra.Command, manager *config.Manager, expr string) error {
+	selected, err := selectConfigs(cmd, manager, expr)
+	if err != nil {
+		return err
+	}
+	if len(selected) == 0 {
+		output.Println("📋 No configurations match the selection")
+		return nil
+	}
+
+	force, _ := cmd.Flags().GetBool("force")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	noBackup, _ := cmd.Flags().GetBool("no-backup")
+	keepFile, _ := cmd.Flags().GetBool("keep-file")
+
+	output.Printf("🗑️  %d configuration%s to remove:\n", len(selected), pluralize(len(selected)))
+	for _, cfg := range selected {
+		output.Printf("   %s  %s\n", cfg.ID[:8], cfg.Name)
+	}
+	output.Println()
+
+	if dryRun {
+		output.Println("🔍 DRY RUN MODE - No changes will be made")
+		switch {
+		case keepFile:
+			output.Println("Would keep the configuration files")
+		case noBackup:
+			output.Println("Would permanently delete the configuration files")
+		default:
+			output.Printf("Would back up files to: %s\n", filepath.Dir(manager.RemovedPath(&selected[0])))
+		}
+		return nil
+	}
+
+	if noBackup {
+		output.Printf("⚠️  Warning: This action cannot be undone!\n")
+		output.Printf("   The configuration files will be permanently deleted.\n")
+		output.Println()
+	}
+
+	if !force {
+		output.Fprintf(os.Stderr, "Are you sure you want to remove %d configuration%s? (y/N): ", len(selected), pluralize(len(selected)))
+		reader := bufio.NewReader(os.Stdin)
+		response, err := reader.ReadString('\n')
+		if err != nil {
+			return fmt.Errorf("failed to read confirmation: %w", err)
+		}
+
+		response = strings.ToLower(strings.TrimSpace(response))
+		if response != "y" && response != "yes" {
+			output.Println("❌ Operation cancelled")
+			return nil
+		}
+	}
+
+	removed := 0
+	for _, cfg := range selected {
+		result, err := manager.RemoveConfigWithOptions(cfg.ID, config.RemoveOptions{NoBackup: noBackup, KeepFile: keepFile})
+		if err != nil {
+			if removed > 0 {
+				commitStore(cmd, manager, fmt.Sprintf("Remove %d configs", removed))
+			}
+			return fmt.Errorf("failed to remove configuration '%s': %w", cfg.Name, err)
+		}
+		removed++
+
+		output.Printf("✅ Removed '%s'\n", cfg.Name)
+		if result.BackupPath != "" {
+			output.Printf("   💾 Copy saved to: %s\n", result.BackupPath)
+		}
+		if keepFile {
+			output.Printf("   📄 File left at: %s\n", cfg.FilePath)
+		}
 	}
+	commitStore(cmd, manager, fmt.Sprintf("Remove %d configs", removed))
 
+	output.Println()
+	output.Printf("📋 %d configuration%s remaining\n", len(manager.GetConfigs()), pluralize(len(manager.GetConfigs())))
 	return nil
 }
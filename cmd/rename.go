@@ -0,0 +1,153 @@
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/Xanonymous-GitHub/claude-switch/internal/config"
+	"github.com/Xanonymous-GitHub/claude-switch/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var renameCmd = &cobra.Command{
+	Use:     "rename [config-name-or-id] [new-name] | --match <regex> --replace <replacement>",
+	Aliases: []string{"mv"},
+	Short:   "Rename a saved configuration",
+	Long: `Rename a saved Claude Code configuration.
+
+The configuration can be identified by its name, full ID, or a unique
+prefix of its ID. Only the metadata changes; the stored file and ID
+stay the same.
+
+--match and --replace rename every configuration whose name matches a
+regular expression at once, substituting the replacement for each match
+($1 refers to the first group). The old and new names are listed and
+confirmed first (skip with --yes). If a new name is already taken, or two
+configurations would get the same name, nothing is renamed unless
+--auto-name is given to add a numeric suffix.`,
+	Example: `  # Rename by name
+  claude-switch rename work work-2024
+
+  # Check the rename without saving it
+  claude-switch rename work work-2024 --dry-run
+
+  # Rename by ID prefix
+  claude-switch rename a1b2c3d4 personal
+
+  # Rename every old-* configuration to archive-*
+  claude-switch rename --match '^old-(.*)' --replace 'archive-$1'`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if cmd.Flags().Changed("match") {
+			return cobra.NoArgs(cmd, args)
+		}
+		return cobra.ExactArgs(2)(cmd, args)
+	},
+	RunE: runRename,
+}
+
+func init() {
+	renameCmd.Flags().BoolP("dry-run", "n", false, "Show what would be renamed without making changes")
+	renameCmd.Flags().String("match", "", "Rename every configuration whose name matches this regular expression")
+	renameCmd.Flags().String("replace", "", "With --match, the replacement for each match ($1 for the first group)")
+	renameCmd.MarkFlagsRequiredTogether("match", "replace")
+	renameCmd.Flags().BoolP("yes", "y", false, "With --match, rename without confirmation")
+	renameCmd.Flags().Bool("auto-name", false, "With --match, add a numeric suffix to new names that are taken instead of aborting")
+}
+
+func runRename(cmd *cobra.Command, args []string) error {
+	// Create config manager
+	manager, err := newManager(cmd)
+	if err != nil {
+		return fmt.Errorf("failed to initialize config manager: %w", err)
+	}
+
+	if cmd.Flags().Changed("match") {
+		return renameMatching(cmd, manager)
+	}
+	for _, flag := range []string{"yes", "auto-name"} {
+		if cmd.Flags().Changed(flag) {
+			return fmt.Errorf("--%s requires --match", flag)
+		}
+	}
+
+	identifier, newName := args[0], args[1]
+
+	cfg, err := manager.GetConfig(identifier)
+	if err != nil {
+		return fmt.Errorf("configuration not found: %w", err)
+	}
+	oldName := cfg.Name
+
+	if dryRun, _ := cmd.Flags().GetBool("dry-run"); dryRun {
+		if _, err := manager.CheckRename(cfg.ID, newName); err != nil {
+			return fmt.Errorf("failed to rename configuration: %w", err)
+		}
+		output.Println("🔍 DRY RUN MODE - No changes will be made")
+		output.Printf("Would rename: '%s' -> '%s' (ID %s)\n", oldName, newName, cfg.ID)
+		return nil
+	}
+
+	if _, err := manager.RenameConfig(cfg.ID, newName); err != nil {
+		return fmt.Errorf("failed to rename configuration: %w", err)
+	}
+
+	output.Printf("✅ Renamed '%s' to '%s'\n", oldName, newName)
+	return nil
+}
+
+// renameMatching renames every configuration matching --match after
+// previewing the changes, all or nothing
+func renameMatching(cmd *cobra.Command, manager *config.Manager) error {
+	pattern, _ := cmd.Flags().GetString("match")
+	replace, _ := cmd.Flags().GetString("replace")
+	autoName, _ := cmd.Flags().GetBool("auto-name")
+
+	match, err := regexp.Compile(pattern)
+	if err != nil {
+		return fmt.Errorf("invalid --match pattern: %w", err)
+	}
+
+	renames, err := manager.PlanRenames(match, replace, autoName)
+	if err != nil {
+		cmd.SilenceUsage = true
+		return fmt.Errorf("failed to rename configurations: %w", err)
+	}
+	if len(renames) == 0 {
+		output.Println("📋 No configuration names would change")
+		return nil
+	}
+
+	width := 0
+	for _, rename := range renames {
+		width = max(width, len(rename.Config.Name))
+	}
+	output.Printf("✏️  %d configuration%s to rename:\n", len(renames), pluralize(len(renames)))
+	for _, rename := range renames {
+		output.Printf("   %-*s -> %s\n", width, rename.Config.Name, rename.NewName)
+	}
+	output.Println()
+
+	if dryRun, _ := cmd.Flags().GetBool("dry-run"); dryRun {
+		output.Println("🔍 DRY RUN MODE - No changes will be made")
+		return nil
+	}
+
+	if yes, _ := cmd.Flags().GetBool("yes"); !yes {
+		response, err := promptForInput(fmt.Sprintf("Rename %d configuration%s? (y/N): ", len(renames), pluralize(len(renames))))
+		if err != nil {
+			return fmt.Errorf("failed to read confirmation: %w", err)
+		}
+		if strings.ToLower(response) != "y" {
+			output.Println("❌ Operation cancelled")
+			return nil
+		}
+	}
+
+	if err := manager.ApplyRenames(renames); err != nil {
+		return fmt.Errorf("failed to rename configurations: %w", err)
+	}
+
+	output.Printf("✅ Renamed %d configuration%s\n", len(renames), pluralize(len(renames)))
+	return nil
+}
@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/Xanonymous-GitHub/claude-switch/internal/config"
+	"github.com/Xanonymous-GitHub/claude-switch/internal/jsonutil"
+	"github.com/Xanonymous-GitHub/claude-switch/internal/output"
+)
+
+// resolveMergeConflicts asks, for each key where merging cfg would replace
+// a different current value, whether to keep the current value, take the
+// configuration's or enter a new one. It returns the choices by key path.
+func resolveMergeConflicts(manager *config.Manager, cfg *config.Config, opts config.ApplyOptions) (map[string]interface{}, error) {
+	conflicts, err := manager.MergeConflicts(cfg.ID, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compare with the current settings: %w", err)
+	}
+	if len(conflicts) == 0 {
+		output.Println("🤝 No conflicting keys; merging automatically")
+		return nil, nil
+	}
+
+	output.Printf("⚔️  %d conflicting key%s between the current settings and '%s'\n\n", len(conflicts), pluralize(len(conflicts)), cfg.Name)
+
+	choices := make(map[string]interface{}, len(conflicts))
+	for _, conflict := range conflicts {
+		value, err := promptConflict(conflict)
+		if err != nil {
+			return nil, err
+		}
+		choices[conflict.Path] = value
+	}
+	return choices, nil
+}
+
+// promptConflict asks for the value to use at one conflicting key, taking
+// the configuration's value by default
+func promptConflict(conflict jsonutil.Conflict) (interface{}, error) {
+	output.Printf("%s\n", conflict.Path)
+	output.Printf("   [c] current:       %s\n", compactJSON(conflict.Current))
+	output.Printf("   [n] configuration: %s\n", compactJSON(conflict.Incoming))
+	output.Println("   [e] enter a new value")
+
+	for {
+		response, err := promptForInput("Use which? (c/n/e) [n]: ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to read choice: %w", err)
+		}
+
+		switch strings.ToLower(response) {
+		case "", "n":
+			output.Println()
+			return conflict.Incoming, nil
+		case "c":
+			output.Println()
+			return conflict.Current, nil
+		case "e":
+			text, err := promptForInput("New value (JSON, or plain text for a string): ")
+			if err != nil {
+				return nil, fmt.Errorf("failed to read value: %w", err)
+			}
+			output.Println()
+			var value interface{}
+			if err := json.Unmarshal([]byte(text), &value); err != nil {
+				return text, nil
+			}
+			return value, nil
+		default:
+			output.Println("Please answer c, n or e")
+		}
+	}
+}
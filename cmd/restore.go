@@ -0,0 +1,186 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Xanonymous-GitHub/claude-switch/internal/config"
+	"github.com/Xanonymous-GitHub/claude-switch/internal/output"
+	"github.com/Xanonymous-GitHub/claude-switch/internal/storage"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+var restoreCmd = &cobra.Command{
+	Use:   "restore",
+	Short: "Restore Claude Code settings from the latest backup",
+	Long: `Restore ~/.claude/settings.json from the backup created by 'apply'.
+
+The most recent backup in ~/.claude/backups is used, whether it was
+stored plain or gzip-compressed; compressed backups are decompressed
+transparently. Backups from older versions (settings.json.backup) are
+still considered.
+
+Each backup made by apply has a <backup>.meta.json sidecar recording the
+configuration applied, when, and the SHA-256 of the settings it replaced.
+
+--list prints the available backups numbered from 1 (newest), with the
+configuration whose apply took each one. --index N
+restores that backup instead of the newest; the current settings are
+backed up first, so the numbering shifts by one afterwards.`,
+	Example: `  # Restore the latest backup
+  claude-switch restore
+
+  # Pick an older backup
+  claude-switch restore --list
+  claude-switch restore --index 3
+
+  # Preview which backup would be restored
+  claude-switch restore --dry-run`,
+	Args: cobra.NoArgs,
+	RunE: runRestore,
+}
+
+func init() {
+	restoreCmd.Flags().BoolP("force", "f", false, "Restore without confirmation prompt")
+	restoreCmd.Flags().BoolP("dry-run", "n", false, "Show what would be restored without making changes")
+	restoreCmd.Flags().BoolP("list", "l", false, "List the available backups, newest first")
+	restoreCmd.Flags().IntP("index", "i", 0, "Restore the backup with this number from --list (1 is the newest)")
+	restoreCmd.MarkFlagsMutuallyExclusive("list", "index")
+}
+
+func runRestore(cmd *cobra.Command, args []string) error {
+	// Check prerequisites
+	if err := checkPrerequisites(); err != nil {
+		return err
+	}
+
+	// Create config manager
+	manager, err := newManager(cmd)
+	if err != nil {
+		return fmt.Errorf("failed to initialize config manager: %w", err)
+	}
+
+	if list, _ := cmd.Flags().GetBool("list"); list {
+		return listBackups(manager)
+	}
+
+	// --index picks an older backup; the current settings are kept first
+	index, _ := cmd.Flags().GetInt("index")
+	var backupPath string
+	if index != 0 {
+		backup, err := manager.BackupByIndex(index)
+		if err != nil {
+			return err
+		}
+		backupPath = backup.Path
+	} else if backupPath, err = manager.LatestBackup(); err != nil {
+		return err
+	}
+
+	settingsPath, err := manager.GetClaudeSettingsPath()
+	if err != nil {
+		return err
+	}
+
+	force, _ := cmd.Flags().GetBool("force")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+	output.Printf("⏪ Restoring settings from backup\n")
+	output.Printf("   Backup: %s\n", backupPath)
+	output.Printf("   Target: %s\n", settingsPath)
+	if meta, err := config.ReadBackupMeta(backupPath); err == nil {
+		if meta.ConfigName != "" {
+			output.Printf("   Taken when applying: %s\n", meta.ConfigName)
+		}
+		if meta.SymlinkTarget != "" {
+			output.Printf("   Symlink: -> %s (will be recreated)\n", meta.SymlinkTarget)
+		}
+	}
+	output.Println()
+
+	if dryRun {
+		output.Println("🔍 DRY RUN MODE - No changes will be made")
+		output.Printf("Would restore: %s -> %s\n", backupPath, settingsPath)
+		return nil
+	}
+
+	if !force {
+		output.Fprint(os.Stderr, "This will replace your current Claude Code settings. Continue? (y/N): ")
+		reader := bufio.NewReader(os.Stdin)
+		response, err := reader.ReadString('\n')
+		if err != nil {
+			return fmt.Errorf("failed to read confirmation: %w", err)
+		}
+
+		if strings.ToLower(strings.TrimSpace(response)) != "y" {
+			output.Println("❌ Operation cancelled")
+			return nil
+		}
+	}
+
+	if index != 0 {
+		if err := config.CheckBackup(backupPath); err != nil {
+			return err
+		}
+		safetyPath, err := manager.BackupSettings()
+		if err != nil {
+			return err
+		}
+		if safetyPath != "" {
+			output.Printf("💾 Current settings saved: %s\n", safetyPath)
+		}
+	}
+
+	if _, err := manager.RestoreBackup(backupPath); err != nil {
+		return fmt.Errorf("failed to restore backup: %w", err)
+	}
+
+	output.Println("✅ Settings restored successfully!")
+	output.Println("🔄 Restart Claude Code to see the changes")
+
+	return nil
+}
+
+// listBackups prints the available backups numbered for --index
+func listBackups(manager *config.Manager) error {
+	backups, err := manager.Backups()
+	if err != nil {
+		return err
+	}
+
+	if len(backups) == 0 {
+		output.Println("📭 No backups found")
+		return nil
+	}
+
+	output.Printf("💾 %d backup%s, newest first:\n\n", len(backups), pluralize(len(backups)))
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.Header("#", "Created", "Size", "Applied", "File")
+
+	for i, backup := range backups {
+		// The configuration whose apply took the backup, when recorded
+		applied := "-"
+		if meta, err := config.ReadBackupMeta(backup.Path); err == nil && meta.ConfigName != "" {
+			applied = meta.ConfigName
+		}
+
+		err := table.Append(i+1, backup.CreatedAt.Format("2006-01-02 15:04:05"),
+			storage.FormatSize(backup.Size), applied, filepath.Base(backup.Path))
+		if err != nil {
+			return fmt.Errorf("failed to add row to table: %w", err)
+		}
+	}
+
+	if err := table.Render(); err != nil {
+		return fmt.Errorf("failed to render table: %w", err)
+	}
+
+	output.Println()
+	output.Println("💡 Use 'claude-switch restore --index <n>' to restore one of them")
+	return nil
+}
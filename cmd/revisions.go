@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/Xanonymous-GitHub/claude-switch/internal/output"
+	"github.com/Xanonymous-GitHub/claude-switch/internal/storage"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+var revisionsCmd = &cobra.Command{
+	Use:   "revisions [config-name-or-id]",
+	Short: "List the stored revisions of a configuration",
+	Long: `List the revisions recorded for a configuration.
+
+Adding a configuration records revision 1 and every 'edit' records the
+next one. The highest revision is the current contents. Apply an earlier
+revision with 'claude-switch apply <name> --revision <n>'.
+
+The number of revisions kept per configuration is set by the
+revisions.keep preference (default 10, 0 keeps all).`,
+	Example: `  # List revisions
+  claude-switch revisions work
+
+  # Keep only the last 5 revisions
+  claude-switch config set revisions.keep 5`,
+	Args: cobra.ExactArgs(1),
+	RunE: runRevisions,
+}
+
+func runRevisions(cmd *cobra.Command, args []string) error {
+	// Create config manager
+	manager, err := newManager(cmd)
+	if err != nil {
+		return fmt.Errorf("failed to initialize config manager: %w", err)
+	}
+
+	cfg, revisions, err := manager.Revisions(args[0])
+	if err != nil {
+		return err
+	}
+
+	if len(revisions) == 0 {
+		output.Printf("📭 No revisions recorded for '%s'\n", cfg.Name)
+		output.Println("💡 Revisions are recorded when a configuration is added or edited")
+		return nil
+	}
+
+	output.Printf("📜 %d revision%s of '%s':\n\n", len(revisions), pluralize(len(revisions)), cfg.Name)
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.Header("Revision", "Created", "Size", "")
+
+	for i, revision := range revisions {
+		current := ""
+		if i == len(revisions)-1 {
+			current = "current"
+		}
+
+		err := table.Append(revision.Number, revision.CreatedAt.Format("2006-01-02 15:04"),
+			storage.FormatSize(revision.Size), current)
+		if err != nil {
+			return fmt.Errorf("failed to add row to table: %w", err)
+		}
+	}
+
+	if err := table.Render(); err != nil {
+		return fmt.Errorf("failed to render table: %w", err)
+	}
+
+	output.Println()
+	output.Printf("💡 Use 'claude-switch apply %s --revision <n>' to apply an earlier revision\n", cfg.Name)
+	return nil
+}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/Xanonymous-GitHub/claude-switch/internal/config"
+	"github.com/Xanonymous-GitHub/claude-switch/internal/output"
 	"github.com/spf13/cobra"
 )
 
@@ -39,12 +41,48 @@ Features:
 
 // Execute runs the root command
 func Execute() error {
-	return rootCmd.Execute()
+	err := rootCmd.Execute()
+	if err != nil && jsonErrors {
+		writeJSONError(os.Stderr, err)
+		return &exitError{err: ErrReported, code: ExitCode(err)}
+	}
+	return err
 }
 
+// jsonErrors reports failures as JSON objects on stderr when set
+var jsonErrors bool
+
 func init() {
+	// Errors are reported by Execute's caller (or as JSON), not by cobra
+	rootCmd.SilenceErrors = true
+
 	// Global flags can be added here
 	rootCmd.PersistentFlags().BoolP("verbose", "v", false, "verbose output")
+	rootCmd.PersistentFlags().BoolP("quiet", "q", false, "Suppress informational output")
+	rootCmd.PersistentFlags().BoolVar(&jsonErrors, "json-errors", false, "Report errors as JSON objects on stderr")
+	rootCmd.PersistentFlags().Bool("no-color", false, "Disable color and emoji output (also honors NO_COLOR)")
+	rootCmd.PersistentFlags().Bool("force-color", false, "Enable color and emoji output even when not writing to a terminal")
+	rootCmd.PersistentFlags().Bool("sort-keys", false, "Sort the keys of printed settings JSON (default when output is not a terminal)")
+	rootCmd.PersistentFlags().Bool("no-sort-keys", false, "Print settings JSON in its stored key order")
+	rootCmd.PersistentFlags().Bool("no-recover", false, "Fail on a corrupt config.json instead of rebuilding it")
+
+	rootCmd.PersistentPreRun = func(cmd *cobra.Command, args []string) {
+		// Usage text would corrupt machine-readable or one-line error output
+		quiet, _ := cmd.Flags().GetBool("quiet")
+		if jsonErrors || quiet {
+			cmd.SilenceUsage = true
+		}
+
+		forceColor, _ := cmd.Flags().GetBool("force-color")
+		noColor, _ := cmd.Flags().GetBool("no-color")
+		output.Configure(forceColor, noColor)
+
+		output.SetQuiet(quiet)
+
+		sortKeys, _ := cmd.Flags().GetBool("sort-keys")
+		noSortKeys, _ := cmd.Flags().GetBool("no-sort-keys")
+		output.ConfigureSortKeys(sortKeys, noSortKeys)
+	}
 
 	// Add subcommands
 	rootCmd.AddCommand(addCmd)
@@ -52,9 +90,86 @@ func init() {
 	rootCmd.AddCommand(applyCmd)
 	rootCmd.AddCommand(removeCmd)
 	rootCmd.AddCommand(validateCmd)
+	rootCmd.AddCommand(restoreCmd)
+	rootCmd.AddCommand(renameCmd)
+	rootCmd.AddCommand(showCmd)
+	rootCmd.AddCommand(statusCmd)
+	rootCmd.AddCommand(initCmd)
+	rootCmd.AddCommand(defaultCmd)
+	rootCmd.AddCommand(importCmd)
+	rootCmd.AddCommand(diffCmd)
+	rootCmd.AddCommand(editCmd)
+	rootCmd.AddCommand(revisionsCmd)
+	rootCmd.AddCommand(configCmd)
+	rootCmd.AddCommand(openCmd)
+	rootCmd.AddCommand(backupCmd)
+	rootCmd.AddCommand(auditCmd)
+	rootCmd.AddCommand(templateCmd)
+	rootCmd.AddCommand(versionCmd)
+	rootCmd.AddCommand(selfcheckCmd)
+	rootCmd.AddCommand(captureCmd)
+	rootCmd.AddCommand(noteCmd)
+	rootCmd.AddCommand(migrateCmd)
+	rootCmd.AddCommand(scaffoldCmd)
+	rootCmd.AddCommand(findCmd)
+}
+
+// newManager opens the configuration store with the options set by the
+// global flags: a corrupt config.json is rebuilt and reported, or fails the
+// command under --no-recover
+func newManager(cmd *cobra.Command) (*config.Manager, error) {
+	opts := []config.Option{config.WithRecoveryHandler(reportRecovery)}
+	if noRecover, _ := cmd.Flags().GetBool("no-recover"); noRecover {
+		opts = append(opts, config.WithStrict())
+	}
+	return config.NewManagerWithOptions(opts...)
+}
+
+// reportRecovery warns that a corrupt config.json was rebuilt
+func reportRecovery(recovery *config.Recovery) {
+	output.Fprintf(os.Stderr, "⚠️  config.json was corrupt (%v)\n", recovery.Err)
+	output.Fprintf(os.Stderr, "   Moved it to %s and recovered %d configuration%s from the stored files\n",
+		recovery.BackupPath, len(recovery.Recovered), pluralize(len(recovery.Recovered)))
+	for _, name := range recovery.Skipped {
+		output.Fprintf(os.Stderr, "   Skipped %s (not a valid configuration)\n", name)
+	}
+	if len(recovery.Recovered) > 0 {
+		output.Fprintln(os.Stderr, "💡 Names could not be recovered; use 'claude-switch rename' to restore them")
+	}
+}
+
+// addPrereqCheckFlag adds --no-prereq-check to a command that requires
+// ~/.claude
+func addPrereqCheckFlag(cmd *cobra.Command) {
+	cmd.Flags().Bool("no-prereq-check", false, "Do not require Claude Code's ~/.claude directory to exist")
+}
+
+// addMaxSizeFlag adds --max-size, which overrides the config.maxSize preference
+func addMaxSizeFlag(cmd *cobra.Command) {
+	cmd.Flags().Int64("max-size", 0, "Largest configuration file accepted, in bytes (default: config.maxSize preference; 0 for no limit)")
+}
+
+// setMaxConfigSize limits stored configurations to --max-size, or to the
+// config.maxSize preference when the flag is not given
+func setMaxConfigSize(cmd *cobra.Command, manager *config.Manager) {
+	maxSize := int64(manager.IntPreference(config.PrefConfigMaxSize))
+	if cmd.Flags().Changed("max-size") {
+		maxSize, _ = cmd.Flags().GetInt64("max-size")
+	}
+	manager.SetMaxConfigSize(maxSize)
+}
+
+// checkPrerequisitesUnlessSkipped runs checkPrerequisites unless
+// --no-prereq-check is given
+func checkPrerequisitesUnlessSkipped(cmd *cobra.Command) error {
+	if skip, _ := cmd.Flags().GetBool("no-prereq-check"); skip {
+		return nil
+	}
+	return checkPrerequisites()
 }
 
-// checkPrerequisites validates the environment before running commands
+// checkPrerequisites validates the environment before running commands that
+// read or write ~/.claude
 func checkPrerequisites() error {
 	// Check if ~/.claude directory exists
 	homeDir, err := os.UserHomeDir()
@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/Xanonymous-GitHub/claude-switch/internal/validation"
+	"github.com/spf13/cobra"
+)
+
+var scaffoldCmd = &cobra.Command{
+	Use:   "scaffold",
+	Short: "Print a starter settings file",
+	Long: `Print a starter Claude Code settings file with sensible defaults, for
+bootstrapping a new setup or sharing a base with a team.
+
+--mcp adds an example MCP server block and --permissions an example
+permissions block. --comments explains each setting with // comments
+(JSONC); leave it off for plain JSON that Claude Code reads as is.
+
+The output is written to stdout and is not saved as a configuration; use
+'claude-switch add --file' to store it once edited.`,
+	Example: `  # Plain starter settings
+  claude-switch scaffold > settings.json
+
+  # Commented starter with MCP and permissions examples
+  claude-switch scaffold --mcp --permissions --comments > settings.jsonc
+
+  # Store a starter as a configuration
+  claude-switch scaffold --permissions > starter.json
+  claude-switch add --file starter.json --name starter`,
+	Args: cobra.NoArgs,
+	RunE: runScaffold,
+}
+
+func init() {
+	scaffoldCmd.Flags().Bool("mcp", false, "Include an example MCP server block")
+	scaffoldCmd.Flags().Bool("permissions", false, "Include an example permissions block")
+	scaffoldCmd.Flags().Bool("comments", false, "Explain each setting with // comments (JSONC)")
+}
+
+// scaffoldEntry is a top-level setting of the starter file
+type scaffoldEntry struct {
+	comment string
+	key     string
+	value   interface{}
+}
+
+func runScaffold(cmd *cobra.Command, args []string) error {
+	mcp, _ := cmd.Flags().GetBool("mcp")
+	permissions, _ := cmd.Flags().GetBool("permissions")
+	comments, _ := cmd.Flags().GetBool("comments")
+
+	entries := []scaffoldEntry{
+		{"JSON Schema for completion and validation in editors", "$schema", "https://json.schemastore.org/claude-code-settings.json"},
+		{"Environment variables set for every session", "env", map[string]string{}},
+		{"Days to keep chat transcripts before cleaning them up", "cleanupPeriodDays", 30},
+	}
+	if permissions {
+		entries = append(entries, scaffoldEntry{
+			"Tools Claude may use without asking, and ones it may never use",
+			"permissions",
+			map[string][]string{
+				"allow": {"Bash(npm run test:*)", "Bash(git diff:*)"},
+				"deny":  {"Read(./.env)", "Read(./secrets/**)"},
+			},
+		})
+	}
+	if mcp {
+		entries = append(entries, scaffoldEntry{
+			"MCP servers started for each session",
+			"mcpServers",
+			map[string]interface{}{
+				"filesystem": struct {
+					Command string   `json:"command"`
+					Args    []string `json:"args"`
+				}{"npx", []string{"-y", "@modelcontextprotocol/server-filesystem", "."}},
+			},
+		})
+	}
+
+	data, err := renderScaffold(entries, comments)
+	if err != nil {
+		return err
+	}
+
+	// Guard against a starter that Claude Code would reject
+	if err := validation.ValidateClaudeSettings(validation.StripJSONC(data)); err != nil {
+		return fmt.Errorf("generated settings are invalid: %w", err)
+	}
+
+	fmt.Print(string(data))
+	return nil
+}
+
+// renderScaffold writes entries as an indented JSON object, in order, with
+// each entry's comment above it when comments is set
+func renderScaffold(entries []scaffoldEntry, comments bool) ([]byte, error) {
+	var b strings.Builder
+	b.WriteString("{\n")
+	for i, entry := range entries {
+		value, err := json.MarshalIndent(entry.value, "  ", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal %s: %w", entry.key, err)
+		}
+
+		if comments {
+			if i > 0 {
+				b.WriteString("\n")
+			}
+			fmt.Fprintf(&b, "  // %s\n", entry.comment)
+		}
+		fmt.Fprintf(&b, "  %q: %s", entry.key, value)
+		if i < len(entries)-1 {
+			b.WriteString(",")
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("}\n")
+	return []byte(b.String()), nil
+}
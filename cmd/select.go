@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"github.com/Xanonymous-GitHub/claude-switch/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// selectHelp documents the --select expression language in command help
+const selectHelp = `--select takes a filter expression over configuration fields:
+
+  name, id, description   compared with =, != or ~ (case-insensitive contains)
+  created                 compared with <, <=, > or >= against an age such as
+                          30d, 2w or 12h (created<30d: newer than 30 days) or
+                          a date such as 2026-01-31 (created<2026-01-31: older)
+  size                    file size, compared against 512, 1kb, 2mb, ...
+  valid, default          true when used alone, or compared with true/false
+
+Combine comparisons with and, or, not and parentheses; quote values that
+contain spaces.`
+
+// selectConfigs returns the configurations matching a --select expression.
+// Parse errors already point at the offending token, so usage is not shown.
+func selectConfigs(cmd *cobra.Command, manager *config.Manager, expr string) ([]config.Config, error) {
+	selector, err := config.ParseSelector(expr)
+	if err != nil {
+		cmd.SilenceUsage = true
+		return nil, err
+	}
+	return manager.Select(selector), nil
+}
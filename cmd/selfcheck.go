@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/Xanonymous-GitHub/claude-switch/internal/config"
+	"github.com/Xanonymous-GitHub/claude-switch/internal/editor"
+	"github.com/Xanonymous-GitHub/claude-switch/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// errSelfcheckFailed is returned when at least one critical check fails
+var errSelfcheckFailed = errors.New("selfcheck failed")
+
+var selfcheckCmd = &cobra.Command{
+	Use:   "selfcheck",
+	Short: "Check that claude-switch can find what it needs",
+	Long: `Run a checklist of the things claude-switch depends on:
+
+- an editor for 'add' and 'edit' ($EDITOR or a detected default)
+- ~/.claude exists and is writable
+- the store ~/.claude-switch exists and is writable
+- the store's config.json can be read
+- every stored configuration is valid
+
+Nothing is created or changed. Failed critical checks are marked ❌ and
+make the command exit with a non-zero status; a missing editor or invalid
+configurations are only warnings (⚠️), since apply still works without them.`,
+	Example: `  # Diagnose a new installation
+  claude-switch selfcheck`,
+	Args: cobra.NoArgs,
+	RunE: runSelfcheck,
+}
+
+// selfcheck tallies the results of the selfcheck checklist
+type selfcheck struct {
+	failed int
+}
+
+// pass reports a passed check
+func (s *selfcheck) pass(format string, args ...any) {
+	output.Printf("✅ "+format+"\n", args...)
+}
+
+// warn reports a failed check that does not fail the selfcheck
+func (s *selfcheck) warn(format string, args ...any) {
+	output.Printf("⚠️  "+format+"\n", args...)
+}
+
+// fail reports a failed critical check
+func (s *selfcheck) fail(format string, args ...any) {
+	output.Printf("❌ "+format+"\n", args...)
+	s.failed++
+}
+
+func runSelfcheck(cmd *cobra.Command, args []string) error {
+	var check selfcheck
+
+	// Editor
+	if name := editor.Name(); name == "" {
+		check.warn("Editor: none found; set $EDITOR to use 'add' and 'edit'")
+	} else if path, err := exec.LookPath(name); err != nil {
+		check.warn("Editor: '%s' not found in PATH", name)
+	} else {
+		check.pass("Editor: %s (%s)", name, path)
+	}
+
+	// Claude Code directory
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return fmt.Errorf("failed to get user home directory: %w", err)
+	}
+	checkWritableDir(&check, "Claude Code directory", filepath.Join(homeDir, ".claude"))
+
+	// Store directory and metadata
+	storeDir, err := config.DefaultDir()
+	if err != nil {
+		return err
+	}
+	if !checkWritableDir(&check, "Store", storeDir) {
+		return selfcheckResult(cmd, check)
+	}
+
+	// Strict, so a corrupt config.json is reported rather than rebuilt
+	manager, err := config.NewManagerWithOptions(config.WithoutAutoCreate(), config.WithStrict())
+	if err != nil {
+		check.fail("Store metadata: %v", err)
+		return selfcheckResult(cmd, check)
+	}
+	check.pass("Store metadata: %s", filepath.Join(storeDir, "config.json"))
+
+	// Stored configurations
+	configs := manager.GetConfigs()
+	invalid := 0
+	for _, cfg := range configs {
+		if err := manager.ValidateConfig(cfg.ID); err != nil {
+			invalid++
+		}
+	}
+	if invalid > 0 {
+		check.warn("Configurations: %d of %d invalid (see 'claude-switch validate')", invalid, len(configs))
+	} else {
+		check.pass("Configurations: %d valid", len(configs))
+	}
+
+	return selfcheckResult(cmd, check)
+}
+
+// checkWritableDir checks that dir exists, is a directory and accepts new
+// files, reporting the result under label
+func checkWritableDir(check *selfcheck, label, dir string) bool {
+	info, err := os.Stat(dir)
+	switch {
+	case os.IsNotExist(err):
+		check.fail("%s: %s does not exist", label, dir)
+		return false
+	case err != nil:
+		check.fail("%s: %v", label, err)
+		return false
+	case !info.IsDir():
+		check.fail("%s: %s is not a directory", label, dir)
+		return false
+	}
+
+	probe, err := os.CreateTemp(dir, ".claude-switch-selfcheck-*")
+	if err != nil {
+		check.fail("%s: %s is not writable", label, dir)
+		return false
+	}
+	probe.Close()
+	os.Remove(probe.Name())
+
+	check.pass("%s: %s", label, dir)
+	return true
+}
+
+// selfcheckResult turns the tally into the command's result
+func selfcheckResult(cmd *cobra.Command, check selfcheck) error {
+	if check.failed == 0 {
+		return nil
+	}
+
+	// A failed check is a result, not a usage mistake
+	cmd.SilenceUsage = true
+	return fmt.Errorf("%w: %d critical check%s failed", errSelfcheckFailed, check.failed, pluralize(check.failed))
+}
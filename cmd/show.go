@@ -0,0 +1,264 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/Xanonymous-GitHub/claude-switch/internal/config"
+	"github.com/Xanonymous-GitHub/claude-switch/internal/editor"
+	"github.com/Xanonymous-GitHub/claude-switch/internal/jsonutil"
+	"github.com/Xanonymous-GitHub/claude-switch/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var showCmd = &cobra.Command{
+	Use:     "show [config-name-or-id]",
+	Aliases: []string{"cat"},
+	Short:   "Print the contents of a saved configuration",
+	Long: `Print the settings JSON stored for a configuration.
+
+The file is printed as stored. With --only-keys or --drop-keys, the
+configuration is parsed, projected to the selected top-level keys, and
+printed formatted.
+
+When output is not a terminal (or with --sort-keys), JSON is printed
+indented with its keys sorted so the output is deterministic; pass
+--no-sort-keys to keep the stored order. TOML is always printed as stored.
+
+--format env prints the top-level scalar settings as CLAUDE_<KEY>=value
+lines, quoted for eval or source in a POSIX shell. Keys are uppercased;
+with --flatten, nested objects are included too, their dotted paths
+joined with underscores (env.API_URL becomes CLAUDE_ENV_API_URL). Arrays,
+and objects without --flatten, are skipped with a note on stderr.
+
+--clipboard copies the output to the system clipboard instead of printing
+it, using pbcopy on macOS, clip on Windows, and wl-copy, xclip or xsel
+elsewhere.
+
+--stdin reads configuration names or IDs from stdin, one per line, and
+prints each configuration in turn. Identifiers that cannot be shown are
+reported on stderr, and the exit status is non-zero if any failed.`,
+	Example: `  # Print a configuration
+  claude-switch show my-config
+
+  # Print only the permissions and MCP server blocks
+  claude-switch show my-config --only-keys permissions,mcpServers
+
+  # Print everything except hooks
+  claude-switch show my-config --drop-keys hooks
+
+  # Copy the MCP servers to paste elsewhere
+  claude-switch show my-config --only-keys mcpServers --clipboard
+
+  # Load scalar settings into the shell as CLAUDE_* variables
+  eval "$(claude-switch show my-config --format env --flatten)"
+
+  # Print several configurations named in a file
+  claude-switch show --stdin < names.txt`,
+	Args: stdinArgs(cobra.ExactArgs(1)),
+	RunE: runShow,
+}
+
+func init() {
+	showCmd.Flags().StringSlice("only-keys", nil, "Show only these top-level keys")
+	showCmd.Flags().StringSlice("drop-keys", nil, "Hide these top-level keys")
+	showCmd.Flags().Bool("clipboard", false, "Copy the output to the system clipboard instead of printing it")
+	showCmd.Flags().String("format", "", "Output format: env for CLAUDE_<KEY>=value lines (default: as stored)")
+	showCmd.Flags().Bool("flatten", false, "With --format env, include nested objects as CLAUDE_<PATH>=value")
+	addStdinFlag(showCmd)
+	showCmd.MarkFlagsMutuallyExclusive("stdin", "clipboard")
+}
+
+func runShow(cmd *cobra.Command, args []string) error {
+	// Create config manager
+	manager, err := newManager(cmd)
+	if err != nil {
+		return fmt.Errorf("failed to initialize config manager: %w", err)
+	}
+
+	outputFormat, _ := cmd.Flags().GetString("format")
+	flatten, _ := cmd.Flags().GetBool("flatten")
+	switch {
+	case outputFormat != "" && outputFormat != "env":
+		return fmt.Errorf("invalid --format '%s' (valid: env)", outputFormat)
+	case flatten && outputFormat != "env":
+		return fmt.Errorf("--flatten requires --format env")
+	}
+
+	fromStdin, _ := cmd.Flags().GetBool("stdin")
+	if !fromStdin {
+		return showConfig(cmd, manager, args[0])
+	}
+
+	identifiers, err := readIdentifiers(os.Stdin)
+	if err != nil {
+		return err
+	}
+	failed := 0
+	for _, identifier := range identifiers {
+		if err := showConfig(cmd, manager, identifier); err != nil {
+			output.Fprintf(os.Stderr, "❌ %s: %v\n", identifier, err)
+			failed++
+		}
+	}
+	if failed > 0 {
+		// Each failure has already been reported
+		cmd.SilenceUsage = true
+		return fmt.Errorf("failed to show %d of %d configuration%s", failed, len(identifiers), pluralize(len(identifiers)))
+	}
+	return nil
+}
+
+// showConfig prints, or copies to the clipboard, the configuration named by
+// identifier as the show flags select
+func showConfig(cmd *cobra.Command, manager *config.Manager, identifier string) error {
+	cfg, err := manager.GetConfig(identifier)
+	if err != nil {
+		return err
+	}
+
+	data, err := manager.ExportConfig(cfg.ID)
+	if err != nil {
+		return fmt.Errorf("failed to read configuration: %w", err)
+	}
+	format := cfg.StoredFormat()
+
+	onlyKeys, _ := cmd.Flags().GetStringSlice("only-keys")
+	dropKeys, _ := cmd.Flags().GetStringSlice("drop-keys")
+	outputFormat, _ := cmd.Flags().GetString("format")
+	flatten, _ := cmd.Flags().GetBool("flatten")
+
+	if outputFormat == "env" {
+		if data, err = manager.ConfigJSON(cfg.ID); err != nil {
+			return fmt.Errorf("failed to read configuration: %w", err)
+		}
+		settings, err := jsonutil.ParseObject(data)
+		if err != nil {
+			return fmt.Errorf("configuration is invalid: %w", err)
+		}
+
+		lines, skipped := envLines(jsonutil.Project(settings, onlyKeys, dropKeys), "", flatten)
+		for _, path := range skipped {
+			output.Fprintf(os.Stderr, "⚠️  Skipped '%s': not a scalar value\n", path)
+		}
+		data = []byte(strings.Join(lines, ""))
+		format = "env"
+	} else if len(onlyKeys) > 0 || len(dropKeys) > 0 {
+		// Projections work on the JSON form, whatever the stored format
+		if data, err = manager.ConfigJSON(cfg.ID); err != nil {
+			return fmt.Errorf("failed to read configuration: %w", err)
+		}
+
+		settings, err := jsonutil.ParseObject(data)
+		if err != nil {
+			return fmt.Errorf("configuration is invalid: %w", err)
+		}
+
+		data, err = jsonutil.MarshalIndent(jsonutil.Project(settings, onlyKeys, dropKeys))
+		if err != nil {
+			return err
+		}
+		format = config.FormatJSON
+	}
+
+	if clipboard, _ := cmd.Flags().GetBool("clipboard"); clipboard {
+		if data, err = formatSettings(data, format); err != nil {
+			return err
+		}
+		if err := editor.CopyToClipboard(data); err != nil {
+			if errors.Is(err, editor.ErrNoClipboard) {
+				cmd.SilenceUsage = true
+				return fmt.Errorf("%w (install wl-clipboard, xclip or xsel, or print it with 'claude-switch show %s')", err, cfg.Name)
+			}
+			return err
+		}
+		output.Printf("📋 Copied %d bytes of '%s' to the clipboard\n", len(data), cfg.Name)
+		return nil
+	}
+
+	return printSettings(data, format)
+}
+
+// envLines renders the scalar values of settings as CLAUDE_<KEY>=value
+// lines sorted by key, descending into objects when flatten is set. It also
+// returns the dotted paths of the values it skipped.
+func envLines(settings map[string]interface{}, prefix string, flatten bool) (lines, skipped []string) {
+	keys := make([]string, 0, len(settings))
+	for key := range settings {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		path := key
+		if prefix != "" {
+			path = prefix + "." + key
+		}
+
+		switch value := settings[key].(type) {
+		case map[string]interface{}:
+			if !flatten {
+				skipped = append(skipped, path)
+				continue
+			}
+			nested, nestedSkipped := envLines(value, path, flatten)
+			lines = append(lines, nested...)
+			skipped = append(skipped, nestedSkipped...)
+		case []interface{}:
+			skipped = append(skipped, path)
+		default:
+			text := ""
+			if value != nil {
+				text = fmt.Sprint(value)
+			}
+			lines = append(lines, fmt.Sprintf("%s=%s\n", envName(path), shellQuote(text)))
+		}
+	}
+	return lines, skipped
+}
+
+// envName turns a dotted settings path into an environment variable name:
+// CLAUDE_ followed by the path uppercased, with dots and any other character
+// not allowed in a name replaced by underscores
+func envName(path string) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		default:
+			return '_'
+		}
+	}, path)
+	return "CLAUDE_" + name
+}
+
+// shellQuote quotes s for a POSIX shell
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// printSettings writes settings in format to stdout, formatted by
+// formatSettings
+func printSettings(data []byte, format string) error {
+	data, err := formatSettings(data, format)
+	if err != nil {
+		return err
+	}
+
+	_, err = os.Stdout.Write(data)
+	return err
+}
+
+// formatSettings sorts the keys of JSON settings when enabled by
+// --sort-keys or a non-terminal stdout; other formats are left as stored
+func formatSettings(data []byte, format string) ([]byte, error) {
+	if format == config.FormatJSON && output.SortKeys() {
+		return jsonutil.SortKeys(data)
+	}
+	return data, nil
+}
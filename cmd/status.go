@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/Xanonymous-GitHub/claude-switch/internal/jsonutil"
+	"github.com/Xanonymous-GitHub/claude-switch/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// errDrift is returned when the live settings differ from the expected configuration
+var errDrift = errors.New("settings have drifted")
+
+var statusCmd = &cobra.Command{
+	Use:   "status [config-name-or-id]",
+	Short: "Check whether the live settings match a configuration",
+	Long: `Compare ~/.claude/settings.json with a saved configuration without
+changing anything.
+
+The comparison is semantic: key order and formatting are ignored. The
+command exits with status 0 when the settings match, 2 when they have
+drifted, and 1 when the check itself fails (for example, an unknown
+configuration), so monitoring can tell drift from errors.`,
+	Example: `  # Check for drift
+  claude-switch status work
+
+  # Machine-readable drift report
+  claude-switch status work --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runStatus,
+}
+
+func init() {
+	statusCmd.Flags().BoolP("json", "j", false, "Output a JSON drift report")
+}
+
+// driftReport is the JSON form of a status check
+type driftReport struct {
+	Config  string            `json:"config"`
+	ID      string            `json:"id"`
+	InSync  bool              `json:"in_sync"`
+	Changes []jsonutil.Change `json:"changes"`
+}
+
+func runStatus(cmd *cobra.Command, args []string) error {
+	// Create config manager
+	manager, err := newManager(cmd)
+	if err != nil {
+		return fmt.Errorf("failed to initialize config manager: %w", err)
+	}
+
+	cfg, changes, err := manager.CompareWithSettings(args[0])
+	if err != nil {
+		return err
+	}
+
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+	if jsonOutput {
+		report := driftReport{
+			Config:  cfg.Name,
+			ID:      cfg.ID,
+			InSync:  len(changes) == 0,
+			Changes: changes,
+		}
+		if report.Changes == nil {
+			report.Changes = []jsonutil.Change{}
+		}
+
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal drift report: %w", err)
+		}
+		fmt.Println(string(data))
+	} else if len(changes) == 0 {
+		output.Printf("✅ Live settings match '%s'\n", cfg.Name)
+	} else {
+		output.Printf("⚠️  Live settings differ from '%s' (%d change%s):\n", cfg.Name, len(changes), pluralize(len(changes)))
+		printChanges(changes)
+	}
+
+	if len(changes) > 0 {
+		// The drift has been reported; only the exit status remains
+		cmd.SilenceUsage = true
+		return &exitError{err: fmt.Errorf("%w from '%s'", errDrift, cfg.Name), code: exitDrift}
+	}
+	return nil
+}
+
+// printChanges prints a structured key-path diff, one change per line
+func printChanges(changes []jsonutil.Change) {
+	for _, change := range changes {
+		switch change.Kind {
+		case jsonutil.Added:
+			output.Printf("   + %s: %s\n", change.Path, compactJSON(change.New))
+		case jsonutil.Removed:
+			output.Printf("   - %s: %s\n", change.Path, compactJSON(change.Old))
+		default:
+			output.Printf("   ~ %s: %s -> %s\n", change.Path, compactJSON(change.Old), compactJSON(change.New))
+		}
+	}
+}
+
+// compactJSON renders a decoded JSON value on a single line
+func compactJSON(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// addStdinFlag registers --stdin on a command that can take its
+// configuration identifiers from standard input
+func addStdinFlag(cmd *cobra.Command) {
+	cmd.Flags().Bool("stdin", false, "Read configuration names or IDs from stdin, one per line")
+}
+
+// stdinArgs accepts no arguments with --stdin and otherwise defers to args
+func stdinArgs(args cobra.PositionalArgs) cobra.PositionalArgs {
+	return func(cmd *cobra.Command, positional []string) error {
+		if fromStdin, _ := cmd.Flags().GetBool("stdin"); fromStdin {
+			if len(positional) > 0 {
+				return fmt.Errorf("--stdin cannot be combined with a configuration argument")
+			}
+			return nil
+		}
+		return args(cmd, positional)
+	}
+}
+
+// readIdentifiers reads one configuration identifier per line from r,
+// trimming spaces and skipping blank lines
+func readIdentifiers(r io.Reader) ([]string, error) {
+	var identifiers []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if identifier := strings.TrimSpace(scanner.Text()); identifier != "" {
+			identifiers = append(identifiers, identifier)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read identifiers from stdin: %w", err)
+	}
+	if len(identifiers) == 0 {
+		return nil, fmt.Errorf("no configuration names or IDs on stdin")
+	}
+	return identifiers, nil
+}
@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/Xanonymous-GitHub/claude-switch/internal/config"
+	"github.com/Xanonymous-GitHub/claude-switch/internal/output"
+)
+
+// targetResult is the outcome of applying a configuration to one target
+type targetResult struct {
+	dir    string
+	result *config.ApplyResult
+	err    error
+}
+
+// applyToTargets applies cfg to <dir>/.claude/settings.json for every target
+// directory concurrently. A failing target does not stop the others; the
+// command fails at the end if any target failed.
+func applyToTargets(manager *config.Manager, cfg *config.Config, targets []string, opts config.ApplyOptions, confirm, dryRun bool) error {
+	output.Printf("🎯 Applying configuration '%s' to %d target%s\n", cfg.Name, len(targets), pluralize(len(targets)))
+
+	if dryRun {
+		output.Println("🔍 DRY RUN MODE - No changes will be made")
+		if _, err := manager.RenderConfig(cfg.ID, opts); err != nil {
+			return fmt.Errorf("failed to render configuration: %w", err)
+		}
+		for _, dir := range targets {
+			output.Printf("Would apply to: %s\n", targetSettingsPath(dir))
+		}
+		return nil
+	}
+
+	if confirm {
+		output.Fprintf(os.Stderr, "This will replace the Claude Code settings in %d directories. Continue? (y/N): ", len(targets))
+		reader := bufio.NewReader(os.Stdin)
+		response, err := reader.ReadString('\n')
+		if err != nil {
+			return fmt.Errorf("failed to read confirmation: %w", err)
+		}
+		if strings.ToLower(strings.TrimSpace(response)) != "y" {
+			output.Println("❌ Operation cancelled")
+			return nil
+		}
+	}
+
+	results := make([]targetResult, len(targets))
+	var wg sync.WaitGroup
+	for i, dir := range targets {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = applyToTarget(manager, cfg, dir, opts)
+		}()
+	}
+	wg.Wait()
+
+	failed := 0
+	for _, r := range results {
+		if r.err != nil {
+			failed++
+			output.Printf("❌ %s: %v\n", r.dir, r.err)
+			continue
+		}
+		output.Printf("✅ %s\n", r.result.SettingsPath)
+		if r.result.BackupPath != "" {
+			output.Printf("   💾 Backup saved: %s\n", r.result.BackupPath)
+		}
+		if r.result.BackupSkipped {
+			output.Println("   ⚠️  No backup was made: the previous settings exceeded the backup limit")
+		}
+	}
+
+	output.Println()
+	output.Printf("📊 Applied to %d of %d target%s\n", len(targets)-failed, len(targets), pluralize(len(targets)))
+	if failed > 0 {
+		return fmt.Errorf("%d of %d targets failed", failed, len(targets))
+	}
+	return nil
+}
+
+// applyToTarget applies cfg to the settings file of one target directory
+func applyToTarget(manager *config.Manager, cfg *config.Config, dir string, opts config.ApplyOptions) targetResult {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return targetResult{dir: dir, err: fmt.Errorf("target not found: %w", err)}
+	}
+	if !info.IsDir() {
+		return targetResult{dir: dir, err: fmt.Errorf("target is not a directory")}
+	}
+
+	opts.SettingsPath = targetSettingsPath(dir)
+	result, err := manager.ApplyConfigWithOptions(cfg.ID, opts)
+	return targetResult{dir: dir, result: result, err: err}
+}
+
+// targetSettingsPath returns the settings file of a target directory
+func targetSettingsPath(dir string) string {
+	return filepath.Join(dir, ".claude", "settings.json")
+}
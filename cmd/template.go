@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/Xanonymous-GitHub/claude-switch/internal/output"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+var templateCmd = &cobra.Command{
+	Use:   "template",
+	Short: "Inspect configuration templates",
+	Long: `Inspect the Go template placeholders in stored configurations.
+
+See 'claude-switch apply --help' for how placeholders are rendered.`,
+}
+
+var templateVarsCmd = &cobra.Command{
+	Use:   "vars [config-name-or-id]",
+	Short: "List the variables a configuration template expects",
+	Long: `List the placeholders a configuration template references.
+
+Each variable is shown with the number of times it occurs, the lines it
+occurs on and its default, if the template gives one with
+{{or .NAME "default"}}. Variables without a default must be provided with
+--env-file or --var when applying, unless --allow-missing is given.`,
+	Example: `  # Show the variables to put in an env file
+  claude-switch template vars work`,
+	Args: cobra.ExactArgs(1),
+	RunE: runTemplateVars,
+}
+
+func init() {
+	templateCmd.AddCommand(templateVarsCmd)
+}
+
+func runTemplateVars(cmd *cobra.Command, args []string) error {
+	// Create config manager
+	manager, err := newManager(cmd)
+	if err != nil {
+		return fmt.Errorf("failed to initialize config manager: %w", err)
+	}
+
+	cfg, vars, err := manager.TemplateVars(args[0])
+	if err != nil {
+		return err
+	}
+
+	if len(vars) == 0 {
+		output.Printf("📭 '%s' has no template variables\n", cfg.Name)
+		return nil
+	}
+
+	output.Printf("🧩 %d template variable%s in '%s':\n\n", len(vars), pluralize(len(vars)), cfg.Name)
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.Header("Variable", "Occurrences", "Lines", "Default")
+
+	for _, v := range vars {
+		lines := make([]string, len(v.Lines))
+		for i, line := range v.Lines {
+			lines[i] = strconv.Itoa(line)
+		}
+
+		defaultValue := "-"
+		if v.HasDefault {
+			defaultValue = strconv.Quote(v.Default)
+		}
+
+		if err := table.Append(v.Name, len(v.Lines), strings.Join(lines, ", "), defaultValue); err != nil {
+			return fmt.Errorf("failed to add row to table: %w", err)
+		}
+	}
+
+	if err := table.Render(); err != nil {
+		return fmt.Errorf("failed to render table: %w", err)
+	}
+
+	output.Println()
+	output.Printf("💡 Provide values with 'claude-switch apply %s --env-file <file>' or --var KEY=VALUE\n", cfg.Name)
+	return nil
+}
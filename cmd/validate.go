@@ -1,9 +1,18 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
 
 	"github.com/Xanonymous-GitHub/claude-switch/internal/config"
+	"github.com/Xanonymous-GitHub/claude-switch/internal/diff"
+	"github.com/Xanonymous-GitHub/claude-switch/internal/jsonutil"
+	"github.com/Xanonymous-GitHub/claude-switch/internal/output"
+	"github.com/Xanonymous-GitHub/claude-switch/internal/validation"
 	"github.com/spf13/cobra"
 )
 
@@ -19,7 +28,45 @@ This command can validate:
 The validation checks for:
 - Valid JSON syntax
 - Proper structure for Claude Code settings
-- File accessibility and readability`,
+- File accessibility and readability
+
+With --deny-keys, configurations that set a key denied by the
+apply.deniedKeys preference are invalid too.
+
+The validate.requiredKeys preference lists keys, as dotted paths, that
+every configuration must set. A configuration missing any of them gets a
+warning (--verbose names the keys), or is invalid with --require-keys.
+
+Duplicate object keys are reported as warnings: JSON parsing keeps only
+the last value, which is rarely what was intended.
+
+With --fix, invalid configurations are parsed tolerantly (comments and
+trailing commas are stripped). Those that become valid are shown as a
+diff and, once confirmed, rewritten formatted. Configurations that
+cannot be salvaged are left untouched and reported as invalid.
+
+--schema-url also checks configurations against a JSON Schema published
+at a URL, such as a settings schema for the current Claude Code release.
+The validate.schemaUrl preference sets a default URL, which 'apply
+--force-validate' checks against too.
+The schema is cached in the store and downloaded again once it is older
+than --schema-ttl. If it cannot be downloaded, a stale cached copy is used;
+without one, validation falls back to the built-in checks. Download problems
+are reported as warnings on stderr and never fail validation by themselves.
+
+--json prints an array of {"name", "id", "valid", "error"} objects, one
+per validated configuration, with "error" null for valid ones. The exit
+status is non-zero if any configuration is invalid.
+
+--stdin validates the configurations named on stdin, one name or ID per
+line, reporting a result for each line. A name that matches no
+configuration counts as a failure.
+
+--file validates a settings file that is not in the store, or stdin for
+"-", with the same checks (including --deny-keys, --require-keys and
+--schema-url) and exit
+status, and stores nothing. It reads the file as 'add --file' would: in
+the --format given, and otherwise .jsonc and .json5 files tolerantly.`,
 	Example: `  # Validate a specific configuration
   claude-switch validate my-work-setup
 
@@ -27,35 +74,176 @@ The validation checks for:
   claude-switch validate
 
   # Validate with verbose output
-  claude-switch validate --verbose`,
-	Args: cobra.MaximumNArgs(1),
+  claude-switch validate --verbose
+
+  # Only set the exit code, e.g. in a pre-commit hook
+  claude-switch validate --quiet && echo ok
+
+  # Also check against a published settings schema
+  claude-switch validate --schema-url https://json.schemastore.org/claude-code-settings.json
+
+  # Machine-readable results for CI
+  claude-switch validate --json
+
+  # Repair comments and trailing commas in invalid configurations
+  claude-switch validate --fix
+
+  # Validate the configurations a script selected
+  claude-switch list --porcelain | cut -f2 | grep ^work- | claude-switch validate --stdin
+
+  # Lint a candidate settings file before adding it
+  claude-switch validate --file ./candidate.json --deny-keys --require-keys
+
+  # Validate generated settings from stdin
+  generate-settings | claude-switch validate --file -`,
+	Args: stdinArgs(cobra.MaximumNArgs(1)),
 	RunE: runValidate,
 }
 
 func init() {
 	validateCmd.Flags().BoolP("verbose", "v", false, "Show detailed validation information")
 	validateCmd.Flags().BoolP("all", "a", false, "Validate all configurations (default when no config specified)")
+	validateCmd.Flags().Bool("fix", false, "Repair invalid configurations by stripping comments and trailing commas")
+	validateCmd.Flags().BoolP("yes", "y", false, "Apply fixes without confirmation")
+	validateCmd.Flags().BoolP("json", "j", false, "Output validation results as a JSON array")
+	validateCmd.MarkFlagsMutuallyExclusive("json", "fix")
+	validateCmd.Flags().String("schema-url", "", "Also validate against the JSON Schema at this URL (default: validate.schemaUrl preference)")
+	validateCmd.Flags().String("schema-ttl", defaultSchemaTTL, "How long a downloaded schema is reused before fetching it again (e.g. 12h, 7d)")
+	validateCmd.Flags().Bool("deny-keys", false, "Treat configurations that set a key in apply.deniedKeys as invalid")
+	validateCmd.Flags().Bool("require-keys", false, "Treat configurations missing a key in validate.requiredKeys as invalid")
+	addStdinFlag(validateCmd)
+	validateCmd.MarkFlagsMutuallyExclusive("stdin", "all")
+	validateCmd.MarkFlagsMutuallyExclusive("stdin", "fix")
+	validateCmd.Flags().String("file", "", "Validate this settings file instead of stored configurations (- for stdin)")
+	validateCmd.Flags().String("format", config.FormatJSON, "With --file, the format the file is written in: json or toml")
+	for _, flag := range []string{"stdin", "all", "fix"} {
+		validateCmd.MarkFlagsMutuallyExclusive("file", flag)
+	}
+}
+
+// defaultSchemaTTL is how long a downloaded schema is reused by default
+const defaultSchemaTTL = "24h"
+
+// validationReport is the JSON form of one configuration's validation
+type validationReport struct {
+	Name  string  `json:"name"`
+	ID    string  `json:"id"`
+	Valid bool    `json:"valid"`
+	Error *string `json:"error"`
 }
 
 func runValidate(cmd *cobra.Command, args []string) error {
 	// Create config manager
-	manager, err := config.NewManager()
+	manager, err := newManager(cmd)
 	if err != nil {
 		return fmt.Errorf("failed to initialize config manager: %w", err)
 	}
 
 	verbose, _ := cmd.Flags().GetBool("verbose")
 	validateAll, _ := cmd.Flags().GetBool("all")
+	fix, _ := cmd.Flags().GetBool("fix")
+	yes, _ := cmd.Flags().GetBool("yes")
+
+	schemaURL, _ := cmd.Flags().GetString("schema-url")
+	if !cmd.Flags().Changed("schema-url") {
+		schemaURL, _, _ = manager.GetPreference(config.PrefValidateSchemaURL)
+	}
+	if schemaURL != "" {
+		ttlFlag, _ := cmd.Flags().GetString("schema-ttl")
+		ttl, err := parseAge(ttlFlag)
+		if err != nil {
+			return err
+		}
+		useSettingsSchema(manager, schemaURL, ttl, verbose)
+	}
+
+	// Keys apply would refuse and missing required keys make a configuration
+	// invalid only when asked for
+	if denyKeys, _ := cmd.Flags().GetBool("deny-keys"); denyKeys {
+		manager.EnableDeniedKeyValidation()
+	}
+	if requireKeys, _ := cmd.Flags().GetBool("require-keys"); requireKeys {
+		manager.EnableRequiredKeyValidation()
+	}
+
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+	if file, _ := cmd.Flags().GetString("file"); file != "" {
+		if len(args) > 0 {
+			return fmt.Errorf("--file cannot be combined with a configuration argument")
+		}
+		return validateFile(cmd, manager, file, verbose, jsonOutput)
+	}
+	if cmd.Flags().Changed("format") {
+		return fmt.Errorf("--format requires --file")
+	}
+
+	if fromStdin, _ := cmd.Flags().GetBool("stdin"); fromStdin {
+		identifiers, err := readIdentifiers(os.Stdin)
+		if err != nil {
+			return err
+		}
+		return validateIdentifiers(cmd, manager, identifiers, verbose, jsonOutput)
+	}
+
+	if jsonOutput {
+		var results []config.ValidationResult
+		if len(args) == 0 || validateAll {
+			results = manager.ValidateConfigs()
+		} else {
+			cfg, err := manager.GetConfig(args[0])
+			if err != nil {
+				return fmt.Errorf("configuration not found: %w", err)
+			}
+			results = []config.ValidationResult{{Config: *cfg, Err: manager.ValidateConfig(cfg.ID)}}
+		}
+		return outputValidationJSON(cmd, results)
+	}
 
 	// If no specific config is provided, validate all
 	if len(args) == 0 || validateAll {
+		if fix {
+			if err := fixConfigs(manager, manager.GetConfigs(), yes); err != nil {
+				return err
+			}
+		}
 		return validateAllConfigs(manager, verbose)
 	}
 
+	if fix {
+		cfg, err := manager.GetConfig(args[0])
+		if err != nil {
+			return fmt.Errorf("configuration not found: %w", err)
+		}
+		if err := fixConfigs(manager, []config.Config{*cfg}, yes); err != nil {
+			return err
+		}
+	}
+
 	// Validate specific configuration
 	return validateSingleConfig(manager, args[0], verbose)
 }
 
+// useSettingsSchema loads the schema at url for validation. Download
+// failures are only warnings: validation then uses a stale cached schema, or
+// the built-in checks alone.
+func useSettingsSchema(manager *config.Manager, url string, ttl time.Duration, verbose bool) {
+	loaded, err := manager.LoadSettingsSchema(url, ttl)
+	if err != nil {
+		output.Fprintf(os.Stderr, "⚠️  %v; using the built-in checks only\n", err)
+		return
+	}
+
+	switch {
+	case loaded.FetchErr != nil:
+		output.Fprintf(os.Stderr, "⚠️  %v; using the cached schema %s\n", loaded.FetchErr, loaded.CachePath)
+	case verbose && loaded.FromCache:
+		output.Printf("📐 Using cached schema %s\n", loaded.CachePath)
+	case verbose:
+		output.Printf("📐 Downloaded schema from %s\n", url)
+	}
+	manager.SetSettingsSchema(loaded.Schema)
+}
+
 func validateSingleConfig(manager *config.Manager, identifier string, verbose bool) error {
 	// Get the configuration
 	cfg, err := manager.GetConfig(identifier)
@@ -63,83 +251,323 @@ func validateSingleConfig(manager *config.Manager, identifier string, verbose bo
 		return fmt.Errorf("configuration not found: %w", err)
 	}
 
-	fmt.Printf("🔍 Validating configuration: %s\n", cfg.Name)
+	output.Printf("🔍 Validating configuration: %s\n", cfg.Name)
 	if verbose {
-		fmt.Printf("   ID: %s\n", cfg.ID)
-		fmt.Printf("   File: %s\n", cfg.FilePath)
+		output.Printf("   ID: %s\n", cfg.ID)
+		output.Printf("   File: %s\n", cfg.FilePath)
 		if cfg.Description != "" {
-			fmt.Printf("   Description: %s\n", cfg.Description)
+			printDescription(cfg.Description)
 		}
-		fmt.Printf("   Created: %s\n", cfg.CreatedAt.Format("2006-01-02 15:04:05"))
+		output.Printf("   Created: %s\n", cfg.CreatedAt.Format("2006-01-02 15:04:05"))
 	}
 
 	// Validate the configuration
 	if err := manager.ValidateConfig(identifier); err != nil {
-		fmt.Printf("❌ Validation failed: %v\n", err)
-		return fmt.Errorf("configuration validation failed")
+		output.Printf("❌ Validation failed: %v\n", err)
+		return fmt.Errorf("configuration '%s' is invalid: %w", cfg.Name, err)
 	}
 
-	fmt.Println("✅ Configuration is valid")
+	output.Println("✅ Configuration is valid")
+	warnDuplicateKeys(cfg.FilePath, cfg.StoredFormat())
+	warnMissingKeys(manager, cfg, verbose)
 	return nil
 }
 
+// warnMissingKeys warns when a configuration does not set every key of the
+// validate.requiredKeys preference, naming them when verbose. Under
+// --require-keys validation has already failed instead.
+func warnMissingKeys(manager *config.Manager, cfg *config.Config, verbose bool) {
+	_, settings, err := manager.LoadSettings(cfg.ID)
+	if err != nil {
+		return
+	}
+	printMissingKeys(manager.MissingRequiredKeys(settings), verbose)
+}
+
+// printMissingKeys warns about required keys that are missing, naming them
+// when verbose
+func printMissingKeys(missing []string, verbose bool) {
+	if len(missing) == 0 {
+		return
+	}
+	if !verbose {
+		output.Printf("⚠️  Missing %d required key%s (%s); use --verbose to list them\n",
+			len(missing), pluralize(len(missing)), config.PrefValidateRequiredKeys)
+		return
+	}
+	for _, key := range missing {
+		output.Printf("⚠️  Missing required key '%s' (%s)\n", key, config.PrefValidateRequiredKeys)
+	}
+}
+
+// warnDuplicateKeys prints a warning for every repeated object key in the
+// file at path, written in format. Unreadable or invalid files are reported
+// by validation instead.
+func warnDuplicateKeys(path, format string) {
+	for _, warning := range duplicateKeyWarnings(path, format) {
+		output.Printf("⚠️  %s\n", warning)
+	}
+}
+
+// duplicateKeyWarnings describes the duplicate object keys in the file at
+// path, written in format. TOML is checked after conversion to JSON.
+func duplicateKeyWarnings(path, format string) []string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	if data, err = config.ToJSON(data, format); err != nil {
+		return nil
+	}
+	return duplicateKeyWarningsIn(data)
+}
+
+// duplicateKeyWarningsIn describes the duplicate object keys in JSON data
+func duplicateKeyWarningsIn(data []byte) []string {
+	duplicates, err := validation.FindDuplicateKeys(data)
+	if err != nil {
+		return nil
+	}
+
+	var warnings []string
+	for _, duplicate := range duplicates {
+		warnings = append(warnings, fmt.Sprintf("Duplicate key '%s' at %s (only the last value is used)", duplicate.Key, duplicate.Path))
+	}
+	return warnings
+}
+
 func validateAllConfigs(manager *config.Manager, verbose bool) error {
 	configs := manager.GetConfigs()
 
 	if len(configs) == 0 {
-		fmt.Println("📭 No configurations found to validate")
+		output.Println("📭 No configurations found to validate")
 		return nil
 	}
 
-	fmt.Printf("🔍 Validating %d configuration(s)...\n\n", len(configs))
+	output.Printf("🔍 Validating %d configuration(s)...\n\n", len(configs))
 
-	errors := manager.ValidateAllConfigs()
+	results := manager.ValidateConfigs()
 
-	validCount := len(configs) - len(errors)
+	invalidCount := 0
+	for _, result := range results {
+		if result.Err != nil {
+			invalidCount++
+		}
+	}
+	validCount := len(configs) - invalidCount
 
 	// Show results
-	for _, cfg := range configs {
-		// Check if this config has an error
-		hasError := false
-		var errorMsg string
-		for _, err := range errors {
-			if fmt.Sprintf("config '%s'", cfg.Name) == fmt.Sprintf("config '%s'", cfg.Name) {
-				hasError = true
-				errorMsg = err.Error()
-				break
-			}
-		}
+	for _, result := range results {
+		cfg := result.Config
 
-		if hasError {
-			fmt.Printf("❌ %s - %s\n", cfg.Name, errorMsg)
+		if result.Err != nil {
+			output.Printf("❌ %s - %v\n", cfg.Name, result.Err)
 			if verbose {
-				fmt.Printf("   ID: %s\n", cfg.ID)
-				fmt.Printf("   File: %s\n", cfg.FilePath)
+				output.Printf("   ID: %s\n", cfg.ID)
+				output.Printf("   File: %s\n", cfg.FilePath)
 			}
 		} else {
-			fmt.Printf("✅ %s - Valid\n", cfg.Name)
+			output.Printf("✅ %s - Valid\n", cfg.Name)
+			warnDuplicateKeys(cfg.FilePath, cfg.StoredFormat())
+			warnMissingKeys(manager, &cfg, verbose)
 			if verbose {
-				fmt.Printf("   ID: %s\n", cfg.ID)
-				fmt.Printf("   File: %s\n", cfg.FilePath)
+				output.Printf("   ID: %s\n", cfg.ID)
+				output.Printf("   File: %s\n", cfg.FilePath)
 			}
 		}
 
 		if verbose {
-			fmt.Println()
+			output.Println()
 		}
 	}
 
 	// Summary
-	fmt.Printf("\n📊 Validation Summary:\n")
-	fmt.Printf("   Valid: %d\n", validCount)
-	fmt.Printf("   Invalid: %d\n", len(errors))
-	fmt.Printf("   Total: %d\n", len(configs))
+	output.Printf("\n📊 Validation Summary:\n")
+	output.Printf("   Valid: %d\n", validCount)
+	output.Printf("   Invalid: %d\n", invalidCount)
+	output.Printf("   Total: %d\n", len(configs))
+
+	if invalidCount > 0 {
+		output.Printf("\n⚠️  Found %d invalid configuration(s). Use --verbose for details.\n", invalidCount)
+		return fmt.Errorf("validation failed for %d configuration(s)", invalidCount)
+	}
 
-	if len(errors) > 0 {
-		fmt.Printf("\n⚠️  Found %d invalid configuration(s). Use --verbose for details.\n", len(errors))
-		return fmt.Errorf("validation failed for %d configuration(s)", len(errors))
+	output.Println("\n🎉 All configurations are valid!")
+	return nil
+}
+
+// validateFile validates the settings file at path, or stdin for "-",
+// without storing it
+func validateFile(cmd *cobra.Command, manager *config.Manager, path string, verbose, jsonOutput bool) error {
+	formatName, _ := cmd.Flags().GetString("format")
+	format, err := config.ParseFormat(formatName)
+	if err != nil {
+		return err
+	}
+
+	name := path
+	var data []byte
+	if path == "-" {
+		name = "stdin"
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", name, err)
+	}
+
+	// Read the file as add --file would
+	var settings []byte
+	if !cmd.Flags().Changed("format") && config.SourceFormat(path) == config.FormatJSONC {
+		settings, err = validation.Repair(data)
+	} else {
+		settings, err = config.ToJSON(data, format)
+	}
+	if err == nil {
+		err = manager.ValidateSettings(settings)
+	}
+
+	if jsonOutput {
+		return outputValidationJSON(cmd, []config.ValidationResult{{Config: config.Config{Name: name}, Err: err}})
+	}
+
+	output.Printf("🔍 Validating file: %s\n", name)
+	if err != nil {
+		output.Printf("❌ Validation failed: %v\n", err)
+		cmd.SilenceUsage = true
+		return fmt.Errorf("%s is invalid: %w", name, err)
+	}
+
+	output.Println("✅ File is valid")
+	for _, warning := range duplicateKeyWarningsIn(settings) {
+		output.Printf("⚠️  %s\n", warning)
+	}
+	if parsed, err := jsonutil.ParseObject(settings); err == nil {
+		printMissingKeys(manager.MissingRequiredKeys(parsed), verbose)
+	}
+	return nil
+}
+
+// validateIdentifiers validates the configurations named by identifiers,
+// reporting one result per identifier, and fails if any is missing or invalid
+func validateIdentifiers(cmd *cobra.Command, manager *config.Manager, identifiers []string, verbose, jsonOutput bool) error {
+	results := make([]config.ValidationResult, len(identifiers))
+	for i, identifier := range identifiers {
+		cfg, err := manager.GetConfig(identifier)
+		if err != nil {
+			// Report the identifier as given, without an ID
+			results[i] = config.ValidationResult{Config: config.Config{Name: identifier}, Err: fmt.Errorf("configuration not found: %w", err)}
+			continue
+		}
+		results[i] = config.ValidationResult{Config: *cfg, Err: manager.ValidateConfig(cfg.ID)}
+	}
+
+	if jsonOutput {
+		return outputValidationJSON(cmd, results)
+	}
+
+	invalidCount := 0
+	for _, result := range results {
+		cfg := result.Config
+		if result.Err != nil {
+			invalidCount++
+			output.Printf("❌ %s - %v\n", cfg.Name, result.Err)
+			continue
+		}
+
+		output.Printf("✅ %s - Valid\n", cfg.Name)
+		warnDuplicateKeys(cfg.FilePath, cfg.StoredFormat())
+		warnMissingKeys(manager, &cfg, verbose)
+		if verbose {
+			output.Printf("   ID: %s\n", cfg.ID)
+			output.Printf("   File: %s\n", cfg.FilePath)
+		}
+	}
+
+	if invalidCount > 0 {
+		// Each failure has already been reported
+		cmd.SilenceUsage = true
+		return fmt.Errorf("validation failed for %d of %d configuration(s)", invalidCount, len(results))
+	}
+	return nil
+}
+
+// outputValidationJSON prints validation results as a JSON array and fails
+// if any configuration is invalid
+func outputValidationJSON(cmd *cobra.Command, results []config.ValidationResult) error {
+	reports := make([]validationReport, len(results))
+	invalidCount := 0
+	for i, result := range results {
+		reports[i] = validationReport{Name: result.Config.Name, ID: result.Config.ID, Valid: result.Err == nil}
+		if result.Err != nil {
+			message := result.Err.Error()
+			reports[i].Error = &message
+			invalidCount++
+		}
+	}
+
+	data, err := json.MarshalIndent(reports, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal validation results: %w", err)
+	}
+	fmt.Println(string(data))
+
+	if invalidCount > 0 {
+		// The report already names the failures
+		cmd.SilenceUsage = true
+		return fmt.Errorf("validation failed for %d configuration(s)", invalidCount)
+	}
+	return nil
+}
+
+// fixConfigs attempts to repair each invalid configuration, showing a diff
+// and asking for confirmation before rewriting it
+func fixConfigs(manager *config.Manager, configs []config.Config, yes bool) error {
+	for _, cfg := range configs {
+		if manager.ValidateConfig(cfg.ID) == nil {
+			continue
+		}
+
+		if cfg.StoredFormat() != config.FormatJSON {
+			output.Printf("⚠️  %s - only JSON configurations can be repaired\n", cfg.Name)
+			continue
+		}
+
+		data, err := os.ReadFile(cfg.FilePath)
+		if err != nil {
+			output.Printf("⚠️  %s - cannot read file: %v\n", cfg.Name, err)
+			continue
+		}
+
+		repaired, err := validation.Repair(data)
+		if err != nil {
+			output.Printf("⚠️  %s - cannot be repaired automatically\n", cfg.Name)
+			continue
+		}
+
+		output.Printf("🔧 Proposed fix for %s:\n", cfg.Name)
+		fmt.Print(diff.Unified(cfg.FilePath, cfg.FilePath+" (fixed)", string(data), string(repaired), 3))
+		output.Println()
+
+		if !yes {
+			response, err := promptForInput(fmt.Sprintf("Apply fix to '%s'? (y/N): ", cfg.Name))
+			if err != nil {
+				return fmt.Errorf("failed to read confirmation: %w", err)
+			}
+			if strings.ToLower(response) != "y" {
+				output.Println("❌ Fix skipped")
+				continue
+			}
+		}
+
+		// Stored as a new revision, so the fix can be undone like an edit
+		_, revision, err := manager.UpdateConfig(cfg.ID, repaired)
+		if err != nil {
+			return fmt.Errorf("failed to write repaired config '%s': %w", cfg.Name, err)
+		}
+		output.Printf("✅ %s repaired (revision %d)\n\n", cfg.Name, revision)
 	}
 
-	fmt.Println("\n🎉 All configurations are valid!")
 	return nil
 }
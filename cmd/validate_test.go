@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Xanonymous-GitHub/claude-switch/internal/config"
+)
+
+func TestDuplicateKeyWarnings(t *testing.T) {
+	tests := []struct {
+		name   string
+		format string
+		data   string
+		want   int
+	}{
+		{"json", config.FormatJSON, `{"a": 1, "a": 2}`, 1},
+		{"json with byte order mark", config.FormatJSON, "\xEF\xBB\xBF{\"a\": 1, \"a\": 2}", 1},
+		{"toml", config.FormatTOML, "model = \"opus\"\n[env]\nX = \"1\"\n", 0},
+		{"invalid toml", config.FormatTOML, "model = ", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "settings")
+			if err := os.WriteFile(path, []byte(tt.data), 0644); err != nil {
+				t.Fatal(err)
+			}
+			if got := duplicateKeyWarnings(path, tt.format); len(got) != tt.want {
+				t.Errorf("duplicateKeyWarnings = %q, want %d warnings", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDuplicateKeyWarningsIn(t *testing.T) {
+	got := duplicateKeyWarningsIn([]byte(`{"editor": {"theme": "dark", "theme": "light"}}`))
+	want := "Duplicate key 'theme' at editor.theme (only the last value is used)"
+	if len(got) != 1 || got[0] != want {
+		t.Errorf("duplicateKeyWarningsIn = %q, want [%q]", got, want)
+	}
+
+	if got := duplicateKeyWarningsIn([]byte(`{"editor": `)); got != nil {
+		t.Errorf("duplicateKeyWarningsIn on invalid JSON = %q, want none", got)
+	}
+}
+
+func TestFixConfigsRecordsRevision(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	manager, err := config.NewManager()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := manager.ImportConfig("work", "", []byte(`{"model": "opus"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, before, err := manager.Revisions(cfg.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A hand edit left a trailing comma behind
+	if err := os.WriteFile(cfg.FilePath, []byte(`{"model": "opus",}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	captureStdout(t, func() {
+		if err := fixConfigs(manager, []config.Config{*cfg}, true); err != nil {
+			t.Errorf("fixConfigs: %v", err)
+		}
+	})
+
+	if err := manager.ValidateConfig(cfg.ID); err != nil {
+		t.Errorf("config still invalid after the fix: %v", err)
+	}
+	_, after, err := manager.Revisions(cfg.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(after) <= len(before) {
+		t.Fatalf("%d revisions after the fix, want more than %d", len(after), len(before))
+	}
+	fixed, _ := os.ReadFile(cfg.FilePath)
+	latest, _ := os.ReadFile(after[len(after)-1].Path)
+	if string(latest) != string(fixed) {
+		t.Errorf("latest revision holds %q, want the repaired %q", latest, fixed)
+	}
+}
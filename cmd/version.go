@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/Xanonymous-GitHub/claude-switch/internal/config"
+	"github.com/Xanonymous-GitHub/claude-switch/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var versionCmd = &cobra.Command{
+	Use:   "version",
+	Short: "Print the version and check store compatibility",
+	Long: `Print the claude-switch version and the config store schema it supports.
+
+With --check, the schema version of ~/.claude-switch/config.json is read
+and compared: an older store is migrated when next loaded, a current store
+needs nothing, and a store written by a newer claude-switch is unsupported.
+The newer case exits with a non-zero status so CI can catch version skew.`,
+	Example: `  # Print the version
+  claude-switch version
+
+  # Fail if the store needs a newer binary
+  claude-switch version --check`,
+	Args: cobra.NoArgs,
+	RunE: runVersion,
+}
+
+func init() {
+	versionCmd.Flags().Bool("check", false, "Check whether this build can read the config store")
+}
+
+func runVersion(cmd *cobra.Command, args []string) error {
+	fmt.Printf("claude-switch %s\n", rootCmd.Version)
+	output.Printf("Store schema: v%d\n", config.SchemaVersion)
+
+	check, _ := cmd.Flags().GetBool("check")
+	if !check {
+		return nil
+	}
+
+	dir, err := config.DefaultDir()
+	if err != nil {
+		return err
+	}
+
+	result, err := config.CheckSchema(dir)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case result.Version == 0:
+		output.Printf("📭 No store at %s; nothing to check\n", result.Path)
+	case result.Newer():
+		cmd.SilenceUsage = true
+		return fmt.Errorf("%w: %s is schema v%d, this build supports v%d; upgrade claude-switch",
+			config.ErrSchemaTooNew, result.Path, result.Version, config.SchemaVersion)
+	case result.Older():
+		output.Printf("⬆️  Store schema v%d is older and will be migrated to v%d\n", result.Version, config.SchemaVersion)
+	default:
+		output.Printf("✅ Store schema v%d is current\n", result.Version)
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/Xanonymous-GitHub/claude-switch/internal/output"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// watchInterval is how often list --watch polls for changes
+	watchInterval = 500 * time.Millisecond
+	// watchDebounce is how long changes must settle before a redraw, so a
+	// burst of writes redraws once
+	watchDebounce = 300 * time.Millisecond
+)
+
+// watchList redraws the list table whenever the store or the live settings
+// change, until interrupted
+func watchList(cmd *cobra.Command) error {
+	format, _ := cmd.Flags().GetString("output")
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+	porcelain, _ := cmd.Flags().GetBool("porcelain")
+	if format != "table" || jsonOutput || porcelain {
+		return fmt.Errorf("--watch only works with table output")
+	}
+
+	// Create config manager
+	manager, err := newManager(cmd)
+	if err != nil {
+		return fmt.Errorf("failed to initialize config manager: %w", err)
+	}
+	storeDir := manager.GetConfigDir()
+	settingsPath, err := manager.GetClaudeSettingsPath()
+	if err != nil {
+		return err
+	}
+	watched := []string{
+		filepath.Join(storeDir, "config.json"),
+		filepath.Join(storeDir, "configs"),
+		settingsPath,
+	}
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(interrupt)
+
+	ticker := time.NewTicker(watchInterval)
+	defer ticker.Stop()
+
+	redraw := func() {
+		// Clear the screen and move the cursor home
+		fmt.Print("\033[H\033[2J")
+		if err := listConfigs(cmd, true); err != nil {
+			output.Fprintf(os.Stderr, "❌ %v\n", err)
+		}
+		output.Printf("\n👀 Watching %s (updated %s, Ctrl+C to stop)\n", storeDir, time.Now().Format("15:04:05"))
+	}
+
+	last := watchFingerprint(watched)
+	redraw()
+
+	var changedAt time.Time
+	for {
+		select {
+		case <-interrupt:
+			fmt.Println()
+			return nil
+		case now := <-ticker.C:
+			if current := watchFingerprint(watched); current != last {
+				last = current
+				changedAt = now
+				continue
+			}
+			if !changedAt.IsZero() && now.Sub(changedAt) >= watchDebounce {
+				changedAt = time.Time{}
+				redraw()
+			}
+		}
+	}
+}
+
+// watchFingerprint summarizes the size and modification time of each path,
+// and of the entries of directories, so any change alters the result
+func watchFingerprint(paths []string) string {
+	var b strings.Builder
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			fmt.Fprintf(&b, "%s:missing;", path)
+			continue
+		}
+		fmt.Fprintf(&b, "%s:%d:%d;", path, info.Size(), info.ModTime().UnixNano())
+
+		if !info.IsDir() {
+			continue
+		}
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			if entryInfo, err := entry.Info(); err == nil {
+				fmt.Fprintf(&b, "%s:%d:%d;", entry.Name(), entryInfo.Size(), entryInfo.ModTime().UnixNano())
+			}
+		}
+	}
+	return b.String()
+}
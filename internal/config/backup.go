@@ -0,0 +1,446 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Xanonymous-GitHub/claude-switch/internal/storage"
+	"github.com/Xanonymous-GitHub/claude-switch/internal/validation"
+)
+
+// backupTimeFormat is the timestamp layout in backup file names
+const backupTimeFormat = "20060102-150405"
+
+// backupNamePattern matches timestamped backup names: settings-<timestamp>
+// with an optional -<n> suffix for backups taken within the same second
+var backupNamePattern = regexp.MustCompile(`^settings-(\d{8}-\d{6})(?:-(\d+))?\.json(?:\.gz)?$`)
+
+// Backup is a saved copy of settings.json taken before an apply
+type Backup struct {
+	Path      string
+	CreatedAt time.Time
+	Size      int64
+	// Legacy marks an untimestamped settings.json.backup from older versions,
+	// whose CreatedAt is its modification time. Legacy backups are never pruned.
+	Legacy bool
+
+	// seq orders backups taken within the same second
+	seq int
+}
+
+// BackupDir returns the directory holding timestamped backups of a settings file
+func BackupDir(settingsPath string) string {
+	return filepath.Join(filepath.Dir(settingsPath), "backups")
+}
+
+// BackupPath returns the backup location for a settings file backed up at the
+// given time: backups/settings-<timestamp>.json, or .json.gz when compressed
+func BackupPath(settingsPath string, at time.Time, compressed bool) string {
+	name := "settings-" + at.Format(backupTimeFormat) + ".json"
+	if compressed {
+		name += ".gz"
+	}
+	return filepath.Join(BackupDir(settingsPath), name)
+}
+
+// newBackupPath returns an unused backup path for a backup taken now, adding
+// -2, -3, ... to the file name when backups were taken in the same second
+func newBackupPath(settingsPath string, compressed bool) string {
+	path := BackupPath(settingsPath, time.Now(), compressed)
+	dir, name := filepath.Split(path)
+	ext := ".json"
+	if compressed {
+		ext += filepath.Ext(name)
+	}
+	stem := strings.TrimSuffix(name, ext)
+	for n := 2; storage.FileExists(path); n++ {
+		path = filepath.Join(dir, fmt.Sprintf("%s-%d%s", stem, n, ext))
+	}
+	return path
+}
+
+// legacyBackupPaths returns the untimestamped backup locations used by older
+// versions
+func legacyBackupPaths(settingsPath string) []string {
+	return []string{settingsPath + ".backup", settingsPath + ".backup.gz"}
+}
+
+// Backups returns the backups of settings.json, newest first. Timestamped
+// backups are dated by the timestamp in their name rather than their
+// modification time.
+func (m *Manager) Backups() ([]Backup, error) {
+	settingsPath, err := m.GetClaudeSettingsPath()
+	if err != nil {
+		return nil, err
+	}
+
+	var backups []Backup
+
+	dir := BackupDir(settingsPath)
+	entries, err := os.ReadDir(dir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read backups: %w", err)
+	}
+	for _, entry := range entries {
+		match := backupNamePattern.FindStringSubmatch(entry.Name())
+		if match == nil || !entry.Type().IsRegular() {
+			continue
+		}
+
+		createdAt, err := time.ParseInLocation(backupTimeFormat, match[1], time.Local)
+		if err != nil {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+
+		seq := 1
+		if match[2] != "" {
+			seq, _ = strconv.Atoi(match[2])
+		}
+
+		backups = append(backups, Backup{
+			Path:      filepath.Join(dir, entry.Name()),
+			CreatedAt: createdAt,
+			Size:      info.Size(),
+			seq:       seq,
+		})
+	}
+
+	for _, path := range legacyBackupPaths(settingsPath) {
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		backups = append(backups, Backup{
+			Path:      path,
+			CreatedAt: info.ModTime(),
+			Size:      info.Size(),
+			Legacy:    true,
+		})
+	}
+
+	sort.SliceStable(backups, func(i, j int) bool {
+		if backups[i].CreatedAt.Equal(backups[j].CreatedAt) {
+			return backups[i].seq > backups[j].seq
+		}
+		return backups[i].CreatedAt.After(backups[j].CreatedAt)
+	})
+
+	return backups, nil
+}
+
+// BackupByIndex returns the backup at a 1-based index in Backups order, so 1
+// is the newest
+func (m *Manager) BackupByIndex(index int) (*Backup, error) {
+	backups, err := m.Backups()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(backups) == 0 {
+		return nil, fmt.Errorf("no backups found")
+	}
+	if index < 1 || index > len(backups) {
+		return nil, fmt.Errorf("backup index %d out of range (valid: 1-%d)", index, len(backups))
+	}
+	return &backups[index-1], nil
+}
+
+// BackupSettings takes a timestamped backup of the current settings.json and
+// returns its path, or an empty path if there is no settings.json
+func (m *Manager) BackupSettings() (string, error) {
+	settingsPath, err := m.GetClaudeSettingsPath()
+	if err != nil {
+		return "", err
+	}
+	return backupSettings(settingsPath, false, BackupMeta{})
+}
+
+// backupSettings copies settingsPath to a new timestamped backup and writes
+// its metadata sidecar: meta completed with the time, the hash of the
+// replaced contents and any symlinked settings file. It returns an empty path
+// when settingsPath does not exist; any other failure to read it is an error,
+// since the settings would otherwise be replaced without a backup.
+func backupSettings(settingsPath string, compress bool, meta BackupMeta) (string, error) {
+	source, err := os.Open(settingsPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read settings to back up: %w", err)
+	}
+	defer source.Close()
+
+	info, err := source.Stat()
+	if err != nil {
+		return "", fmt.Errorf("failed to read settings to back up: %w", err)
+	}
+
+	if err := os.MkdirAll(BackupDir(settingsPath), 0755); err != nil {
+		return "", fmt.Errorf("failed to create backup directory: %w", err)
+	}
+	backupPath := newBackupPath(settingsPath, compress)
+
+	// Hash the contents as they stream into the backup
+	hash := sha256.New()
+	contents := io.TeeReader(source, hash)
+	write := storage.AtomicWriteFrom
+	if compress {
+		write = storage.CompressFrom
+	}
+	if err := write(backupPath, contents, info.Mode().Perm()); err != nil {
+		return "", fmt.Errorf("failed to create backup: %w", err)
+	}
+
+	meta.ReplacedHash = hex.EncodeToString(hash.Sum(nil))
+	meta.CreatedAt = time.Now()
+	if target, err := os.Readlink(settingsPath); err == nil {
+		meta.SymlinkTarget = target
+	}
+	if err := writeBackupMeta(backupPath, meta); err != nil {
+		return "", err
+	}
+	return backupPath, nil
+}
+
+// CheckBackup reads a backup, decompressing it if needed, and validates its contents
+func CheckBackup(backupPath string) error {
+	data, err := storage.ReadFile(backupPath)
+	if err != nil {
+		return fmt.Errorf("failed to read backup: %w", err)
+	}
+	if err := validation.ValidateClaudeSettings(data); err != nil {
+		return fmt.Errorf("backup is invalid: %w", err)
+	}
+	return nil
+}
+
+// LatestBackup returns the most recently taken backup of settings.json
+func (m *Manager) LatestBackup() (string, error) {
+	backups, err := m.Backups()
+	if err != nil {
+		return "", err
+	}
+
+	if len(backups) == 0 {
+		settingsPath, err := m.GetClaudeSettingsPath()
+		if err != nil {
+			return "", err
+		}
+		return "", fmt.Errorf("no backup found for %s", settingsPath)
+	}
+
+	return backups[0].Path, nil
+}
+
+// PruneOptions is the retention policy for PruneBackups. A backup is removed
+// when it falls outside either limit; zero values disable a limit.
+type PruneOptions struct {
+	// OlderThan removes backups taken longer ago than this
+	OlderThan time.Duration
+	// Keep removes all but the Keep newest backups
+	Keep int
+	// DryRun reports what would be removed without removing anything
+	DryRun bool
+}
+
+// PruneBackups removes timestamped backups outside the retention policy,
+// along with their metadata sidecars, and returns the backups removed
+func (m *Manager) PruneBackups(opts PruneOptions) ([]Backup, error) {
+	backups, err := m.Backups()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	var removed []Backup
+	kept := 0
+	for _, backup := range backups {
+		if backup.Legacy {
+			continue
+		}
+
+		expired := opts.OlderThan > 0 && now.Sub(backup.CreatedAt) > opts.OlderThan
+		excess := opts.Keep > 0 && kept >= opts.Keep
+		if !expired && !excess {
+			kept++
+			continue
+		}
+
+		if !opts.DryRun {
+			if err := os.Remove(backup.Path); err != nil && !os.IsNotExist(err) {
+				return removed, fmt.Errorf("failed to remove backup: %w", err)
+			}
+			if err := os.Remove(backupMetaPath(backup.Path)); err != nil && !os.IsNotExist(err) {
+				return removed, fmt.Errorf("failed to remove backup metadata: %w", err)
+			}
+		}
+		removed = append(removed, backup)
+	}
+
+	return removed, nil
+}
+
+// RestoreBackup restores settings.json from the given backup file,
+// decompressing it if needed, and returns the settings path written
+func (m *Manager) RestoreBackup(backupPath string) (string, error) {
+	settingsPath, err := m.GetClaudeSettingsPath()
+	if err != nil {
+		return "", err
+	}
+
+	if err := m.RestoreBackupTo(backupPath, settingsPath); err != nil {
+		return "", err
+	}
+
+	return settingsPath, nil
+}
+
+// RestoreBackupTo restores the given backup file to settingsPath, such as
+// the file an apply with ApplyOptions.SettingsPath wrote
+func (m *Manager) RestoreBackupTo(backupPath, settingsPath string) error {
+	return m.restoreFrom(backupPath, settingsPath)
+}
+
+// restoreFrom writes the (possibly compressed) backup contents to settingsPath,
+// keeping the permissions of the file it replaces, or taking those of the
+// backup when there is none. If the backup was taken of a symlink, the link
+// is recreated and the contents are written through it to the original target.
+func (m *Manager) restoreFrom(backupPath, settingsPath string) error {
+	data, err := storage.ReadFile(backupPath)
+	if err != nil {
+		return fmt.Errorf("failed to read backup: %w", err)
+	}
+
+	if err := validation.ValidateClaudeSettings(data); err != nil {
+		return fmt.Errorf("backup is invalid: %w", err)
+	}
+
+	meta, err := ReadBackupMeta(backupPath)
+	if err != nil {
+		return err
+	}
+
+	if meta.SymlinkTarget != "" {
+		if err := ensureSymlink(settingsPath, meta.SymlinkTarget); err != nil {
+			return fmt.Errorf("failed to recreate settings symlink: %w", err)
+		}
+	}
+
+	// Write through an existing link so the rename does not replace it
+	writePath := settingsPath
+	if resolved, err := filepath.EvalSymlinks(settingsPath); err == nil {
+		writePath = resolved
+	} else if meta.SymlinkTarget != "" {
+		writePath = resolveLinkTarget(settingsPath, meta.SymlinkTarget)
+	}
+
+	perm := storage.FileMode(writePath, storage.FileMode(backupPath, 0644))
+	if err := storage.AtomicWriteMode(writePath, data, perm); err != nil {
+		return fmt.Errorf("failed to restore backup: %w", err)
+	}
+
+	return nil
+}
+
+// BackupMeta records information about a settings backup, stored next to it
+// as <backup>.meta.json
+type BackupMeta struct {
+	// SymlinkTarget is where settings.json pointed when the backup was taken
+	SymlinkTarget string `json:"symlink_target,omitempty"`
+	// ConfigName and ConfigID identify the configuration whose apply
+	// replaced the backed up settings; empty for backups taken otherwise
+	ConfigName string `json:"config_name,omitempty"`
+	ConfigID   string `json:"config_id,omitempty"`
+	// CreatedAt is when the backup was taken
+	CreatedAt time.Time `json:"created_at,omitzero"`
+	// ReplacedHash is the hex SHA-256 of the settings file that was backed up
+	ReplacedHash string `json:"replaced_sha256,omitempty"`
+}
+
+// backupMetaPath returns the sidecar metadata path for a backup
+func backupMetaPath(backupPath string) string {
+	return backupPath + ".meta.json"
+}
+
+// ReadBackupMeta reads the sidecar metadata of a backup. A missing sidecar
+// yields empty metadata.
+func ReadBackupMeta(backupPath string) (BackupMeta, error) {
+	var meta BackupMeta
+
+	data, err := os.ReadFile(backupMetaPath(backupPath))
+	if os.IsNotExist(err) {
+		return meta, nil
+	}
+	if err != nil {
+		return meta, fmt.Errorf("failed to read backup metadata: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return meta, fmt.Errorf("failed to parse backup metadata: %w", err)
+	}
+
+	return meta, nil
+}
+
+// writeBackupMeta writes the sidecar metadata of a backup, removing any stale
+// sidecar when there is nothing to record
+func writeBackupMeta(backupPath string, meta BackupMeta) error {
+	metaPath := backupMetaPath(backupPath)
+
+	if meta == (BackupMeta{}) {
+		if err := os.Remove(metaPath); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove stale backup metadata: %w", err)
+		}
+		return nil
+	}
+
+	data, err := json.MarshalIndent(meta, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal backup metadata: %w", err)
+	}
+
+	if err := storage.AtomicWrite(metaPath, data); err != nil {
+		return fmt.Errorf("failed to write backup metadata: %w", err)
+	}
+
+	return nil
+}
+
+// ensureSymlink makes linkPath a symlink to target, replacing whatever is there
+func ensureSymlink(linkPath, target string) error {
+	if current, err := os.Readlink(linkPath); err == nil && current == target {
+		return nil
+	}
+
+	if err := os.Remove(linkPath); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return os.Symlink(target, linkPath)
+}
+
+// resolveLinkTarget returns the path a symlink at linkPath with the given
+// target refers to
+func resolveLinkTarget(linkPath, target string) string {
+	if filepath.IsAbs(target) {
+		return target
+	}
+	return filepath.Join(filepath.Dir(linkPath), target)
+}
@@ -0,0 +1,261 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Xanonymous-GitHub/claude-switch/internal/storage"
+)
+
+func TestBackupSettings(t *testing.T) {
+	for _, compress := range []bool{false, true} {
+		name := "plain"
+		if compress {
+			name = "compressed"
+		}
+		t.Run(name, func(t *testing.T) {
+			settingsPath := filepath.Join(t.TempDir(), "settings.json")
+			data := []byte(`{"model": "opus"}`)
+			if err := os.WriteFile(settingsPath, data, 0600); err != nil {
+				t.Fatal(err)
+			}
+
+			backupPath, err := backupSettings(settingsPath, compress, BackupMeta{ConfigName: "work"})
+			if err != nil {
+				t.Fatalf("backupSettings: %v", err)
+			}
+
+			backedUp, err := storage.ReadFile(backupPath)
+			if err != nil {
+				t.Fatalf("reading backup: %v", err)
+			}
+			if string(backedUp) != string(data) {
+				t.Errorf("backup holds %q, want %q", backedUp, data)
+			}
+			if info, err := os.Stat(backupPath); err != nil || info.Mode().Perm() != 0600 {
+				t.Errorf("backup mode = %v (%v), want 0600", info.Mode().Perm(), err)
+			}
+
+			meta, err := ReadBackupMeta(backupPath)
+			if err != nil {
+				t.Fatalf("ReadBackupMeta: %v", err)
+			}
+			sum := sha256.Sum256(data)
+			if meta.ReplacedHash != hex.EncodeToString(sum[:]) {
+				t.Errorf("ReplacedHash = %s, want the SHA-256 of the settings", meta.ReplacedHash)
+			}
+			if meta.ConfigName != "work" {
+				t.Errorf("ConfigName = %q, want work", meta.ConfigName)
+			}
+		})
+	}
+}
+
+func TestBackupSettingsMissingFile(t *testing.T) {
+	settingsPath := filepath.Join(t.TempDir(), "settings.json")
+
+	backupPath, err := backupSettings(settingsPath, false, BackupMeta{})
+	if err != nil || backupPath != "" {
+		t.Errorf("backupSettings = %q, %v; want no backup and no error", backupPath, err)
+	}
+}
+
+func TestBackupSettingsUnreadableFile(t *testing.T) {
+	// A directory opens but cannot be read, like a file with an I/O error
+	settingsPath := filepath.Join(t.TempDir(), "settings.json")
+	if err := os.Mkdir(settingsPath, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	backupPath, err := backupSettings(settingsPath, false, BackupMeta{})
+	if err == nil {
+		t.Fatalf("backupSettings = %q, nil; want an error", backupPath)
+	}
+}
+
+// BenchmarkBackupLargeSettings backs up a 64 MiB settings file; the
+// allocations per operation stay flat because the file is streamed
+func BenchmarkBackupLargeSettings(b *testing.B) {
+	settingsPath := filepath.Join(b.TempDir(), "settings.json")
+	note := make([]byte, 64<<20)
+	for i := range note {
+		note[i] = 'a' + byte(i%26)
+	}
+	data := append(append([]byte(`{"note": "`), note...), `"}`...)
+	if err := os.WriteFile(settingsPath, data, 0600); err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(data)))
+	for b.Loop() {
+		backupPath, err := backupSettings(settingsPath, false, BackupMeta{})
+		if err != nil {
+			b.Fatalf("backupSettings: %v", err)
+		}
+		b.StopTimer()
+		os.Remove(backupPath)
+		os.Remove(backupMetaPath(backupPath))
+		b.StartTimer()
+	}
+}
+
+func TestCompressedBackupRoundTrip(t *testing.T) {
+	manager := newTestManager(t)
+	const original = `{"model": "sonnet", "env": {"A": "1"}}`
+	settingsPath := writeSettingsFile(t, manager, original)
+	config := mustImport(t, manager, "work", `{"model": "opus"}`)
+
+	result, err := manager.ApplyConfigWithOptions(config.ID, ApplyOptions{CompressBackup: true})
+	if err != nil {
+		t.Fatalf("ApplyConfigWithOptions: %v", err)
+	}
+	if !storage.IsCompressed(result.BackupPath) {
+		t.Fatalf("backup %s is not compressed", result.BackupPath)
+	}
+	raw, err := os.ReadFile(result.BackupPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(raw) == original {
+		t.Error("compressed backup holds the settings uncompressed")
+	}
+
+	if _, err := manager.RestoreBackup(result.BackupPath); err != nil {
+		t.Fatalf("RestoreBackup: %v", err)
+	}
+	restored, err := os.ReadFile(settingsPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(restored) != original {
+		t.Errorf("restored settings = %q, want %q", restored, original)
+	}
+}
+
+func TestRestoreBackupKeepsMode(t *testing.T) {
+	manager := newTestManager(t)
+	settingsPath := writeSettingsFile(t, manager, `{"model": "sonnet"}`)
+	if err := os.Chmod(settingsPath, 0600); err != nil {
+		t.Fatal(err)
+	}
+	config := mustImport(t, manager, "work", `{"model": "opus"}`)
+
+	result, err := manager.ApplyConfigWithOptions(config.ID, ApplyOptions{})
+	if err != nil {
+		t.Fatalf("ApplyConfigWithOptions: %v", err)
+	}
+	assertMode(t, settingsPath, 0600)
+
+	if _, err := manager.RestoreBackup(result.BackupPath); err != nil {
+		t.Fatalf("RestoreBackup: %v", err)
+	}
+	assertMode(t, settingsPath, 0600)
+
+	// Without settings to replace, the restored file takes the backup's mode
+	if err := os.Remove(settingsPath); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := manager.RestoreBackup(result.BackupPath); err != nil {
+		t.Fatalf("RestoreBackup: %v", err)
+	}
+	assertMode(t, settingsPath, 0600)
+}
+
+func TestApplyRollbackKeepsMode(t *testing.T) {
+	manager := newTestManager(t)
+	settingsPath := writeSettingsFile(t, manager, `{"model": "sonnet"}`)
+	if err := os.Chmod(settingsPath, 0600); err != nil {
+		t.Fatal(err)
+	}
+	config := mustImport(t, manager, "work", `{"model": "opus"}`)
+
+	withWriteSettings(t, func(path string, data []byte, perm os.FileMode) error {
+		return os.WriteFile(path, data[:len(data)/2], perm)
+	})
+
+	if _, err := manager.ApplyConfigWithOptions(config.ID, ApplyOptions{}); err == nil {
+		t.Fatal("ApplyConfigWithOptions succeeded despite the damaged write")
+	}
+	assertMode(t, settingsPath, 0600)
+}
+
+func assertMode(t *testing.T, path string, want os.FileMode) {
+	t.Helper()
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("stat %s: %v", path, err)
+	}
+	if got := info.Mode().Perm(); got != want {
+		t.Errorf("%s mode = %v, want %v", path, got, want)
+	}
+}
+
+func TestBackupsMixCompressedAndPlain(t *testing.T) {
+	manager := newTestManager(t)
+	writeSettingsFile(t, manager, `{"model": "sonnet"}`)
+	config := mustImport(t, manager, "work", `{"model": "opus"}`)
+
+	for _, compress := range []bool{false, true} {
+		if _, err := manager.ApplyConfigWithOptions(config.ID, ApplyOptions{CompressBackup: compress}); err != nil {
+			t.Fatalf("ApplyConfigWithOptions: %v", err)
+		}
+	}
+
+	backups, err := manager.Backups()
+	if err != nil {
+		t.Fatalf("Backups: %v", err)
+	}
+	if len(backups) != 2 {
+		t.Fatalf("Backups returned %d backups, want 2", len(backups))
+	}
+	compressed := 0
+	for _, backup := range backups {
+		if storage.IsCompressed(backup.Path) {
+			compressed++
+		}
+		data, err := storage.ReadFile(backup.Path)
+		if err != nil {
+			t.Fatalf("reading %s: %v", backup.Path, err)
+		}
+		if err := manager.ValidateSettings(data); err != nil {
+			t.Errorf("%s does not hold valid settings: %v", backup.Path, err)
+		}
+	}
+	if compressed != 1 {
+		t.Errorf("%d compressed backups, want 1", compressed)
+	}
+}
+
+func TestNewBackupPathInDirectoryNamedJSON(t *testing.T) {
+	for _, compress := range []bool{false, true} {
+		// A directory name containing .json must not be mistaken for the extension
+		settingsPath := filepath.Join(t.TempDir(), "project.json", ".claude", "settings.json")
+
+		first := newBackupPath(settingsPath, compress)
+		if err := os.MkdirAll(filepath.Dir(first), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(first, []byte(`{}`), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		second := newBackupPath(settingsPath, compress)
+		if filepath.Dir(second) != filepath.Dir(first) {
+			t.Errorf("second backup %s is not next to %s", second, first)
+		}
+		ext := ".json"
+		if compress {
+			ext = ".json.gz"
+		}
+		want := strings.TrimSuffix(filepath.Base(first), ext) + "-2" + ext
+		if filepath.Base(second) != want {
+			t.Errorf("second backup is named %s, want %s", filepath.Base(second), want)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Rename is one change of a bulk rename
+type Rename struct {
+	Config  Config
+	NewName string
+}
+
+// PlanRenames substitutes replace for match in the name of every
+// configuration match matches, as regexp.ReplaceAllString does (so $1 refers
+// to a group), and returns the renames sorted by current name. Names that do
+// not change are left out. A new name that another configuration keeps or
+// that two renames produce is an ErrConfigExists error listing every
+// collision, unless autoName is set, in which case a numeric suffix is added
+// as with add --auto-name. Nothing is changed.
+func (m *Manager) PlanRenames(match *regexp.Regexp, replace string, autoName bool) ([]Rename, error) {
+	var renames []Rename
+	renamed := map[string]bool{}
+	for _, config := range m.configs {
+		if !match.MatchString(config.Name) {
+			continue
+		}
+		if newName := match.ReplaceAllString(config.Name, replace); newName != config.Name {
+			renames = append(renames, Rename{Config: config, NewName: newName})
+			renamed[config.ID] = true
+		}
+	}
+	sort.Slice(renames, func(i, j int) bool { return renames[i].Config.Name < renames[j].Config.Name })
+
+	// Names held after the batch by configurations it does not rename
+	takenBy := map[string]string{}
+	for _, config := range m.configs {
+		if !renamed[config.ID] {
+			takenBy[config.Name] = fmt.Sprintf("kept by '%s'", config.Name)
+		}
+	}
+
+	var collisions []string
+	for i := range renames {
+		rename := &renames[i]
+		if err := m.checkName(rename.NewName); err != nil {
+			return nil, fmt.Errorf("cannot rename '%s': %w", rename.Config.Name, err)
+		}
+
+		if holder, taken := takenBy[rename.NewName]; taken {
+			if !autoName {
+				collisions = append(collisions, fmt.Sprintf("'%s' -> '%s' (%s)", rename.Config.Name, rename.NewName, holder))
+				continue
+			}
+			base := rename.NewName
+			for n := 2; takenBy[rename.NewName] != ""; n++ {
+				rename.NewName = fmt.Sprintf("%s (%d)", base, n)
+			}
+		}
+		takenBy[rename.NewName] = fmt.Sprintf("also produced from '%s'", rename.Config.Name)
+	}
+
+	if len(collisions) > 0 {
+		return nil, fmt.Errorf("%w: %s", ErrConfigExists, strings.Join(collisions, "; "))
+	}
+	return renames, nil
+}
+
+// ApplyRenames renames configurations as planned by PlanRenames, saving the
+// metadata once
+func (m *Manager) ApplyRenames(renames []Rename) error {
+	newNames := make(map[string]string, len(renames))
+	for _, rename := range renames {
+		newNames[rename.Config.ID] = rename.NewName
+	}
+
+	for i := range m.configs {
+		if newName, ok := newNames[m.configs[i].ID]; ok {
+			m.configs[i].Name = newName
+		}
+	}
+
+	if err := m.saveConfigs(); err != nil {
+		return fmt.Errorf("failed to update config metadata: %w", err)
+	}
+	return nil
+}
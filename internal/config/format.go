@@ -0,0 +1,130 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/Xanonymous-GitHub/claude-switch/internal/jsonutil"
+	"github.com/Xanonymous-GitHub/claude-switch/internal/validation"
+)
+
+// Formats a configuration can be stored in. Claude Code reads JSON, so
+// configurations stored as TOML are converted when applied.
+const (
+	FormatJSON = "json"
+	FormatTOML = "toml"
+)
+
+// FormatJSONC is a source format only: JSON with comments and trailing
+// commas, read by add --file and import and stored as strict JSON
+const FormatJSONC = "jsonc"
+
+// SourceFormat returns the format a file's extension implies: FormatJSONC
+// for .jsonc and .json5, and "" for anything else
+func SourceFormat(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".jsonc", ".json5":
+		return FormatJSONC
+	default:
+		return ""
+	}
+}
+
+// ParseFormat normalizes a format name; an empty name means JSON
+func ParseFormat(name string) (string, error) {
+	switch name {
+	case "", FormatJSON:
+		return FormatJSON, nil
+	case FormatTOML:
+		return FormatTOML, nil
+	default:
+		return "", fmt.Errorf("unsupported format '%s' (valid: json, toml)", name)
+	}
+}
+
+// StoredFormat returns the format the configuration file is stored in
+func (c Config) StoredFormat() string {
+	if c.Format == "" {
+		return FormatJSON
+	}
+	return c.Format
+}
+
+// ToJSON converts configuration data stored in format to JSON. A UTF-8 byte
+// order mark is dropped and UTF-16 data is rejected.
+func ToJSON(data []byte, format string) ([]byte, error) {
+	data, err := validation.NormalizeEncoding(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if format != FormatTOML {
+		return data, nil
+	}
+
+	var settings map[string]interface{}
+	if err := toml.Unmarshal(data, &settings); err != nil {
+		return nil, fmt.Errorf("invalid TOML: %w", err)
+	}
+	if settings == nil {
+		settings = map[string]interface{}{}
+	}
+
+	return jsonutil.MarshalIndent(settings)
+}
+
+// FromJSON converts JSON settings to format. JSON null has no TOML
+// equivalent and is rejected.
+func FromJSON(data []byte, format string) ([]byte, error) {
+	if format != FormatTOML {
+		return data, nil
+	}
+
+	// Keep numbers as written so integers do not become floats
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var settings map[string]interface{}
+	if err := decoder.Decode(&settings); err != nil {
+		return nil, fmt.Errorf("invalid JSON format: %w", err)
+	}
+	if path, ok := findNull(settings, ""); ok {
+		return nil, fmt.Errorf("cannot convert to TOML: null value at %s", path)
+	}
+
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(settings); err != nil {
+		return nil, fmt.Errorf("failed to encode TOML: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// findNull returns the path of the first null inside value, if any
+func findNull(value interface{}, path string) (string, bool) {
+	switch v := value.(type) {
+	case nil:
+		return path, true
+	case map[string]interface{}:
+		for key, child := range v {
+			childPath := key
+			if path != "" {
+				childPath = path + "." + key
+			}
+			if found, ok := findNull(child, childPath); ok {
+				return found, true
+			}
+		}
+	case []interface{}:
+		for i, child := range v {
+			if found, ok := findNull(child, fmt.Sprintf("%s[%d]", path, i)); ok {
+				return found, true
+			}
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,123 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// DirEnv overrides the store directory when set
+const DirEnv = "CLAUDE_SWITCH_DIR"
+
+// ErrMigrateStore is returned when the store cannot be moved to the XDG location
+var ErrMigrateStore = errors.New("cannot migrate config store")
+
+// DefaultDir returns the store directory: $CLAUDE_SWITCH_DIR when set,
+// otherwise the XDG location (see XDGDir) if it exists, otherwise
+// ~/.claude-switch. Windows always uses ~/.claude-switch.
+func DefaultDir() (string, error) {
+	if dir := os.Getenv(DirEnv); dir != "" {
+		return dir, nil
+	}
+
+	if xdgDir, err := XDGDir(); err == nil {
+		if info, err := os.Stat(xdgDir); err == nil && info.IsDir() {
+			return xdgDir, nil
+		}
+	}
+
+	return LegacyDir()
+}
+
+// LegacyDir returns ~/.claude-switch, the store directory used before XDG
+// support and on Windows
+func LegacyDir() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get user home directory: %w", err)
+	}
+	return filepath.Join(homeDir, ".claude-switch"), nil
+}
+
+// XDGDir returns $XDG_CONFIG_HOME/claude-switch, or
+// ~/.config/claude-switch when XDG_CONFIG_HOME is unset or relative. It is
+// not available on Windows.
+func XDGDir() (string, error) {
+	if runtime.GOOS == "windows" {
+		return "", fmt.Errorf("the XDG store location is not used on Windows")
+	}
+
+	// The XDG spec says relative paths are invalid and must be ignored
+	if configHome := os.Getenv("XDG_CONFIG_HOME"); filepath.IsAbs(configHome) {
+		return filepath.Join(configHome, "claude-switch"), nil
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get user home directory: %w", err)
+	}
+	return filepath.Join(homeDir, ".config", "claude-switch"), nil
+}
+
+// MigrateStore moves the store at ~/.claude-switch to the XDG location and
+// updates the file paths recorded in its metadata. It returns the old and
+// new store directories.
+func MigrateStore() (string, string, error) {
+	if os.Getenv(DirEnv) != "" {
+		return "", "", fmt.Errorf("%w: the store location is set by %s", ErrMigrateStore, DirEnv)
+	}
+
+	from, err := LegacyDir()
+	if err != nil {
+		return "", "", err
+	}
+	to, err := XDGDir()
+	if err != nil {
+		return "", "", fmt.Errorf("%w: %v", ErrMigrateStore, err)
+	}
+
+	if _, err := os.Stat(from); err != nil {
+		return from, to, fmt.Errorf("%w: no store at %s", ErrMigrateStore, from)
+	}
+	if _, err := os.Stat(to); err == nil {
+		return from, to, fmt.Errorf("%w: %s already exists", ErrMigrateStore, to)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(to), 0755); err != nil {
+		return from, to, fmt.Errorf("failed to create %s: %w", filepath.Dir(to), err)
+	}
+	if err := os.Rename(from, to); err != nil {
+		return from, to, fmt.Errorf("failed to move config store: %w", err)
+	}
+
+	// Configurations record absolute file paths under the old directory;
+	// put the store back if they cannot be updated
+	if err := rebaseStore(from, to); err != nil {
+		if moveErr := os.Rename(to, from); moveErr != nil {
+			return from, to, fmt.Errorf("%w (and moving the store back failed: %v)", err, moveErr)
+		}
+		return from, to, err
+	}
+
+	return from, to, nil
+}
+
+// rebaseStore rewrites the file paths in the metadata of the store now in
+// to that still point into from
+func rebaseStore(from, to string) error {
+	manager, err := NewManagerWithOptions(WithDir(to), WithoutAutoCreate(), WithStrict())
+	if err != nil {
+		return err
+	}
+
+	for i := range manager.configs {
+		rel, err := filepath.Rel(from, manager.configs[i].FilePath)
+		if err == nil && !strings.HasPrefix(rel, "..") {
+			manager.configs[i].FilePath = filepath.Join(to, rel)
+		}
+	}
+	return manager.saveConfigs()
+}
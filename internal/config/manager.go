@@ -1,62 +1,143 @@
 package config
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/Xanonymous-GitHub/claude-switch/internal/jsonutil"
+	"github.com/Xanonymous-GitHub/claude-switch/internal/storage"
 	"github.com/Xanonymous-GitHub/claude-switch/internal/validation"
 	"github.com/google/uuid"
 )
 
+// Sentinel errors returned by Manager operations, usable with errors.Is
+var (
+	ErrConfigNotFound = errors.New("config not found")
+	ErrConfigExists   = errors.New("config already exists")
+	ErrEmptyName      = errors.New("config name cannot be empty")
+	ErrInvalidName    = errors.New("invalid config name")
+	ErrAmbiguousID    = errors.New("ambiguous config identifier")
+	ErrConfigTooLarge = errors.New("config file too large")
+	ErrStoreNotFound  = errors.New("config store not found")
+	ErrNoDefault      = errors.New("no default config set")
+	ErrNoActive       = errors.New("no config matches the current settings")
+	ErrNoTracked      = errors.New("no tracked config")
+	ErrSchemaTooNew   = errors.New("config store was written by a newer version of claude-switch")
+	ErrBackupTooLarge = errors.New("settings file too large to back up")
+	ErrWriteCheck     = errors.New("written settings failed verification")
+
+	ErrUnknownPreference = errors.New("unknown preference")
+	ErrRevisionNotFound  = errors.New("revision not found")
+)
+
+// DefaultMaxConfigSize is the default limit on stored config file size (5 MB)
+const DefaultMaxConfigSize int64 = 5 * 1024 * 1024
+
 // Config represents a single Claude Code configuration
 type Config struct {
-	ID          string    `json:"id"`
-	Name        string    `json:"name"`
-	Description string    `json:"description"`
-	CreatedAt   time.Time `json:"created_at"`
-	FilePath    string    `json:"file_path"`
+	ID            string    `json:"id"`
+	Name          string    `json:"name"`
+	Description   string    `json:"description"`
+	CreatedAt     time.Time `json:"created_at"`
+	FilePath      string    `json:"file_path"`
+	ClaudeVersion string    `json:"claude_version,omitempty"`
+	Default       bool      `json:"default,omitempty"`
+	Format        string    `json:"format,omitempty"`
+	// SourceFormat is the format the configuration was read from when it
+	// differs from Format, such as FormatJSONC
+	SourceFormat string `json:"source_format,omitempty"`
+	// Tracked marks the configuration last applied with --track, which
+	// 'capture' writes live settings back into
+	Tracked bool `json:"tracked,omitempty"`
 }
 
 // Manager handles configuration operations
 type Manager struct {
-	configDir string
-	configs   []Config
+	configDir     string
+	configs       []Config
+	prefs         map[string]string
+	maxConfigSize int64
+	// settingsSchema, when set, is checked by validation in addition to
+	// the built-in checks
+	settingsSchema *validation.Schema
+	// validateDenied makes validation reject denied keys, see
+	// EnableDeniedKeyValidation
+	validateDenied bool
+	// validateRequired makes validation reject configurations missing a
+	// required key, see EnableRequiredKeyValidation
+	validateRequired bool
 }
 
-// NewManager creates a new configuration manager
+// NewManager creates a new configuration manager backed by ~/.claude-switch,
+// creating the store directories if needed
 func NewManager() (*Manager, error) {
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get user home directory: %w", err)
-	}
-
-	configDir := filepath.Join(homeDir, ".claude-switch")
+	return NewManagerWithOptions()
+}
 
-	// Create config directory if it doesn't exist
-	if err := os.MkdirAll(configDir, 0755); err != nil {
-		return nil, fmt.Errorf("failed to create config directory: %w", err)
+// NewManagerWithOptions creates a new configuration manager configured by opts
+func NewManagerWithOptions(opts ...Option) (*Manager, error) {
+	options := managerOptions{autoCreate: true}
+	for _, opt := range opts {
+		opt(&options)
 	}
 
-	// Create configs subdirectory
+	configDir := options.dir
+	if configDir == "" {
+		var err error
+		if configDir, err = DefaultDir(); err != nil {
+			return nil, err
+		}
+	}
 	configsDir := filepath.Join(configDir, "configs")
-	if err := os.MkdirAll(configsDir, 0755); err != nil {
-		return nil, fmt.Errorf("failed to create configs directory: %w", err)
+
+	if options.autoCreate {
+		// Create config directory if it doesn't exist
+		if err := os.MkdirAll(configDir, 0755); err != nil {
+			return nil, fmt.Errorf("failed to create config directory: %w", err)
+		}
+
+		// Create configs subdirectory
+		if err := os.MkdirAll(configsDir, 0755); err != nil {
+			return nil, fmt.Errorf("failed to create configs directory: %w", err)
+		}
+	} else {
+		for _, dir := range []string{configDir, configsDir} {
+			if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+				return nil, fmt.Errorf("%w: %s", ErrStoreNotFound, dir)
+			}
+		}
 	}
 
 	manager := &Manager{
-		configDir: configDir,
+		configDir:     configDir,
+		maxConfigSize: DefaultMaxConfigSize,
 	}
 
-	if err := manager.loadConfigs(); err != nil {
+	if err := manager.loadConfigs(options); err != nil {
 		return nil, fmt.Errorf("failed to load configurations: %w", err)
 	}
 
+	if err := manager.loadPreferences(); err != nil {
+		return nil, err
+	}
+
 	return manager, nil
 }
 
+// GetConfigDir returns the directory holding the configuration store
+func (m *Manager) GetConfigDir() string {
+	return m.configDir
+}
+
 // GetClaudeDir returns the Claude directory path
 func (m *Manager) GetClaudeDir() (string, error) {
 	homeDir, err := os.UserHomeDir()
@@ -75,40 +156,259 @@ func (m *Manager) GetClaudeSettingsPath() (string, error) {
 	return filepath.Join(claudeDir, "settings.json"), nil
 }
 
+// ClaudeVersionEnv is the environment variable consulted for the Claude Code version
+const ClaudeVersionEnv = "CLAUDE_CODE_VERSION"
+
+// DetectClaudeVersion returns the installed Claude Code version from the
+// CLAUDE_CODE_VERSION environment variable or the ~/.claude/version file.
+// It returns an empty string when the version cannot be determined.
+func (m *Manager) DetectClaudeVersion() string {
+	if version := strings.TrimSpace(os.Getenv(ClaudeVersionEnv)); version != "" {
+		return version
+	}
+
+	claudeDir, err := m.GetClaudeDir()
+	if err != nil {
+		return ""
+	}
+
+	data, err := os.ReadFile(filepath.Join(claudeDir, "version"))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// AddOptions controls how a configuration is added
+type AddOptions struct {
+	// AutoName appends " (2)", " (3)", ... to the name on a collision instead of failing
+	AutoName bool
+	// ClaudeVersion records the Claude Code version the config targets
+	ClaudeVersion string
+	// Format is the format of the data and the stored file (default JSON)
+	Format string
+	// SourceFormat records the format the data was converted from, if any
+	SourceFormat string
+}
+
 // AddConfig creates a new configuration from temporary file
 func (m *Manager) AddConfig(tempFile, name, description string) (*Config, error) {
+	return m.AddConfigWithOptions(tempFile, name, description, AddOptions{})
+}
+
+// AddConfigWithOptions creates a new configuration from temporary file using the
+// given options. The returned Config carries the final name chosen.
+func (m *Manager) AddConfigWithOptions(tempFile, name, description string, opts AddOptions) (*Config, error) {
 	// Validate inputs
 	if name == "" {
-		return nil, fmt.Errorf("config name cannot be empty")
+		return nil, ErrEmptyName
 	}
 
-	// Validate JSON in temporary file before proceeding
-	if err := validation.ValidateClaudeSettingsFile(tempFile); err != nil {
-		return nil, fmt.Errorf("invalid configuration file: %w", err)
+	// Reject oversized files before they land permanently in the store
+	size, err := storage.GetFileSize(tempFile)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.checkSize(size); err != nil {
+		return nil, err
 	}
 
-	// Check if name already exists
-	for _, config := range m.configs {
-		if config.Name == name {
-			return nil, fmt.Errorf("config with name '%s' already exists", name)
+	data, err := os.ReadFile(tempFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	return m.store(name, description, data, opts)
+}
+
+// ImportConfig validates data and stores it as a new configuration. It is the
+// programmatic equivalent of AddConfig for callers that hold the bytes directly.
+func (m *Manager) ImportConfig(name, description string, data []byte) (*Config, error) {
+	return m.ImportConfigWithOptions(name, description, data, AddOptions{})
+}
+
+// ImportConfigWithOptions is ImportConfig using the given options
+func (m *Manager) ImportConfigWithOptions(name, description string, data []byte, opts AddOptions) (*Config, error) {
+	if name == "" {
+		return nil, ErrEmptyName
+	}
+
+	if err := m.checkSize(int64(len(data))); err != nil {
+		return nil, err
+	}
+
+	return m.store(name, description, data, opts)
+}
+
+// ExportConfig returns the stored contents of a configuration
+func (m *Manager) ExportConfig(identifier string) ([]byte, error) {
+	config, err := m.Resolve(identifier)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(config.FilePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	return data, nil
+}
+
+// ConfigJSON returns the contents of a configuration as JSON, converting it
+// from its stored format if needed
+func (m *Manager) ConfigJSON(identifier string) ([]byte, error) {
+	config, err := m.Resolve(identifier)
+	if err != nil {
+		return nil, err
+	}
+
+	return m.readJSON(config, config.FilePath)
+}
+
+// readJSON reads a file holding contents of config and converts them to JSON
+func (m *Manager) readJSON(config *Config, path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	return ToJSON(data, config.StoredFormat())
+}
+
+// validateStored validates the stored file of config in its format
+func (m *Manager) validateStored(config *Config) error {
+	data, err := m.loadSource(config, config.FilePath, ApplyOptions{})
+	if err != nil {
+		return err
+	}
+	return m.ValidateSettings(data)
+}
+
+// ValidateSettings validates JSON settings that are not in the store with
+// the checks stored configurations get: the built-in checks, denied and
+// required keys when enabled, and the settings schema when one is set
+func (m *Manager) ValidateSettings(data []byte) error {
+	if err := validation.ValidateClaudeSettings(data); err != nil {
+		return err
+	}
+	if m.validateDenied {
+		if err := m.checkDeniedKeys(data); err != nil {
+			return err
+		}
+	}
+	if m.validateRequired {
+		if err := m.checkRequiredKeys(data); err != nil {
+			return err
 		}
 	}
+	if m.settingsSchema != nil {
+		return m.settingsSchema.Validate(data)
+	}
+	return nil
+}
+
+// fileExt returns the extension of config files stored in format
+func fileExt(format string) string {
+	if format == FormatTOML {
+		return ".toml"
+	}
+	return ".json"
+}
+
+// CheckImport runs the checks ImportConfigWithOptions makes without storing
+// anything, and returns the name the configuration would be saved as. Names
+// for which taken returns true count as used in addition to stored ones,
+// such as the names claimed by earlier files of a dry run.
+func (m *Manager) CheckImport(name string, data []byte, opts AddOptions, taken func(string) bool) (string, error) {
+	if name == "" {
+		return "", ErrEmptyName
+	}
+
+	if err := m.checkSize(int64(len(data))); err != nil {
+		return "", err
+	}
+
+	name, _, _, err := m.prepare(name, data, opts, func(name string) bool {
+		return m.Exists(name) || taken(name)
+	})
+	return name, err
+}
+
+// prepare validates data for a new configuration and resolves its name,
+// with taken reporting the names in use. It returns the name, the format to
+// record and the data to store.
+func (m *Manager) prepare(name string, data []byte, opts AddOptions, taken func(string) bool) (string, string, []byte, error) {
+	format, err := ParseFormat(opts.Format)
+	if err != nil {
+		return "", "", nil, err
+	}
+
+	// Files are stored as UTF-8 without a byte order mark
+	if data, err = validation.NormalizeEncoding(data); err != nil {
+		return "", "", nil, fmt.Errorf("invalid configuration file: %w", err)
+	}
+
+	// Validate the settings before proceeding
+	settings, err := ToJSON(data, format)
+	if err != nil {
+		return "", "", nil, fmt.Errorf("invalid configuration file: %w", err)
+	}
+	if err := validation.ValidateClaudeSettings(settings); err != nil {
+		return "", "", nil, fmt.Errorf("invalid configuration file: %w", err)
+	}
+
+	// JSON stays implicit so existing metadata is unchanged
+	if format == FormatJSON {
+		format = ""
+	}
+
+	// Check if name already exists
+	if opts.AutoName {
+		name = uniqueName(name, taken)
+	}
+	if err := m.checkName(name); err != nil {
+		return "", "", nil, err
+	}
+	if taken(name) {
+		return "", "", nil, fmt.Errorf("%w: '%s'", ErrConfigExists, name)
+	}
+
+	return name, format, data, nil
+}
+
+// store validates data and persists it as a new configuration with metadata
+func (m *Manager) store(name, description string, data []byte, opts AddOptions) (*Config, error) {
+	name, format, data, err := m.prepare(name, data, opts, m.Exists)
+	if err != nil {
+		return nil, err
+	}
 
 	// Generate unique ID
 	id := uuid.New().String()
 
 	// Create config object
 	config := Config{
-		ID:          id,
-		Name:        name,
-		Description: description,
-		CreatedAt:   time.Now(),
-		FilePath:    filepath.Join(m.configDir, "configs", id+".json"),
+		ID:            id,
+		Name:          name,
+		Description:   description,
+		CreatedAt:     time.Now(),
+		FilePath:      filepath.Join(m.configDir, "configs", id+fileExt(format)),
+		ClaudeVersion: opts.ClaudeVersion,
+		Format:        format,
+		SourceFormat:  opts.SourceFormat,
 	}
 
-	// Copy temp file to permanent location
-	if err := copyFile(tempFile, config.FilePath); err != nil {
-		return nil, fmt.Errorf("failed to copy config file: %w", err)
+	// Write config file to permanent location
+	if err := os.WriteFile(config.FilePath, data, 0644); err != nil {
+		return nil, fmt.Errorf("failed to write config file: %w", err)
+	}
+
+	// Record the initial contents as revision 1
+	if _, err := m.saveRevision(&config, data); err != nil {
+		os.Remove(config.FilePath)
+		os.RemoveAll(m.revisionsDir(&config))
+		return nil, err
 	}
 
 	// Add to configs list
@@ -117,78 +417,826 @@ func (m *Manager) AddConfig(tempFile, name, description string) (*Config, error)
 	// Save configs metadata
 	if err := m.saveConfigs(); err != nil {
 		// Clean up created file on error
+		m.configs = m.configs[:len(m.configs)-1]
 		os.Remove(config.FilePath)
+		os.RemoveAll(m.revisionsDir(&config))
 		return nil, fmt.Errorf("failed to save config metadata: %w", err)
 	}
 
 	return &config, nil
 }
 
+// SetMaxConfigSize sets the maximum size in bytes of config files accepted
+// into the store. A limit of 0 disables the check.
+func (m *Manager) SetMaxConfigSize(limit int64) {
+	m.maxConfigSize = limit
+}
+
+// checkSize returns ErrConfigTooLarge if size exceeds the size limit
+func (m *Manager) checkSize(size int64) error {
+	if m.maxConfigSize <= 0 {
+		return nil
+	}
+
+	if size > m.maxConfigSize {
+		return fmt.Errorf("%w: %s exceeds the limit of %s",
+			ErrConfigTooLarge, storage.FormatSize(size), storage.FormatSize(m.maxConfigSize))
+	}
+
+	return nil
+}
+
+// uniqueName returns base if it is free, otherwise the first of
+// "base (2)", "base (3)", ... for which taken returns false
+func uniqueName(base string, taken func(string) bool) string {
+	name := base
+	for n := 2; taken(name); n++ {
+		name = fmt.Sprintf("%s (%d)", base, n)
+	}
+	return name
+}
+
+// Exists reports whether a configuration with exactly this name exists
+func (m *Manager) Exists(name string) bool {
+	return slices.ContainsFunc(m.configs, func(c Config) bool { return c.Name == name })
+}
+
+// ExistsID reports whether a configuration with exactly this full ID exists
+func (m *Manager) ExistsID(id string) bool {
+	return slices.ContainsFunc(m.configs, func(c Config) bool { return c.ID == id })
+}
+
 // GetConfigs returns all configurations
 func (m *Manager) GetConfigs() []Config {
 	return m.configs
 }
 
-// GetConfig returns a specific configuration by ID or name
+// GetConfig returns a specific configuration by name, ID, or unique ID prefix
 func (m *Manager) GetConfig(identifier string) (*Config, error) {
+	return m.Resolve(identifier)
+}
+
+// Resolve finds a configuration by exact name, full ID, or unique ID prefix,
+// in that order of precedence
+func (m *Manager) Resolve(identifier string) (*Config, error) {
+	if identifier == "" {
+		return nil, fmt.Errorf("%w: empty identifier", ErrConfigNotFound)
+	}
+
+	for _, config := range m.configs {
+		if config.Name == identifier {
+			return &config, nil
+		}
+	}
+
+	for _, config := range m.configs {
+		if config.ID == identifier {
+			return &config, nil
+		}
+	}
+
+	var matches []Config
+	for _, config := range m.configs {
+		if strings.HasPrefix(config.ID, identifier) {
+			matches = append(matches, config)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("%w: %s", ErrConfigNotFound, identifier)
+	case 1:
+		return &matches[0], nil
+	default:
+		names := make([]string, len(matches))
+		for i, match := range matches {
+			names[i] = match.Name
+		}
+		return nil, fmt.Errorf("%w: '%s' matches %d configs (%s)",
+			ErrAmbiguousID, identifier, len(matches), strings.Join(names, ", "))
+	}
+}
+
+// RenameConfig changes the name of a configuration
+func (m *Manager) RenameConfig(identifier, newName string) (*Config, error) {
+	config, err := m.CheckRename(identifier, newName)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range m.configs {
+		if m.configs[i].ID == config.ID {
+			m.configs[i].Name = newName
+			renamed := m.configs[i]
+			config = &renamed
+			break
+		}
+	}
+
+	if err := m.saveConfigs(); err != nil {
+		return nil, fmt.Errorf("failed to update config metadata: %w", err)
+	}
+
+	return config, nil
+}
+
+// CheckNewName checks that a new configuration could be saved as name,
+// without changing anything
+func (m *Manager) CheckNewName(name string) error {
+	if err := m.checkName(name); err != nil {
+		return err
+	}
+	if m.Exists(name) {
+		return fmt.Errorf("%w: '%s'", ErrConfigExists, name)
+	}
+	return nil
+}
+
+// CheckRename resolves identifier and checks that it can be renamed to
+// newName, without changing anything
+func (m *Manager) CheckRename(identifier, newName string) (*Config, error) {
+	if err := m.checkName(newName); err != nil {
+		return nil, err
+	}
+
+	config, err := m.Resolve(identifier)
+	if err != nil {
+		return nil, err
+	}
+
+	if newName != config.Name && m.Exists(newName) {
+		return nil, fmt.Errorf("%w: '%s'", ErrConfigExists, newName)
+	}
+
+	return config, nil
+}
+
+// DefaultConfig returns the configuration marked as default
+func (m *Manager) DefaultConfig() (*Config, error) {
 	for _, config := range m.configs {
-		if config.ID == identifier || config.Name == identifier {
+		if config.Default {
 			return &config, nil
 		}
 	}
-	return nil, fmt.Errorf("config not found: %s", identifier)
+	return nil, ErrNoDefault
+}
+
+// SetDefault marks a configuration as the default, replacing any previous default
+func (m *Manager) SetDefault(identifier string) (*Config, error) {
+	config, err := m.Resolve(identifier)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range m.configs {
+		m.configs[i].Default = m.configs[i].ID == config.ID
+	}
+	config.Default = true
+
+	if err := m.saveConfigs(); err != nil {
+		return nil, fmt.Errorf("failed to update config metadata: %w", err)
+	}
+
+	return config, nil
+}
+
+// UnsetDefault clears the default marker and returns the configuration that
+// held it
+func (m *Manager) UnsetDefault() (*Config, error) {
+	config, err := m.DefaultConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range m.configs {
+		m.configs[i].Default = false
+	}
+
+	if err := m.saveConfigs(); err != nil {
+		return nil, fmt.Errorf("failed to update config metadata: %w", err)
+	}
+
+	config.Default = false
+	return config, nil
+}
+
+// ApplyOptions controls how a configuration is applied
+type ApplyOptions struct {
+	// CompressBackup stores the backup of the previous settings gzip-compressed
+	CompressBackup bool
+	// ReplaceSymlink replaces a symlinked settings.json with a regular file
+	// instead of writing through the link to its target
+	ReplaceSymlink bool
+	// Merge deep-merges the configuration into the current settings instead of replacing them
+	Merge bool
+	// MergeStrategy is how Merge combines arrays, one of
+	// jsonutil.ArrayStrategies; empty replaces them
+	MergeStrategy string
+	// MergeChoices are the values to use at conflicting key paths when
+	// merging (see MergeConflicts); other conflicts take the
+	// configuration's value
+	MergeChoices map[string]interface{}
+	// OnlyKeys restricts the applied configuration to these top-level keys (requires Merge)
+	OnlyKeys []string
+	// DropKeys removes these top-level keys from the applied configuration (requires Merge)
+	DropKeys []string
+	// Revision applies this stored revision instead of the current contents (0 for current)
+	Revision int
+	// Render executes the configuration as a Go template with Vars. Without
+	// it the configuration is used as stored, so settings that contain {{
+	// for another tool, such as a status line command, are left alone.
+	Render bool
+	// Vars are the values for template placeholders in the configuration
+	Vars map[string]string
+	// AllowMissing renders placeholders without a value as empty instead of failing
+	AllowMissing bool
+	// SettingsKey replaces only the value at this dot-separated key path in
+	// the current settings with the configuration's value at the same path
+	SettingsKey string
+	// SettingsPath is the settings file to write instead of
+	// ~/.claude/settings.json; its directory is created if needed
+	SettingsPath string
+	// BackupMaxBytes limits the size of the settings file that is backed up
+	// (0 for no limit). A larger file fails the apply with ErrBackupTooLarge
+	// unless SkipOversizeBackup is set.
+	BackupMaxBytes int64
+	// SkipOversizeBackup applies without a backup when the settings file
+	// exceeds BackupMaxBytes
+	SkipOversizeBackup bool
+	// AllowUnsafe applies configurations that set keys denied by the
+	// apply.deniedKeys preference
+	AllowUnsafe bool
+	// Track records the configuration as the one ~/.claude/settings.json
+	// came from. Any other apply to ~/.claude/settings.json clears it.
+	Track bool
+}
+
+// ApplyResult describes the outcome of a successful apply
+type ApplyResult struct {
+	Config       *Config
+	SettingsPath string
+	// BackupPath is empty when there was no previous settings.json to back up
+	BackupPath string
+	// SymlinkTarget is where settings.json pointed, empty if it was not a symlink
+	SymlinkTarget string
+	// BackupSkipped is set when the settings file exceeded
+	// ApplyOptions.BackupMaxBytes and was replaced without a backup
+	BackupSkipped bool
 }
 
 // ApplyConfig switches to the specified configuration
 func (m *Manager) ApplyConfig(identifier string) error {
+	_, err := m.ApplyConfigWithOptions(identifier, ApplyOptions{})
+	return err
+}
+
+// ApplyConfigWithOptions switches to the specified configuration using the given options
+func (m *Manager) ApplyConfigWithOptions(identifier string, opts ApplyOptions) (*ApplyResult, error) {
 	config, err := m.GetConfig(identifier)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	// Validate the configuration file before applying
-	if err := validation.ValidateClaudeSettingsFile(config.FilePath); err != nil {
-		return fmt.Errorf("configuration file is invalid: %w", err)
+	settingsPath, err := m.settingsPath(opts)
+	if err != nil {
+		return nil, err
+	}
+	if opts.SettingsPath != "" {
+		if err := os.MkdirAll(filepath.Dir(settingsPath), 0755); err != nil {
+			return nil, fmt.Errorf("failed to create settings directory: %w", err)
+		}
 	}
 
-	settingsPath, err := m.GetClaudeSettingsPath()
+	// Compute and validate the settings to write before touching anything
+	data, err := m.render(config, settingsPath, opts)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	result := &ApplyResult{
+		Config:       config,
+		SettingsPath: settingsPath,
+	}
+
+	if target, err := os.Readlink(settingsPath); err == nil {
+		result.SymlinkTarget = target
+	}
+
+	// Refuse, or skip, copying a settings file over the size limit
+	if opts.BackupMaxBytes > 0 {
+		if info, err := os.Stat(settingsPath); err == nil && info.Size() > opts.BackupMaxBytes {
+			if !opts.SkipOversizeBackup {
+				return nil, fmt.Errorf("%w: %s is %s, over the limit of %s", ErrBackupTooLarge, settingsPath,
+					storage.FormatSize(info.Size()), storage.FormatSize(opts.BackupMaxBytes))
+			}
+			result.BackupSkipped = true
+		}
 	}
 
 	// Create backup if settings.json exists
-	backupPath := settingsPath + ".backup"
-	if _, err := os.Stat(settingsPath); err == nil {
-		if err := copyFile(settingsPath, backupPath); err != nil {
-			return fmt.Errorf("failed to create backup: %w", err)
+	if !result.BackupSkipped {
+		if result.BackupPath, err = backupSettings(settingsPath, opts.CompressBackup, BackupMeta{ConfigName: config.Name, ConfigID: config.ID}); err != nil {
+			return nil, err
+		}
+	}
+
+	// Replace the link itself rather than writing through it when requested
+	if result.SymlinkTarget != "" && opts.ReplaceSymlink {
+		if err := os.Remove(settingsPath); err != nil {
+			return nil, fmt.Errorf("failed to remove settings symlink: %w", err)
 		}
 	}
 
-	// Apply the configuration
-	if err := copyFile(config.FilePath, settingsPath); err != nil {
+	// Apply the configuration atomically, writing through a kept link and
+	// keeping the permissions of the file being replaced
+	writePath := settingsPath
+	if resolved, err := filepath.EvalSymlinks(settingsPath); err == nil {
+		writePath = resolved
+	}
+	if err := writeSettings(writePath, data, storage.FileMode(writePath, 0644)); err != nil {
 		// Try to restore backup on failure
-		if _, statErr := os.Stat(backupPath); statErr == nil {
-			copyFile(backupPath, settingsPath)
+		if result.BackupPath != "" {
+			m.restoreFrom(result.BackupPath, settingsPath)
+		}
+		return nil, fmt.Errorf("failed to apply configuration: %w", err)
+	}
+
+	// Read the file back so a damaged write is never left for Claude Code
+	if err := checkWritten(writePath, data); err != nil {
+		return nil, m.rollbackWrite(result, writePath, err)
+	}
+
+	if opts.SettingsPath == "" {
+		tracked := ""
+		if opts.Track {
+			tracked = config.ID
+		}
+		if err := m.setTracked(tracked); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// writeSettings writes applied settings; tests replace it to simulate a
+// failed or damaged write
+var writeSettings = storage.AtomicWriteMode
+
+// checkWritten re-reads the settings written to path and checks that they
+// are the expected data and valid
+func checkWritten(path string, data []byte) error {
+	written, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("cannot read it back: %w", err)
+	}
+	if !bytes.Equal(written, data) {
+		return fmt.Errorf("%s holds %d bytes instead of %d", path, len(written), len(data))
+	}
+	return validation.ValidateClaudeSettings(written)
+}
+
+// rollbackWrite undoes an apply whose written settings failed checkWritten:
+// the backup is restored, or a file that did not exist before is removed.
+// The returned error wraps ErrWriteCheck and says what was done.
+func (m *Manager) rollbackWrite(result *ApplyResult, writePath string, cause error) error {
+	err := fmt.Errorf("%w: %v", ErrWriteCheck, cause)
+	switch {
+	case result.BackupPath != "":
+		if restoreErr := m.restoreFrom(result.BackupPath, result.SettingsPath); restoreErr != nil {
+			return fmt.Errorf("%w; restoring the backup %s also failed: %v", err, result.BackupPath, restoreErr)
 		}
-		return fmt.Errorf("failed to apply configuration: %w", err)
+		return fmt.Errorf("%w; rolled back to the previous settings from %s", err, result.BackupPath)
+	case result.BackupSkipped:
+		return fmt.Errorf("%w; no backup was taken, so the previous settings cannot be restored", err)
+	default:
+		if removeErr := os.Remove(writePath); removeErr != nil {
+			return fmt.Errorf("%w; removing the written file also failed: %v", err, removeErr)
+		}
+		return fmt.Errorf("%w; removed the written file, as there were no previous settings", err)
+	}
+}
+
+// RenderConfig returns the settings that applying the configuration with the
+// given options would write, without making any changes
+func (m *Manager) RenderConfig(identifier string, opts ApplyOptions) ([]byte, error) {
+	config, err := m.GetConfig(identifier)
+	if err != nil {
+		return nil, err
+	}
+
+	settingsPath, err := m.settingsPath(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return m.render(config, settingsPath, opts)
+}
+
+// arrayStrategy returns how merging combines arrays under opts
+func (opts ApplyOptions) arrayStrategy() string {
+	if opts.MergeStrategy == "" {
+		return jsonutil.ArraysReplace
+	}
+	return opts.MergeStrategy
+}
+
+// MergeConflicts returns the key paths, sorted, where merging the
+// configuration into the current settings as an apply with opts would
+// replaces a different current value
+func (m *Manager) MergeConflicts(identifier string, opts ApplyOptions) ([]jsonutil.Conflict, error) {
+	config, err := m.GetConfig(identifier)
+	if err != nil {
+		return nil, err
+	}
+
+	settingsPath, err := m.settingsPath(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := m.renderSource(config, opts)
+	if err != nil {
+		return nil, err
+	}
+	settings, err := jsonutil.ParseObject(data)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := readSettings(settingsPath)
+	if err != nil {
+		return nil, err
 	}
 
-	fmt.Printf("Applied configuration '%s' to ~/.claude/settings.json\n", config.Name)
-	fmt.Printf("Backup saved as: %s\n", backupPath)
+	return jsonutil.MergeConflicts(current, jsonutil.Project(settings, opts.OnlyKeys, opts.DropKeys), opts.arrayStrategy()), nil
+}
+
+// settingsPath returns the settings file an apply with opts writes to
+func (m *Manager) settingsPath(opts ApplyOptions) (string, error) {
+	if opts.SettingsPath != "" {
+		return opts.SettingsPath, nil
+	}
+	return m.GetClaudeSettingsPath()
+}
+
+// render builds the settings content for config, projecting and merging it
+// into the current settings at settingsPath as requested by opts
+func (m *Manager) render(config *Config, settingsPath string, opts ApplyOptions) ([]byte, error) {
+	data, err := m.renderSource(config, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	projected := len(opts.OnlyKeys) > 0 || len(opts.DropKeys) > 0
+	if projected && !opts.Merge {
+		return nil, fmt.Errorf("key projection requires merge mode, otherwise the remaining settings would be lost")
+	}
+	if opts.SettingsKey != "" && opts.Merge {
+		return nil, fmt.Errorf("a settings key cannot be combined with merge mode")
+	}
+	if !opts.Merge && opts.SettingsKey == "" {
+		return data, nil
+	}
+
+	settings, err := jsonutil.ParseObject(data)
+	if err != nil {
+		return nil, err
+	}
 
+	current, err := readSettings(settingsPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var result map[string]interface{}
+	if opts.SettingsKey != "" {
+		value, ok := jsonutil.Lookup(settings, opts.SettingsKey)
+		if !ok {
+			return nil, fmt.Errorf("configuration has no key '%s'", opts.SettingsKey)
+		}
+		if err := jsonutil.Set(current, opts.SettingsKey, value); err != nil {
+			return nil, fmt.Errorf("cannot replace '%s' in the current settings: %w", opts.SettingsKey, err)
+		}
+		result = current
+	} else {
+		result = jsonutil.MergeResolved(current, jsonutil.Project(settings, opts.OnlyKeys, opts.DropKeys), opts.arrayStrategy(), opts.MergeChoices)
+	}
+
+	merged, err := jsonutil.MarshalIndent(result)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validation.ValidateClaudeSettings(merged); err != nil {
+		return nil, fmt.Errorf("merged settings are invalid: %w", err)
+	}
+
+	return merged, nil
+}
+
+// renderSource returns the configuration's settings as JSON: the stored
+// file or revision, with template placeholders filled in, validated
+func (m *Manager) renderSource(config *Config, opts ApplyOptions) ([]byte, error) {
+	sourcePath := config.FilePath
+	if opts.Revision > 0 {
+		revision, err := m.revision(config, opts.Revision)
+		if err != nil {
+			return nil, err
+		}
+		sourcePath = revision.Path
+	}
+
+	data, err := m.loadSource(config, sourcePath, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// Validate the configuration before applying
+	if err := validation.ValidateClaudeSettings(data); err != nil {
+		return nil, fmt.Errorf("configuration file is invalid: %w", err)
+	}
+	if !opts.AllowUnsafe {
+		if err := m.checkDeniedKeys(data); err != nil {
+			return nil, err
+		}
+	}
+
+	return data, nil
+}
+
+// loadSource reads the file at path holding contents of config as JSON,
+// rendering it as a template first when opts.Render is set. apply and
+// validate both read configurations through it, so they agree on what is
+// valid.
+func (m *Manager) loadSource(config *Config, path string, opts ApplyOptions) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	// The stored file keeps its placeholders; only the applied output is rendered
+	if opts.Render {
+		if data, err = renderTemplate(data, opts.Vars, opts.AllowMissing); err != nil {
+			return nil, err
+		}
+	}
+
+	// Claude Code reads JSON, whatever format the configuration is stored in
+	if data, err = ToJSON(data, config.StoredFormat()); err != nil {
+		return nil, fmt.Errorf("configuration file is invalid: %w", err)
+	}
+	return data, nil
+}
+
+// readSettings decodes the live settings.json; a missing file decodes as an
+// empty object
+func readSettings(settingsPath string) (map[string]interface{}, error) {
+	data, err := os.ReadFile(settingsPath)
+	if os.IsNotExist(err) {
+		return map[string]interface{}{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read current settings: %w", err)
+	}
+
+	current, err := jsonutil.ParseObject(data)
+	if err != nil {
+		return nil, fmt.Errorf("current settings are invalid: %w", err)
+	}
+	return current, nil
+}
+
+// CompareWithSettings compares the live settings.json with a stored
+// configuration. Changes go from the live settings (old) to the configuration
+// (new); a missing settings.json compares as an empty object.
+func (m *Manager) CompareWithSettings(identifier string) (*Config, []jsonutil.Change, error) {
+	config, live, configured, err := m.LoadWithSettings(identifier)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return config, jsonutil.Diff(live, configured), nil
+}
+
+// LoadSettings decodes the settings of a stored configuration
+func (m *Manager) LoadSettings(identifier string) (*Config, map[string]interface{}, error) {
+	config, err := m.GetConfig(identifier)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	data, err := m.readJSON(config, config.FilePath)
+	if err != nil {
+		return nil, nil, err
+	}
+	settings, err := jsonutil.ParseObject(data)
+	if err != nil {
+		return nil, nil, fmt.Errorf("configuration file '%s' is invalid: %w", config.Name, err)
+	}
+	return config, settings, nil
+}
+
+// LoadWithSettings decodes the live settings.json and a stored configuration
+// for comparison. A missing settings.json decodes as an empty object.
+func (m *Manager) LoadWithSettings(identifier string) (*Config, map[string]interface{}, map[string]interface{}, error) {
+	config, configured, err := m.LoadSettings(identifier)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	settingsPath, err := m.GetClaudeSettingsPath()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	live := map[string]interface{}{}
+	if liveData, err := os.ReadFile(settingsPath); err == nil {
+		if live, err = jsonutil.ParseObject(liveData); err != nil {
+			return nil, nil, nil, fmt.Errorf("current settings are invalid: %w", err)
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, nil, nil, fmt.Errorf("failed to read current settings: %w", err)
+	}
+
+	return config, live, configured, nil
+}
+
+// UnmanagedSettings reports whether the live settings.json holds contents
+// that match no saved configuration, such as hand edits made after the last
+// apply. Contents are compared semantically. A missing settings.json has
+// nothing to lose and is not unmanaged.
+func (m *Manager) UnmanagedSettings() (bool, error) {
+	settingsPath, err := m.GetClaudeSettingsPath()
+	if err != nil {
+		return false, err
+	}
+
+	liveData, err := os.ReadFile(settingsPath)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to read current settings: %w", err)
+	}
+
+	if _, err := jsonutil.ParseObject(liveData); err != nil {
+		// Invalid settings cannot have come from a saved configuration
+		return true, nil
+	}
+
+	return m.FindByContent(liveData) == nil, nil
+}
+
+// ActiveConfig returns the configuration whose contents match the live
+// settings.json, compared semantically. It returns ErrNoActive when the
+// settings are missing, invalid or match no saved configuration.
+func (m *Manager) ActiveConfig() (*Config, error) {
+	settingsPath, err := m.GetClaudeSettingsPath()
+	if err != nil {
+		return nil, err
+	}
+
+	liveData, err := os.ReadFile(settingsPath)
+	if os.IsNotExist(err) {
+		return nil, ErrNoActive
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read current settings: %w", err)
+	}
+
+	if _, err := jsonutil.ParseObject(liveData); err != nil {
+		return nil, ErrNoActive
+	}
+
+	config := m.FindByContent(liveData)
+	if config == nil {
+		return nil, ErrNoActive
+	}
+	return config, nil
+}
+
+// SettingsEqual reports whether the settings file an apply with opts writes
+// to has the same contents as the JSON data, ignoring key order and
+// formatting. A missing or invalid settings file is never equal.
+func (m *Manager) SettingsEqual(data []byte, opts ApplyOptions) (bool, error) {
+	settingsPath, err := m.settingsPath(opts)
+	if err != nil {
+		return false, err
+	}
+
+	liveData, err := os.ReadFile(settingsPath)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to read current settings: %w", err)
+	}
+
+	live, err := jsonutil.Hash(liveData)
+	if err != nil {
+		return false, nil
+	}
+	wanted, err := jsonutil.Hash(data)
+	if err != nil {
+		return false, err
+	}
+	return live == wanted, nil
+}
+
+// FindByContent returns the first configuration whose contents equal the
+// JSON data, ignoring key order and formatting, or nil if none does
+func (m *Manager) FindByContent(data []byte) *Config {
+	hash, err := jsonutil.Hash(data)
+	if err != nil {
+		return nil
+	}
+
+	for i := range m.configs {
+		config := &m.configs[i]
+		if configHash, err := m.ContentHash(config); err == nil && configHash == hash {
+			return config
+		}
+	}
 	return nil
 }
 
-// RemoveConfig removes a configuration
+// FindByHash returns the configurations whose content hash starts with
+// prefix, in list order. Configurations that cannot be read are skipped.
+func (m *Manager) FindByHash(prefix string) []Config {
+	prefix = strings.ToLower(prefix)
+
+	var matches []Config
+	for i := range m.configs {
+		if hash, err := m.ContentHash(&m.configs[i]); err == nil && strings.HasPrefix(hash, prefix) {
+			matches = append(matches, m.configs[i])
+		}
+	}
+	return matches
+}
+
+// ContentHash returns the hash of the configuration's contents in canonical
+// JSON form, so configurations that differ only in key order, formatting or
+// storage format hash the same
+func (m *Manager) ContentHash(config *Config) (string, error) {
+	data, err := m.readJSON(config, config.FilePath)
+	if err != nil {
+		return "", err
+	}
+	return jsonutil.Hash(data)
+}
+
+// RemoveOptions controls how a configuration is removed
+type RemoveOptions struct {
+	// NoBackup skips keeping a copy of the config file under removed/
+	NoBackup bool
+	// KeepFile drops the metadata entry but leaves the config file, its
+	// revisions and its notes on disk
+	KeepFile bool
+}
+
+// RemoveResult describes the outcome of a successful removal
+type RemoveResult struct {
+	Config *Config
+	// BackupPath is where the removed config file was saved, empty if skipped
+	BackupPath string
+}
+
+// RemoveConfig removes a configuration, keeping a copy of its file under removed/
 func (m *Manager) RemoveConfig(identifier string) error {
+	_, err := m.RemoveConfigWithOptions(identifier, RemoveOptions{})
+	return err
+}
+
+// RemoveConfigWithOptions removes a configuration using the given options
+func (m *Manager) RemoveConfigWithOptions(identifier string, opts RemoveOptions) (*RemoveResult, error) {
 	config, err := m.GetConfig(identifier)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	result := &RemoveResult{Config: config}
+
+	// Keep a safety copy so a mistaken removal is recoverable
+	if !opts.NoBackup && !opts.KeepFile && storage.FileExists(config.FilePath) {
+		backupPath := m.RemovedPath(config)
+		if err := storage.SafeCopy(config.FilePath, backupPath); err != nil {
+			return nil, fmt.Errorf("failed to back up config file: %w", err)
+		}
+		result.BackupPath = backupPath
 	}
 
-	// Remove the config file
-	if err := os.Remove(config.FilePath); err != nil && !os.IsNotExist(err) {
-		return fmt.Errorf("failed to remove config file: %w", err)
+	// Remove the config file with its revisions and notes
+	if !opts.KeepFile {
+		if err := os.Remove(config.FilePath); err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to remove config file: %w", err)
+		}
+		if err := os.RemoveAll(m.revisionsDir(config)); err != nil {
+			return nil, fmt.Errorf("failed to remove config revisions: %w", err)
+		}
+		if err := os.Remove(m.NotesPath(config)); err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to remove config notes: %w", err)
+		}
 	}
 
 	// Remove from configs list
@@ -201,14 +1249,21 @@ func (m *Manager) RemoveConfig(identifier string) error {
 
 	// Save updated configs metadata
 	if err := m.saveConfigs(); err != nil {
-		return fmt.Errorf("failed to update config metadata: %w", err)
+		return nil, fmt.Errorf("failed to update config metadata: %w", err)
 	}
 
-	return nil
+	return result, nil
 }
 
-// loadConfigs loads configuration metadata from file
-func (m *Manager) loadConfigs() error {
+// RemovedPath returns where a removed config's file is saved: removed/<id>-<timestamp>.json
+func (m *Manager) RemovedPath(config *Config) string {
+	name := fmt.Sprintf("%s-%s%s", config.ID, time.Now().Format("20060102-150405"), fileExt(config.StoredFormat()))
+	return filepath.Join(m.configDir, "removed", name)
+}
+
+// loadConfigs loads configuration metadata from file. Corrupt metadata is
+// rebuilt from the configs directory unless options.strict is set.
+func (m *Manager) loadConfigs(options managerOptions) error {
 	metadataPath := filepath.Join(m.configDir, "config.json")
 
 	data, err := os.ReadFile(metadataPath)
@@ -221,8 +1276,24 @@ func (m *Manager) loadConfigs() error {
 		return fmt.Errorf("failed to read config metadata: %w", err)
 	}
 
+	// A newer store is not corrupt; recovering it would discard its metadata
+	if version, err := metadataVersion(data); err == nil && version > SchemaVersion {
+		return fmt.Errorf("%w (schema v%d, this build supports v%d); upgrade claude-switch", ErrSchemaTooNew, version, SchemaVersion)
+	}
+
 	if err := json.Unmarshal(data, &m.configs); err != nil {
-		return fmt.Errorf("failed to parse config metadata: %w", err)
+		if options.strict {
+			return fmt.Errorf("failed to parse config metadata: %w", err)
+		}
+
+		// Rebuild the metadata rather than leave every command unusable
+		recovery, recoverErr := m.recoverConfigs(metadataPath, err)
+		if recoverErr != nil {
+			return fmt.Errorf("failed to parse config metadata: %w (recovery failed: %v)", err, recoverErr)
+		}
+		if options.onRecover != nil {
+			options.onRecover(recovery)
+		}
 	}
 
 	return nil
@@ -251,30 +1322,60 @@ func (m *Manager) ValidateConfig(identifier string) error {
 		return err
 	}
 
-	return validation.ValidateClaudeSettingsFile(config.FilePath)
+	return m.validateStored(config)
 }
 
-// ValidateAllConfigs validates all stored configuration files
-func (m *Manager) ValidateAllConfigs() []error {
-	var errors []error
-	for _, config := range m.configs {
-		if err := validation.ValidateClaudeSettingsFile(config.FilePath); err != nil {
-			errors = append(errors, fmt.Errorf("config '%s' (%s): %w", config.Name, config.ID, err))
-		}
-	}
-	return errors
+// ValidationResult is the outcome of validating a single configuration
+type ValidationResult struct {
+	Config Config
+	// Err is nil when the configuration is valid
+	Err error
 }
 
-// copyFile copies a file from src to dst
-func copyFile(src, dst string) error {
-	sourceData, err := os.ReadFile(src)
-	if err != nil {
-		return fmt.Errorf("failed to read source file: %w", err)
+// ValidateConfigs validates all stored configuration files concurrently using
+// a bounded pool of GOMAXPROCS workers. Results are returned in the same order
+// as GetConfigs.
+func (m *Manager) ValidateConfigs() []ValidationResult {
+	results := make([]ValidationResult, len(m.configs))
+	if len(m.configs) == 0 {
+		return results
 	}
 
-	if err := os.WriteFile(dst, sourceData, 0644); err != nil {
-		return fmt.Errorf("failed to write destination file: %w", err)
+	jobs := make(chan int)
+	workers := min(runtime.GOMAXPROCS(0), len(m.configs))
+
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// Each index is written by exactly one worker, so no locking is needed
+			for i := range jobs {
+				config := m.configs[i]
+				results[i] = ValidationResult{
+					Config: config,
+					Err:    m.validateStored(&config),
+				}
+			}
+		}()
 	}
 
-	return nil
+	for i := range m.configs {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	return results
+}
+
+// ValidateAllConfigs validates all stored configuration files
+func (m *Manager) ValidateAllConfigs() []error {
+	var errors []error
+	for _, result := range m.ValidateConfigs() {
+		if result.Err != nil {
+			errors = append(errors, fmt.Errorf("config '%s' (%s): %w", result.Config.Name, result.Config.ID, result.Err))
+		}
+	}
+	return errors
 }
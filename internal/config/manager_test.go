@@ -0,0 +1,210 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Xanonymous-GitHub/claude-switch/internal/storage"
+	"github.com/Xanonymous-GitHub/claude-switch/internal/validation"
+)
+
+// newTestManager returns a Manager whose store and ~/.claude live in a
+// temporary home directory
+func newTestManager(t *testing.T) *Manager {
+	t.Helper()
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+
+	manager, err := NewManagerWithOptions(WithDir(filepath.Join(home, ".claude-switch")))
+	if err != nil {
+		t.Fatalf("NewManagerWithOptions: %v", err)
+	}
+	return manager
+}
+
+// mustImport stores data as a new configuration named name
+func mustImport(t *testing.T, manager *Manager, name, data string) *Config {
+	t.Helper()
+
+	config, err := manager.ImportConfig(name, "", []byte(data))
+	if err != nil {
+		t.Fatalf("ImportConfig(%s): %v", name, err)
+	}
+	return config
+}
+
+// withWriteSettings replaces the settings writer for the duration of a test
+func withWriteSettings(t *testing.T, write func(string, []byte, os.FileMode) error) {
+	t.Helper()
+
+	original := writeSettings
+	writeSettings = write
+	t.Cleanup(func() { writeSettings = original })
+}
+
+// writeSettingsFile writes the live settings.json of the test home
+func writeSettingsFile(t *testing.T, manager *Manager, data string) string {
+	t.Helper()
+
+	settingsPath, err := manager.GetClaudeSettingsPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(settingsPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(settingsPath, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return settingsPath
+}
+
+func TestApplyRollsBackDamagedWrite(t *testing.T) {
+	manager := newTestManager(t)
+	const original = `{"model": "sonnet", "env": {"A": "1"}}`
+	settingsPath := writeSettingsFile(t, manager, original)
+	config := mustImport(t, manager, "work", `{"model": "opus"}`)
+
+	// A write that is cut short leaves invalid settings behind
+	withWriteSettings(t, func(path string, data []byte, perm os.FileMode) error {
+		return os.WriteFile(path, data[:len(data)/2], perm)
+	})
+
+	_, err := manager.ApplyConfigWithOptions(config.ID, ApplyOptions{})
+	if !errors.Is(err, ErrWriteCheck) {
+		t.Fatalf("ApplyConfigWithOptions error = %v, want ErrWriteCheck", err)
+	}
+
+	restored, err := os.ReadFile(settingsPath)
+	if err != nil {
+		t.Fatalf("reading settings: %v", err)
+	}
+	if string(restored) != original {
+		t.Errorf("settings = %q, want the original %q", restored, original)
+	}
+}
+
+func TestApplyRestoresAfterFailedWrite(t *testing.T) {
+	manager := newTestManager(t)
+	const original = `{"model": "sonnet"}`
+	settingsPath := writeSettingsFile(t, manager, original)
+	config := mustImport(t, manager, "work", `{"model": "opus"}`)
+
+	// The writer clobbers the file and then fails
+	withWriteSettings(t, func(path string, data []byte, perm os.FileMode) error {
+		os.WriteFile(path, []byte("{"), perm)
+		return errors.New("disk full")
+	})
+
+	if _, err := manager.ApplyConfigWithOptions(config.ID, ApplyOptions{}); err == nil {
+		t.Fatal("ApplyConfigWithOptions succeeded despite the failed write")
+	}
+
+	restored, err := os.ReadFile(settingsPath)
+	if err != nil {
+		t.Fatalf("reading settings: %v", err)
+	}
+	if string(restored) != original {
+		t.Errorf("settings = %q, want the original %q", restored, original)
+	}
+}
+
+func TestApplyRemovesDamagedNewFile(t *testing.T) {
+	manager := newTestManager(t)
+	settingsPath, err := manager.GetClaudeSettingsPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(settingsPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	config := mustImport(t, manager, "work", `{"model": "opus"}`)
+
+	withWriteSettings(t, func(path string, data []byte, perm os.FileMode) error {
+		return os.WriteFile(path, []byte(`{"model": `), perm)
+	})
+
+	if _, err := manager.ApplyConfigWithOptions(config.ID, ApplyOptions{}); !errors.Is(err, ErrWriteCheck) {
+		t.Fatalf("ApplyConfigWithOptions error = %v, want ErrWriteCheck", err)
+	}
+	if storage.FileExists(settingsPath) {
+		t.Error("damaged settings.json was left behind")
+	}
+}
+
+func TestFindByContentIgnoresKeyOrder(t *testing.T) {
+	manager := newTestManager(t)
+	mustImport(t, manager, "home", `{"model": "sonnet"}`)
+	work := mustImport(t, manager, "work", `{"model": "opus", "env": {"A": "1", "B": "2"}}`)
+
+	found := manager.FindByContent([]byte("{\n  \"env\": {\"B\": \"2\", \"A\": \"1\"},\n  \"model\": \"opus\"\n}\n"))
+	if found == nil || found.ID != work.ID {
+		t.Errorf("FindByContent = %v, want work", found)
+	}
+	if found := manager.FindByContent([]byte(`{"model": "haiku"}`)); found != nil {
+		t.Errorf("FindByContent = %s for unknown content, want nil", found.Name)
+	}
+}
+
+func TestApplySettingsKeyKeepsOtherValuesExactly(t *testing.T) {
+	manager := newTestManager(t)
+	settingsPath := writeSettingsFile(t, manager, "\xEF\xBB\xBF"+`{"statusLine": {"command": "git log | head -n 1 && echo <done>"}, "cleanupPeriodDays": 12345678901234567890, "permissions": {"allow": ["Read"]}}`)
+	config := mustImport(t, manager, "work", `{"permissions": {"allow": ["Edit"]}, "model": "opus"}`)
+
+	if _, err := manager.ApplyConfigWithOptions(config.ID, ApplyOptions{SettingsKey: "permissions"}); err != nil {
+		t.Fatalf("ApplyConfigWithOptions: %v", err)
+	}
+
+	written, err := os.ReadFile(settingsPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`"git log | head -n 1 && echo <done>"`, `12345678901234567890`, `"Edit"`} {
+		if !strings.Contains(string(written), want) {
+			t.Errorf("written settings lack %s:\n%s", want, written)
+		}
+	}
+	if strings.Contains(string(written), `"model"`) {
+		t.Errorf("keys outside permissions were applied:\n%s", written)
+	}
+}
+
+func TestStoreStripsByteOrderMark(t *testing.T) {
+	manager := newTestManager(t)
+	const settings = `{"model": "opus"}`
+	bom := "\xEF\xBB\xBF"
+
+	config, err := manager.ImportConfig("work", "", []byte(bom+settings))
+	if err != nil {
+		t.Fatalf("ImportConfig: %v", err)
+	}
+	if data, err := os.ReadFile(config.FilePath); err != nil || string(data) != settings {
+		t.Errorf("imported file holds %q (%v), want %q", data, err, settings)
+	}
+
+	const updated = `{"model": "sonnet"}`
+	if _, _, err := manager.UpdateConfig(config.ID, []byte(bom+updated)); err != nil {
+		t.Fatalf("UpdateConfig: %v", err)
+	}
+	if data, err := os.ReadFile(config.FilePath); err != nil || string(data) != updated {
+		t.Errorf("updated file holds %q (%v), want %q", data, err, updated)
+	}
+}
+
+func TestStoreRejectsUTF16(t *testing.T) {
+	manager := newTestManager(t)
+	utf16 := []byte("\xFF\xFE{\x00}\x00")
+
+	if _, err := manager.ImportConfig("work", "", utf16); !errors.Is(err, validation.ErrUTF16) {
+		t.Errorf("ImportConfig error = %v, want ErrUTF16", err)
+	}
+	config := mustImport(t, manager, "work", `{"model": "opus"}`)
+	if _, _, err := manager.UpdateConfig(config.ID, utf16); !errors.Is(err, validation.ErrUTF16) {
+		t.Errorf("UpdateConfig error = %v, want ErrUTF16", err)
+	}
+}
@@ -0,0 +1,55 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// notesSuffix ends the name of a configuration's notes file in configs/
+const notesSuffix = ".notes.md"
+
+// NotesPath returns the path of the freeform notes kept for config,
+// configs/<id>.notes.md. The file only exists once notes are written.
+func (m *Manager) NotesPath(config *Config) string {
+	return filepath.Join(m.configDir, "configs", config.ID+notesSuffix)
+}
+
+// Notes returns the notes of a configuration, or "" if it has none
+func (m *Manager) Notes(identifier string) (*Config, string, error) {
+	config, err := m.GetConfig(identifier)
+	if err != nil {
+		return nil, "", err
+	}
+
+	data, err := os.ReadFile(m.NotesPath(config))
+	if os.IsNotExist(err) {
+		return config, "", nil
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read notes: %w", err)
+	}
+	return config, string(data), nil
+}
+
+// SetNotes replaces the notes of a configuration. Empty notes remove the
+// notes file.
+func (m *Manager) SetNotes(identifier, notes string) (*Config, error) {
+	config, err := m.GetConfig(identifier)
+	if err != nil {
+		return nil, err
+	}
+
+	path := m.NotesPath(config)
+	if notes == "" {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to remove notes: %w", err)
+		}
+		return config, nil
+	}
+
+	if err := os.WriteFile(path, []byte(notes), 0644); err != nil {
+		return nil, fmt.Errorf("failed to write notes: %w", err)
+	}
+	return config, nil
+}
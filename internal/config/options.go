@@ -0,0 +1,44 @@
+package config
+
+// Option configures a Manager created by NewManagerWithOptions
+type Option func(*managerOptions)
+
+// managerOptions holds the settings collected from Options
+type managerOptions struct {
+	dir        string
+	autoCreate bool
+	strict     bool
+	onRecover  func(*Recovery)
+}
+
+// WithDir stores configurations under dir instead of ~/.claude-switch
+func WithDir(dir string) Option {
+	return func(o *managerOptions) {
+		o.dir = dir
+	}
+}
+
+// WithoutAutoCreate makes NewManagerWithOptions fail with ErrStoreNotFound
+// when the store directories do not exist, instead of creating them
+func WithoutAutoCreate() Option {
+	return func(o *managerOptions) {
+		o.autoCreate = false
+	}
+}
+
+// WithStrict makes NewManagerWithOptions fail when config.json cannot be
+// parsed, instead of moving it aside and rebuilding it from the configs
+// directory
+func WithStrict() Option {
+	return func(o *managerOptions) {
+		o.strict = true
+	}
+}
+
+// WithRecoveryHandler calls fn after a corrupt config.json has been rebuilt,
+// so the caller can report it
+func WithRecoveryHandler(fn func(*Recovery)) Option {
+	return func(o *managerOptions) {
+		o.onRecover = fn
+	}
+}
@@ -0,0 +1,65 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// corruptStore writes a store holding one configuration file and a config.json
+// that does not parse, and returns its directory
+func corruptStore(t *testing.T) string {
+	t.Helper()
+
+	dir := filepath.Join(t.TempDir(), ".claude-switch")
+	if err := os.MkdirAll(filepath.Join(dir, "configs"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "configs", "0123456789abcdef.json"), []byte(`{"model": "opus"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "config.json"), []byte(`[{"id": `), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestNewManagerWithStrictFailsOnCorruptMetadata(t *testing.T) {
+	dir := corruptStore(t)
+
+	if _, err := NewManagerWithOptions(WithDir(dir), WithStrict()); err == nil {
+		t.Fatal("NewManagerWithOptions succeeded on a corrupt config.json under WithStrict")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "config.json")); err != nil {
+		t.Errorf("config.json was moved under WithStrict: %v", err)
+	}
+}
+
+func TestNewManagerRecoversCorruptMetadata(t *testing.T) {
+	dir := corruptStore(t)
+
+	var recovery *Recovery
+	manager, err := NewManagerWithOptions(WithDir(dir), WithRecoveryHandler(func(r *Recovery) { recovery = r }))
+	if err != nil {
+		t.Fatalf("NewManagerWithOptions: %v", err)
+	}
+	if recovery == nil {
+		t.Fatal("the recovery handler was not called")
+	}
+	if len(recovery.Recovered) != 1 || len(manager.GetConfigs()) != 1 {
+		t.Errorf("recovered %d configurations, store holds %d, want 1", len(recovery.Recovered), len(manager.GetConfigs()))
+	}
+	if _, err := os.Stat(recovery.BackupPath); err != nil {
+		t.Errorf("corrupt config.json was not kept: %v", err)
+	}
+}
+
+func TestNewManagerOptionsDoNotCarryOver(t *testing.T) {
+	// A strict Manager must not make later Managers strict
+	if _, err := NewManagerWithOptions(WithDir(corruptStore(t)), WithStrict()); err == nil {
+		t.Fatal("NewManagerWithOptions succeeded under WithStrict")
+	}
+	if _, err := NewManagerWithOptions(WithDir(corruptStore(t))); err != nil {
+		t.Errorf("NewManagerWithOptions without WithStrict: %v", err)
+	}
+}
@@ -0,0 +1,122 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/Xanonymous-GitHub/claude-switch/internal/jsonutil"
+)
+
+// ErrDeniedKey is returned when a configuration sets a key listed in the
+// apply.deniedKeys preference
+var ErrDeniedKey = errors.New("configuration sets a denied key")
+
+// ErrMissingRequiredKey is returned when a configuration does not set a key
+// listed in the validate.requiredKeys preference
+var ErrMissingRequiredKey = errors.New("configuration is missing a required key")
+
+// DeniedKeys returns the keys the apply.deniedKeys preference forbids
+func (m *Manager) DeniedKeys() []string {
+	return m.ListPreference(PrefApplyDeniedKeys)
+}
+
+// DeniedKeyPaths returns the paths of every denied key set in the JSON
+// settings data, at any depth including inside arrays, in sorted order
+func (m *Manager) DeniedKeyPaths(data []byte) ([]string, error) {
+	denied := m.DeniedKeys()
+	if len(denied) == 0 {
+		return nil, nil
+	}
+
+	settings, err := jsonutil.ParseObject(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	findKeys(settings, "", denied, &paths)
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// checkDeniedKeys fails with ErrDeniedKey when data sets a denied key
+func (m *Manager) checkDeniedKeys(data []byte) error {
+	paths, err := m.DeniedKeyPaths(data)
+	if err != nil {
+		return err
+	}
+	if len(paths) > 0 {
+		return fmt.Errorf("%w: %s", ErrDeniedKey, strings.Join(paths, ", "))
+	}
+	return nil
+}
+
+// EnableDeniedKeyValidation makes validation fail for configurations that
+// set a denied key, as apply does
+func (m *Manager) EnableDeniedKeyValidation() {
+	m.validateDenied = true
+}
+
+// RequiredKeys returns the keys the validate.requiredKeys preference requires
+func (m *Manager) RequiredKeys() []string {
+	return m.ListPreference(PrefValidateRequiredKeys)
+}
+
+// MissingRequiredKeys returns the required keys, as dotted paths, that
+// settings does not set, in the order they are listed
+func (m *Manager) MissingRequiredKeys(settings map[string]interface{}) []string {
+	var missing []string
+	for _, key := range m.RequiredKeys() {
+		if _, ok := jsonutil.Lookup(settings, key); !ok {
+			missing = append(missing, key)
+		}
+	}
+	return missing
+}
+
+// checkRequiredKeys fails with ErrMissingRequiredKey when data does not set
+// a required key
+func (m *Manager) checkRequiredKeys(data []byte) error {
+	if len(m.RequiredKeys()) == 0 {
+		return nil
+	}
+
+	settings, err := jsonutil.ParseObject(data)
+	if err != nil {
+		return err
+	}
+	if missing := m.MissingRequiredKeys(settings); len(missing) > 0 {
+		return fmt.Errorf("%w: %s", ErrMissingRequiredKey, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// EnableRequiredKeyValidation makes validation fail for configurations that
+// do not set every required key
+func (m *Manager) EnableRequiredKeyValidation() {
+	m.validateRequired = true
+}
+
+// findKeys appends the path of every object key in value that is in keys
+func findKeys(value interface{}, path string, keys []string, paths *[]string) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			childPath := key
+			if path != "" {
+				childPath = path + "." + key
+			}
+			if slices.Contains(keys, key) {
+				*paths = append(*paths, childPath)
+			}
+			findKeys(child, childPath, keys, paths)
+		}
+	case []interface{}:
+		for i, item := range v {
+			findKeys(item, fmt.Sprintf("%s[%d]", path, i), keys, paths)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package config
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestPolicyValidationIsOptIn(t *testing.T) {
+	manager := newTestManager(t)
+	if err := manager.SetPreference(PrefApplyDeniedKeys, "dangerouslySkipPermissions"); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.SetPreference(PrefValidateRequiredKeys, "permissions"); err != nil {
+		t.Fatal(err)
+	}
+	denied := mustImport(t, manager, "denied", `{"permissions": {}, "nested": [{"dangerouslySkipPermissions": true}]}`)
+	missing := mustImport(t, manager, "missing", `{"model": "opus"}`)
+
+	// Neither policy fails plain validation
+	for _, config := range []*Config{denied, missing} {
+		if err := manager.ValidateConfig(config.ID); err != nil {
+			t.Errorf("ValidateConfig(%s) = %v before enabling policy checks", config.Name, err)
+		}
+	}
+
+	manager.EnableDeniedKeyValidation()
+	if err := manager.ValidateConfig(denied.ID); !errors.Is(err, ErrDeniedKey) {
+		t.Errorf("ValidateConfig(denied) = %v, want ErrDeniedKey", err)
+	}
+	if err := manager.ValidateConfig(missing.ID); err != nil {
+		t.Errorf("ValidateConfig(missing) = %v with only denied keys enabled", err)
+	}
+
+	manager.EnableRequiredKeyValidation()
+	if err := manager.ValidateConfig(missing.ID); !errors.Is(err, ErrMissingRequiredKey) {
+		t.Errorf("ValidateConfig(missing) = %v, want ErrMissingRequiredKey", err)
+	}
+}
+
+func TestDeniedKeyPaths(t *testing.T) {
+	manager := newTestManager(t)
+	if err := manager.SetPreference(PrefApplyDeniedKeys, "secret,token"); err != nil {
+		t.Fatal(err)
+	}
+
+	paths, err := manager.DeniedKeyPaths([]byte(`{"token": 1, "env": {"secret": "x"}, "list": [{"token": 2}]}`))
+	if err != nil {
+		t.Fatalf("DeniedKeyPaths: %v", err)
+	}
+	want := []string{"env.secret", "list[0].token", "token"}
+	if len(paths) != len(want) {
+		t.Fatalf("DeniedKeyPaths = %v, want %v", paths, want)
+	}
+	for i := range want {
+		if paths[i] != want[i] {
+			t.Errorf("DeniedKeyPaths = %v, want %v", paths, want)
+			break
+		}
+	}
+}
@@ -0,0 +1,261 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Preference keys understood by claude-switch
+const (
+	// PrefBackupCompress makes apply store backups gzip-compressed
+	PrefBackupCompress = "backup.compress"
+	// PrefBackupGit commits settings.json to its git repository before apply overwrites it
+	PrefBackupGit = "backup.git"
+	// PrefBackupMaxBytes is the largest settings file apply backs up (0 for no limit)
+	PrefBackupMaxBytes = "backup.maxBytes"
+	// PrefApplyConfirm makes apply prompt for confirmation unless --no-confirm or --force is given
+	PrefApplyConfirm = "apply.confirmDefault"
+	// PrefApplyDeniedKeys lists keys a configuration may not set, at any depth, to be applied
+	PrefApplyDeniedKeys = "apply.deniedKeys"
+	// PrefApplyPostMessage is a note printed after every successful apply
+	PrefApplyPostMessage = "apply.postMessage"
+	// PrefClaudeLaunchCmd is the command apply --then-open starts Claude Code with
+	PrefClaudeLaunchCmd = "claude.launchCmd"
+	// PrefConfigMaxSize is the largest configuration file add, import and
+	// edit accept, in bytes
+	PrefConfigMaxSize = "config.maxSize"
+	// PrefNamePattern is a regular expression every configuration name must match
+	PrefNamePattern = "name.pattern"
+	// PrefGitAutoCommit commits store changes when the store is a git work tree
+	PrefGitAutoCommit = "git.autoCommit"
+	// PrefRevisionsKeep is the number of revisions kept per configuration
+	PrefRevisionsKeep = "revisions.keep"
+	// PrefValidateRequiredKeys lists keys every configuration must set for validate
+	PrefValidateRequiredKeys = "validate.requiredKeys"
+	// PrefValidateSchemaURL is the JSON Schema validate and apply --force-validate check against
+	PrefValidateSchemaURL = "validate.schemaUrl"
+)
+
+// PreferenceKind is the type of value a preference holds
+type PreferenceKind int
+
+const (
+	PrefString PreferenceKind = iota
+	PrefInt
+	PrefBool
+	PrefList
+	PrefPattern
+)
+
+// String returns the name of the kind as shown to users
+func (k PreferenceKind) String() string {
+	switch k {
+	case PrefInt:
+		return "int"
+	case PrefBool:
+		return "bool"
+	case PrefList:
+		return "list"
+	case PrefPattern:
+		return "regexp"
+	default:
+		return "string"
+	}
+}
+
+// Preference describes a supported preference
+type Preference struct {
+	Key         string
+	Kind        PreferenceKind
+	Default     string
+	Description string
+}
+
+// knownPreferences lists every supported preference, sorted by key
+var knownPreferences = []Preference{
+	{PrefApplyConfirm, PrefBool, "false", "Prompt before apply unless --no-confirm or --force is given"},
+	{PrefApplyDeniedKeys, PrefList, "", "Comma-separated keys apply refuses at any depth unless --allow-unsafe is given"},
+	{PrefApplyPostMessage, PrefString, "", "Message printed after a successful apply ({config}, {id}, {settings}, {backup}; \\n for newlines)"},
+	{PrefBackupCompress, PrefBool, "false", "Store the backups apply makes gzip-compressed"},
+	{PrefBackupGit, PrefBool, "false", "Commit settings.json to its git repository before apply overwrites it"},
+	{PrefBackupMaxBytes, PrefInt, "0", "Largest settings.json apply backs up, in bytes (0 for no limit)"},
+	{PrefClaudeLaunchCmd, PrefString, "", "Command apply --then-open runs to start Claude Code (empty runs 'claude' from PATH)"},
+	{PrefConfigMaxSize, PrefInt, strconv.FormatInt(DefaultMaxConfigSize, 10), "Largest configuration file add, import and edit accept, in bytes (0 for no limit)"},
+	{PrefGitAutoCommit, PrefBool, "false", "Commit add/edit/remove when the store is a git work tree"},
+	{PrefNamePattern, PrefPattern, "", "Regular expression configuration names must match (empty allows any)"},
+	{PrefRevisionsKeep, PrefInt, "10", "Revisions kept per configuration (0 keeps all)"},
+	{PrefValidateRequiredKeys, PrefList, "", "Comma-separated keys (dotted paths) validate warns about when missing, or fails on with --require-keys"},
+	{PrefValidateSchemaURL, PrefString, "", "JSON Schema URL for validate and apply --force-validate (empty for built-in checks only)"},
+}
+
+// KnownPreferences returns every supported preference, sorted by key
+func KnownPreferences() []Preference {
+	return append([]Preference(nil), knownPreferences...)
+}
+
+// lookupPreference returns the description of key, or ErrUnknownPreference
+func lookupPreference(key string) (Preference, error) {
+	for _, pref := range knownPreferences {
+		if pref.Key == key {
+			return pref, nil
+		}
+	}
+
+	keys := make([]string, len(knownPreferences))
+	for i, pref := range knownPreferences {
+		keys[i] = pref.Key
+	}
+	return Preference{}, fmt.Errorf("%w: '%s' (valid: %s)", ErrUnknownPreference, key, strings.Join(keys, ", "))
+}
+
+// GetPreference returns the value of a preference and whether it was set
+// explicitly. Unset preferences return their default.
+func (m *Manager) GetPreference(key string) (string, bool, error) {
+	pref, err := lookupPreference(key)
+	if err != nil {
+		return "", false, err
+	}
+
+	if value, ok := m.prefs[key]; ok {
+		return value, true, nil
+	}
+	return pref.Default, false, nil
+}
+
+// SetPreference validates value against the preference's kind and saves it
+func (m *Manager) SetPreference(key, value string) error {
+	pref, err := lookupPreference(key)
+	if err != nil {
+		return err
+	}
+
+	switch pref.Kind {
+	case PrefInt:
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return fmt.Errorf("preference '%s' must be a non-negative integer, got '%s'", key, value)
+		}
+	case PrefBool:
+		if _, err := strconv.ParseBool(value); err != nil {
+			return fmt.Errorf("preference '%s' must be true or false, got '%s'", key, value)
+		}
+	case PrefPattern:
+		if _, err := regexp.Compile(value); err != nil {
+			return fmt.Errorf("preference '%s' must be a valid regular expression: %w", key, err)
+		}
+	}
+
+	if m.prefs == nil {
+		m.prefs = make(map[string]string)
+	}
+	m.prefs[key] = value
+
+	return m.savePreferences()
+}
+
+// UnsetPreference restores a preference to its default
+func (m *Manager) UnsetPreference(key string) error {
+	if _, err := lookupPreference(key); err != nil {
+		return err
+	}
+
+	delete(m.prefs, key)
+	return m.savePreferences()
+}
+
+// IntPreference returns an integer preference, falling back to its default
+// when the stored value does not parse
+func (m *Manager) IntPreference(key string) int {
+	pref, _ := lookupPreference(key)
+	value, _, _ := m.GetPreference(key)
+
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		n, _ = strconv.Atoi(pref.Default)
+	}
+	return n
+}
+
+// ListPreference returns the comma-separated items of a list preference,
+// trimmed and without empty items
+func (m *Manager) ListPreference(key string) []string {
+	value, _, _ := m.GetPreference(key)
+
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// BoolPreference returns a boolean preference, or false if its value does not parse
+func (m *Manager) BoolPreference(key string) bool {
+	value, _, _ := m.GetPreference(key)
+	enabled, _ := strconv.ParseBool(value)
+	return enabled
+}
+
+// checkName validates a configuration name against the name.pattern
+// preference
+func (m *Manager) checkName(name string) error {
+	if name == "" {
+		return ErrEmptyName
+	}
+
+	pattern, _, _ := m.GetPreference(PrefNamePattern)
+	if pattern == "" {
+		return nil
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return fmt.Errorf("invalid %s preference: %w", PrefNamePattern, err)
+	}
+	if !re.MatchString(name) {
+		return fmt.Errorf("%w: '%s' does not match the required pattern %s (see 'claude-switch config get %s')",
+			ErrInvalidName, name, pattern, PrefNamePattern)
+	}
+	return nil
+}
+
+// loadPreferences loads preferences.json; a missing file means all defaults
+func (m *Manager) loadPreferences() error {
+	data, err := os.ReadFile(filepath.Join(m.configDir, "preferences.json"))
+	if os.IsNotExist(err) {
+		m.prefs = map[string]string{}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read preferences: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &m.prefs); err != nil {
+		return fmt.Errorf("failed to parse preferences: %w", err)
+	}
+	if m.prefs == nil {
+		m.prefs = map[string]string{}
+	}
+
+	return nil
+}
+
+// savePreferences writes preferences.json
+func (m *Manager) savePreferences() error {
+	data, err := json.MarshalIndent(m.prefs, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal preferences: %w", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(m.configDir, "preferences.json"), data, 0644); err != nil {
+		return fmt.Errorf("failed to write preferences: %w", err)
+	}
+
+	return nil
+}
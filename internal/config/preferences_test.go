@@ -0,0 +1,53 @@
+package config
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestKnownPreferencesSorted(t *testing.T) {
+	keys := make([]string, len(knownPreferences))
+	for i, pref := range knownPreferences {
+		keys[i] = pref.Key
+	}
+	if !slices.IsSorted(keys) {
+		t.Errorf("knownPreferences are not sorted by key: %v", keys)
+	}
+}
+
+func TestBackupCompressPreference(t *testing.T) {
+	manager := newTestManager(t)
+	if manager.BoolPreference(PrefBackupCompress) {
+		t.Fatal("backup.compress defaults to true")
+	}
+	if err := manager.SetPreference(PrefBackupCompress, "true"); err != nil {
+		t.Fatalf("SetPreference: %v", err)
+	}
+	if !manager.BoolPreference(PrefBackupCompress) {
+		t.Error("backup.compress is false after setting it")
+	}
+	if err := manager.SetPreference(PrefBackupCompress, "often"); err == nil {
+		t.Error("SetPreference accepted a non-boolean backup.compress")
+	}
+}
+
+func TestConfigMaxSizePreference(t *testing.T) {
+	manager := newTestManager(t)
+	if got := int64(manager.IntPreference(PrefConfigMaxSize)); got != DefaultMaxConfigSize {
+		t.Errorf("config.maxSize defaults to %d, want %d", got, DefaultMaxConfigSize)
+	}
+	if err := manager.SetPreference(PrefConfigMaxSize, "-1"); err == nil {
+		t.Error("SetPreference accepted a negative config.maxSize")
+	}
+
+	config := mustImport(t, manager, "work", `{"model": "opus"}`)
+	manager.SetMaxConfigSize(10)
+	if _, _, err := manager.UpdateConfig(config.ID, []byte(`{"model": "sonnet"}`)); !errors.Is(err, ErrConfigTooLarge) {
+		t.Errorf("UpdateConfig error = %v, want ErrConfigTooLarge", err)
+	}
+	manager.SetMaxConfigSize(0)
+	if _, _, err := manager.UpdateConfig(config.ID, []byte(`{"model": "sonnet"}`)); err != nil {
+		t.Errorf("UpdateConfig without a limit: %v", err)
+	}
+}
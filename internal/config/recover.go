@@ -0,0 +1,108 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Xanonymous-GitHub/claude-switch/internal/validation"
+)
+
+// Recovery describes how a corrupt config.json was replaced
+type Recovery struct {
+	// BackupPath is where the corrupt config.json was moved
+	BackupPath string
+	// Err is the parse error that triggered the recovery
+	Err error
+	// Recovered are the configurations rebuilt from the configs directory
+	Recovered []Config
+	// Skipped are config files that could not be recovered
+	Skipped []string
+}
+
+// recoverConfigs moves the corrupt metadata file aside and rebuilds the
+// metadata from the files in the configs directory. Names cannot be
+// recovered, so each configuration is named after its ID.
+func (m *Manager) recoverConfigs(metadataPath string, parseErr error) (*Recovery, error) {
+	recovery := &Recovery{
+		BackupPath: fmt.Sprintf("%s.corrupt-%s", metadataPath, time.Now().Format(backupTimeFormat)),
+		Err:        parseErr,
+	}
+	if err := os.Rename(metadataPath, recovery.BackupPath); err != nil {
+		return nil, fmt.Errorf("failed to back up corrupt config metadata: %w", err)
+	}
+
+	configsDir := filepath.Join(m.configDir, "configs")
+	entries, err := os.ReadDir(configsDir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read configs directory: %w", err)
+	}
+
+	m.configs = []Config{}
+	for _, entry := range entries {
+		// Subdirectories hold revisions; notes are not configurations
+		if entry.IsDir() || strings.HasSuffix(entry.Name(), notesSuffix) {
+			continue
+		}
+
+		config, err := m.recoverConfig(configsDir, entry)
+		if err != nil {
+			recovery.Skipped = append(recovery.Skipped, entry.Name())
+			continue
+		}
+		m.configs = append(m.configs, *config)
+		recovery.Recovered = append(recovery.Recovered, *config)
+	}
+
+	if err := m.saveConfigs(); err != nil {
+		return nil, err
+	}
+	return recovery, nil
+}
+
+// recoverConfig rebuilds the metadata of a single stored config file
+func (m *Manager) recoverConfig(dir string, entry os.DirEntry) (*Config, error) {
+	ext := filepath.Ext(entry.Name())
+	id := strings.TrimSuffix(entry.Name(), ext)
+
+	format, err := ParseFormat(strings.TrimPrefix(ext, "."))
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := entry.Info()
+	if err != nil {
+		return nil, err
+	}
+
+	config := &Config{
+		ID:        id,
+		Name:      "recovered-" + shortID(id),
+		CreatedAt: info.ModTime(),
+		FilePath:  filepath.Join(dir, entry.Name()),
+	}
+	// JSON stays implicit, as when stored
+	if format != FormatJSON {
+		config.Format = format
+	}
+
+	data, err := m.readJSON(config, config.FilePath)
+	if err != nil {
+		return nil, err
+	}
+	if err := validation.ValidateClaudeSettings(data); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// shortID returns the first eight characters of id
+func shortID(id string) string {
+	if len(id) > 8 {
+		return id[:8]
+	}
+	return id
+}
@@ -0,0 +1,57 @@
+package config
+
+import (
+	"os"
+	"testing"
+)
+
+// configWithHistory stores a configuration with a second revision and notes
+func configWithHistory(t *testing.T, manager *Manager) *Config {
+	t.Helper()
+
+	config := mustImport(t, manager, "work", `{"model": "opus"}`)
+	if _, _, err := manager.UpdateConfig(config.ID, []byte(`{"model": "sonnet"}`)); err != nil {
+		t.Fatalf("UpdateConfig: %v", err)
+	}
+	if _, err := manager.SetNotes(config.ID, "Used for client work"); err != nil {
+		t.Fatalf("SetNotes: %v", err)
+	}
+	return config
+}
+
+func TestRemoveKeepFileKeepsArtifacts(t *testing.T) {
+	manager := newTestManager(t)
+	config := configWithHistory(t, manager)
+
+	if _, err := manager.RemoveConfigWithOptions(config.ID, RemoveOptions{KeepFile: true}); err != nil {
+		t.Fatalf("RemoveConfigWithOptions: %v", err)
+	}
+
+	if manager.ExistsID(config.ID) {
+		t.Error("configuration is still in the store")
+	}
+	for _, path := range []string{config.FilePath, manager.revisionsDir(config), manager.NotesPath(config)} {
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("--keep-file removed %s: %v", path, err)
+		}
+	}
+}
+
+func TestRemoveDeletesArtifacts(t *testing.T) {
+	manager := newTestManager(t)
+	config := configWithHistory(t, manager)
+
+	result, err := manager.RemoveConfigWithOptions(config.ID, RemoveOptions{})
+	if err != nil {
+		t.Fatalf("RemoveConfigWithOptions: %v", err)
+	}
+
+	for _, path := range []string{config.FilePath, manager.revisionsDir(config), manager.NotesPath(config)} {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("%s still exists after removal (%v)", path, err)
+		}
+	}
+	if _, err := os.Stat(result.BackupPath); err != nil {
+		t.Errorf("removed configuration was not backed up: %v", err)
+	}
+}
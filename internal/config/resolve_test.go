@@ -0,0 +1,107 @@
+package config
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestResolve(t *testing.T) {
+	manager := &Manager{configs: []Config{
+		{ID: "a1b2c3d4", Name: "work"},
+		{ID: "a1b2ffff", Name: "home"},
+		// A name that looks like another configuration's ID wins over it
+		{ID: "5555aaaa", Name: "9f8e"},
+	}}
+
+	tests := []struct {
+		name       string
+		identifier string
+		want       string
+		wantErr    error
+	}{
+		{"name", "work", "a1b2c3d4", nil},
+		{"full ID", "a1b2ffff", "a1b2ffff", nil},
+		{"unique prefix", "a1b2c", "a1b2c3d4", nil},
+		{"name before prefix", "9f8e", "5555aaaa", nil},
+		{"ambiguous prefix", "a1b2", "", ErrAmbiguousID},
+		{"not found", "missing", "", ErrConfigNotFound},
+		{"empty", "", "", ErrConfigNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := manager.Resolve(tt.identifier)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("Resolve(%q) error = %v, want %v", tt.identifier, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Resolve(%q): %v", tt.identifier, err)
+			}
+			if config.ID != tt.want {
+				t.Errorf("Resolve(%q) = %s, want %s", tt.identifier, config.ID, tt.want)
+			}
+
+			viaGet, err := manager.GetConfig(tt.identifier)
+			if err != nil || viaGet.ID != config.ID {
+				t.Errorf("GetConfig(%q) = %v, %v, want %s", tt.identifier, viaGet, err, config.ID)
+			}
+		})
+	}
+}
+
+func TestExists(t *testing.T) {
+	manager := newTestManager(t)
+	work := mustImport(t, manager, "work", `{"model": "opus"}`)
+	mustImport(t, manager, "home", `{"model": "sonnet"}`)
+
+	tests := []struct {
+		name       string
+		exists     bool
+		existsID   bool
+		identifier string
+	}{
+		{"name", true, false, "work"},
+		{"other name", true, false, "home"},
+		{"name differing in case", false, false, "Work"},
+		{"full ID", false, true, work.ID},
+		{"ID prefix", false, false, work.ID[:6]},
+		{"unknown", false, false, "missing"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := manager.Exists(tt.identifier); got != tt.exists {
+				t.Errorf("Exists(%q) = %v, want %v", tt.identifier, got, tt.exists)
+			}
+			if got := manager.ExistsID(tt.identifier); got != tt.existsID {
+				t.Errorf("ExistsID(%q) = %v, want %v", tt.identifier, got, tt.existsID)
+			}
+		})
+	}
+}
+
+func TestStoreRejectsExistingName(t *testing.T) {
+	manager := newTestManager(t)
+	mustImport(t, manager, "work", `{"model": "opus"}`)
+
+	if _, err := manager.ImportConfig("work", "", []byte(`{}`)); !errors.Is(err, ErrConfigExists) {
+		t.Errorf("ImportConfig error = %v, want ErrConfigExists", err)
+	}
+	if err := manager.CheckNewName("work"); !errors.Is(err, ErrConfigExists) {
+		t.Errorf("CheckNewName error = %v, want ErrConfigExists", err)
+	}
+	if _, err := manager.CheckRename("home", "work"); err == nil {
+		t.Error("CheckRename of a missing configuration succeeded")
+	}
+
+	config, err := manager.ImportConfigWithOptions("work", "", []byte(`{}`), AddOptions{AutoName: true})
+	if err != nil {
+		t.Fatalf("ImportConfigWithOptions: %v", err)
+	}
+	if config.Name != "work (2)" {
+		t.Errorf("AutoName chose %q, want %q", config.Name, "work (2)")
+	}
+}
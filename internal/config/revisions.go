@@ -0,0 +1,200 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Xanonymous-GitHub/claude-switch/internal/storage"
+	"github.com/Xanonymous-GitHub/claude-switch/internal/validation"
+)
+
+// Revision is a stored version of a configuration. Revisions are numbered
+// from 1 and kept under configs/<id>/<n>.json (or .toml); the highest number
+// matches the current configuration file.
+type Revision struct {
+	Number    int
+	Path      string
+	CreatedAt time.Time
+	Size      int64
+}
+
+// Revisions returns the stored revisions of a configuration, oldest first
+func (m *Manager) Revisions(identifier string) (*Config, []Revision, error) {
+	config, err := m.GetConfig(identifier)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	revisions, err := m.revisions(config)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return config, revisions, nil
+}
+
+// Revision returns the stored revision of a configuration with the given
+// number, or an error wrapping ErrRevisionNotFound
+func (m *Manager) Revision(identifier string, number int) (*Config, *Revision, error) {
+	config, err := m.GetConfig(identifier)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	revision, err := m.revision(config, number)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return config, revision, nil
+}
+
+// UpdateConfig replaces the contents of a configuration and records the new
+// contents as its next revision, which is returned
+func (m *Manager) UpdateConfig(identifier string, data []byte) (*Config, int, error) {
+	config, err := m.GetConfig(identifier)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if err := m.checkSize(int64(len(data))); err != nil {
+		return nil, 0, err
+	}
+	if data, err = validation.NormalizeEncoding(data); err != nil {
+		return nil, 0, fmt.Errorf("invalid configuration file: %w", err)
+	}
+	settings, err := ToJSON(data, config.StoredFormat())
+	if err != nil {
+		return nil, 0, fmt.Errorf("invalid configuration file: %w", err)
+	}
+	if err := validation.ValidateClaudeSettings(settings); err != nil {
+		return nil, 0, fmt.Errorf("invalid configuration file: %w", err)
+	}
+
+	// Configurations stored before revisions existed get their current
+	// contents recorded first so the edit can be undone
+	revisions, err := m.revisions(config)
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(revisions) == 0 {
+		if current, err := os.ReadFile(config.FilePath); err == nil {
+			if _, err := m.saveRevision(config, current); err != nil {
+				return nil, 0, err
+			}
+		}
+	}
+
+	if err := storage.AtomicWrite(config.FilePath, data); err != nil {
+		return nil, 0, fmt.Errorf("failed to write config file: %w", err)
+	}
+
+	number, err := m.saveRevision(config, data)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return config, number, nil
+}
+
+// revisionsDir returns the directory holding a configuration's revisions
+func (m *Manager) revisionsDir(config *Config) string {
+	return filepath.Join(m.configDir, "configs", config.ID)
+}
+
+// revisions lists the revision files of config, oldest first
+func (m *Manager) revisions(config *Config) ([]Revision, error) {
+	dir := m.revisionsDir(config)
+
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read revisions: %w", err)
+	}
+
+	ext := fileExt(config.StoredFormat())
+
+	var revisions []Revision
+	for _, entry := range entries {
+		number, err := strconv.Atoi(strings.TrimSuffix(entry.Name(), ext))
+		if err != nil || number < 1 || !strings.HasSuffix(entry.Name(), ext) {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+
+		revisions = append(revisions, Revision{
+			Number:    number,
+			Path:      filepath.Join(dir, entry.Name()),
+			CreatedAt: info.ModTime(),
+			Size:      info.Size(),
+		})
+	}
+
+	sort.Slice(revisions, func(i, j int) bool {
+		return revisions[i].Number < revisions[j].Number
+	})
+
+	return revisions, nil
+}
+
+// revision returns the revision of config with the given number
+func (m *Manager) revision(config *Config, number int) (*Revision, error) {
+	revisions, err := m.revisions(config)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, revision := range revisions {
+		if revision.Number == number {
+			return &revision, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: '%s' has no revision %d", ErrRevisionNotFound, config.Name, number)
+}
+
+// saveRevision records data as the next revision of config and prunes old
+// revisions beyond the revisions.keep preference
+func (m *Manager) saveRevision(config *Config, data []byte) (int, error) {
+	revisions, err := m.revisions(config)
+	if err != nil {
+		return 0, err
+	}
+
+	number := 1
+	if len(revisions) > 0 {
+		number = revisions[len(revisions)-1].Number + 1
+	}
+
+	dir := m.revisionsDir(config)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return 0, fmt.Errorf("failed to create revisions directory: %w", err)
+	}
+
+	path := filepath.Join(dir, strconv.Itoa(number)+fileExt(config.StoredFormat()))
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return 0, fmt.Errorf("failed to write revision: %w", err)
+	}
+
+	// Old revisions are a convenience; failing to prune them is not an error
+	if keep := m.IntPreference(PrefRevisionsKeep); keep > 0 {
+		revisions = append(revisions, Revision{Number: number, Path: path})
+		for len(revisions) > keep {
+			os.Remove(revisions[0].Path)
+			revisions = revisions[1:]
+		}
+	}
+
+	return number, nil
+}
@@ -0,0 +1,35 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"testing"
+)
+
+func TestRevision(t *testing.T) {
+	manager := newTestManager(t)
+	config := configWithHistory(t, manager)
+
+	_, revisions, err := manager.Revisions(config.ID)
+	if err != nil {
+		t.Fatalf("Revisions: %v", err)
+	}
+	first := revisions[0]
+
+	_, revision, err := manager.Revision(config.Name, first.Number)
+	if err != nil {
+		t.Fatalf("Revision: %v", err)
+	}
+	if revision.Path != first.Path {
+		t.Errorf("Revision path = %s, want %s", revision.Path, first.Path)
+	}
+	if data, err := os.ReadFile(revision.Path); err != nil || string(data) != `{"model": "opus"}` {
+		t.Errorf("revision %d holds %q (%v), want the imported contents", first.Number, data, err)
+	}
+
+	for _, number := range []int{0, -1, len(revisions) + 1} {
+		if _, _, err := manager.Revision(config.ID, number); !errors.Is(err, ErrRevisionNotFound) {
+			t.Errorf("Revision(%d) error = %v, want ErrRevisionNotFound", number, err)
+		}
+	}
+}
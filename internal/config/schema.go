@@ -0,0 +1,65 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// SchemaVersion is the config.json schema this build reads and writes.
+// Version 1 is a bare JSON array of configurations; later versions are
+// objects with a "schema_version" field.
+const SchemaVersion = 1
+
+// SchemaCheck compares the schema of a store with this build
+type SchemaCheck struct {
+	// Path is the config.json that was checked
+	Path string
+	// Version is the schema version of the store, 0 if it has no config.json
+	Version int
+}
+
+// Older reports whether the store predates this build and will be migrated
+func (c SchemaCheck) Older() bool {
+	return c.Version > 0 && c.Version < SchemaVersion
+}
+
+// Newer reports whether the store was written by a newer build
+func (c SchemaCheck) Newer() bool {
+	return c.Version > SchemaVersion
+}
+
+// CheckSchema reads the schema version of the store in dir without loading
+// or changing it
+func CheckSchema(dir string) (SchemaCheck, error) {
+	check := SchemaCheck{Path: filepath.Join(dir, "config.json")}
+
+	data, err := os.ReadFile(check.Path)
+	if os.IsNotExist(err) {
+		return check, nil
+	}
+	if err != nil {
+		return check, fmt.Errorf("failed to read config metadata: %w", err)
+	}
+
+	check.Version, err = metadataVersion(data)
+	return check, err
+}
+
+// metadataVersion returns the schema version of config.json contents
+func metadataVersion(data []byte) (int, error) {
+	data = bytes.TrimSpace(data)
+	if bytes.HasPrefix(data, []byte("[")) {
+		return 1, nil
+	}
+
+	var header struct {
+		SchemaVersion int `json:"schema_version"`
+	}
+	if err := json.Unmarshal(data, &header); err != nil || header.SchemaVersion == 0 {
+		return 0, fmt.Errorf("config metadata has no recognizable schema version")
+	}
+	return header.SchemaVersion, nil
+}
@@ -0,0 +1,487 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/Xanonymous-GitHub/claude-switch/internal/storage"
+)
+
+// ErrInvalidSelector is returned when a --select expression cannot be parsed
+var ErrInvalidSelector = errors.New("invalid select expression")
+
+// SelectorError describes where a select expression failed to parse
+type SelectorError struct {
+	Expr    string
+	Pos     int
+	Len     int
+	Message string
+}
+
+// Error renders the message followed by the expression with the offending
+// token underlined
+func (e *SelectorError) Error() string {
+	width := max(e.Len, 1)
+	return fmt.Sprintf("%s: %s at column %d\n  %s\n  %s%s",
+		ErrInvalidSelector, e.Message, e.Pos+1, e.Expr,
+		strings.Repeat(" ", e.Pos), strings.Repeat("^", width))
+}
+
+// Unwrap lets errors.Is match ErrInvalidSelector
+func (e *SelectorError) Unwrap() error {
+	return ErrInvalidSelector
+}
+
+// Selector is a parsed filter expression over configuration fields, such as
+// "name~work and created<30d and size>1kb". Comparisons on the fields name,
+// id, description, created, size, valid and default are combined with and,
+// or, not and parentheses.
+type Selector struct {
+	root selectNode
+}
+
+// ParseSelector parses a filter expression
+func ParseSelector(expr string) (*Selector, error) {
+	tokens, err := tokenizeSelector(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &selectParser{expr: expr, tokens: tokens}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokenEnd {
+		return nil, p.errorAt(tok, "unexpected '%s'", tok.text)
+	}
+	return &Selector{root: root}, nil
+}
+
+// Select returns the configurations matching sel
+func (m *Manager) Select(sel *Selector) []Config {
+	now := time.Now()
+	var matched []Config
+	for _, config := range m.configs {
+		record := &selectRecord{manager: m, config: &config, now: now}
+		if sel.root.eval(record) {
+			matched = append(matched, config)
+		}
+	}
+	return matched
+}
+
+// selectRecord is a configuration being matched. Size and validity are
+// computed on first use since they read the stored file.
+type selectRecord struct {
+	manager *Manager
+	config  *Config
+	now     time.Time
+	size    *int64
+	valid   *bool
+}
+
+func (r *selectRecord) fileSize() int64 {
+	if r.size == nil {
+		size, _ := storage.GetFileSize(r.config.FilePath)
+		r.size = &size
+	}
+	return *r.size
+}
+
+func (r *selectRecord) isValid() bool {
+	if r.valid == nil {
+		valid := r.manager.validateStored(r.config) == nil
+		r.valid = &valid
+	}
+	return *r.valid
+}
+
+type selectNode interface {
+	eval(r *selectRecord) bool
+}
+
+type andNode struct{ left, right selectNode }
+
+func (n andNode) eval(r *selectRecord) bool { return n.left.eval(r) && n.right.eval(r) }
+
+type orNode struct{ left, right selectNode }
+
+func (n orNode) eval(r *selectRecord) bool { return n.left.eval(r) || n.right.eval(r) }
+
+type notNode struct{ operand selectNode }
+
+func (n notNode) eval(r *selectRecord) bool { return !n.operand.eval(r) }
+
+// stringNode compares a text field; "~" is a case-insensitive substring match
+type stringNode struct {
+	field func(*Config) string
+	op    string
+	value string
+}
+
+func (n stringNode) eval(r *selectRecord) bool {
+	actual := n.field(r.config)
+	switch n.op {
+	case "=":
+		return actual == n.value
+	case "!=":
+		return actual != n.value
+	default:
+		return strings.Contains(strings.ToLower(actual), strings.ToLower(n.value))
+	}
+}
+
+// boolNode compares a boolean field
+type boolNode struct {
+	field func(*selectRecord) bool
+	want  bool
+}
+
+func (n boolNode) eval(r *selectRecord) bool { return n.field(r) == n.want }
+
+// sizeNode compares the stored file size in bytes
+type sizeNode struct {
+	op    string
+	value int64
+}
+
+func (n sizeNode) eval(r *selectRecord) bool {
+	return compareOrdered(r.fileSize(), n.op, n.value)
+}
+
+// createdNode compares the creation time, either against a date or, for a
+// duration literal, against the configuration's age: "created<30d" matches
+// configurations created less than 30 days ago
+type createdNode struct {
+	op   string
+	at   time.Time
+	age  time.Duration
+	date bool
+}
+
+func (n createdNode) eval(r *selectRecord) bool {
+	if n.date {
+		return compareOrdered(r.config.CreatedAt.UnixNano(), n.op, n.at.UnixNano())
+	}
+	return compareOrdered(r.now.Sub(r.config.CreatedAt), n.op, n.age)
+}
+
+// compareOrdered applies a comparison operator to two ordered values
+func compareOrdered[T int64 | time.Duration](a T, op string, b T) bool {
+	switch op {
+	case "=":
+		return a == b
+	case "!=":
+		return a != b
+	case "<":
+		return a < b
+	case "<=":
+		return a <= b
+	case ">":
+		return a > b
+	default:
+		return a >= b
+	}
+}
+
+type tokenKind int
+
+const (
+	tokenEnd tokenKind = iota
+	tokenWord
+	tokenString
+	tokenOp
+	tokenLParen
+	tokenRParen
+)
+
+type selectToken struct {
+	kind tokenKind
+	text string
+	pos  int
+	len  int
+}
+
+// selectOperators lists comparison operators, longest first
+var selectOperators = []string{"!=", "<=", ">=", "=", "<", ">", "~"}
+
+// tokenizeSelector splits an expression into words, quoted strings,
+// operators and parentheses, recording byte offsets for error messages
+func tokenizeSelector(expr string) ([]selectToken, error) {
+	var tokens []selectToken
+	i := 0
+	for i < len(expr) {
+		c := expr[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n':
+			i++
+		case c == '(':
+			tokens = append(tokens, selectToken{kind: tokenLParen, text: "(", pos: i, len: 1})
+			i++
+		case c == ')':
+			tokens = append(tokens, selectToken{kind: tokenRParen, text: ")", pos: i, len: 1})
+			i++
+		case c == '"' || c == '\'':
+			end := strings.IndexByte(expr[i+1:], c)
+			if end < 0 {
+				return nil, &SelectorError{Expr: expr, Pos: i, Len: len(expr) - i, Message: "unterminated string"}
+			}
+			tokens = append(tokens, selectToken{kind: tokenString, text: expr[i+1 : i+1+end], pos: i, len: end + 2})
+			i += end + 2
+		case strings.ContainsRune("=!<>~", rune(c)):
+			op := ""
+			for _, candidate := range selectOperators {
+				if strings.HasPrefix(expr[i:], candidate) {
+					op = candidate
+					break
+				}
+			}
+			if op == "" {
+				return nil, &SelectorError{Expr: expr, Pos: i, Len: 1, Message: fmt.Sprintf("unknown operator '%c'", c)}
+			}
+			tokens = append(tokens, selectToken{kind: tokenOp, text: op, pos: i, len: len(op)})
+			i += len(op)
+		default:
+			start := i
+			for i < len(expr) && !strings.ContainsRune(" \t\n()\"'=!<>~", rune(expr[i])) {
+				i++
+			}
+			tokens = append(tokens, selectToken{kind: tokenWord, text: expr[start:i], pos: start, len: i - start})
+		}
+	}
+	return append(tokens, selectToken{kind: tokenEnd, pos: len(expr)}), nil
+}
+
+// selectParser is a recursive descent parser:
+//
+//	or         = and { "or" and }
+//	and        = unary { "and" unary }
+//	unary      = "not" unary | "(" or ")" | comparison
+//	comparison = field [ operator value ]
+type selectParser struct {
+	expr   string
+	tokens []selectToken
+	next   int
+}
+
+func (p *selectParser) peek() selectToken {
+	return p.tokens[p.next]
+}
+
+func (p *selectParser) advance() selectToken {
+	tok := p.tokens[p.next]
+	if tok.kind != tokenEnd {
+		p.next++
+	}
+	return tok
+}
+
+// keyword reports whether the next token is the given keyword, consuming it
+func (p *selectParser) keyword(word string) bool {
+	if tok := p.peek(); tok.kind == tokenWord && strings.EqualFold(tok.text, word) {
+		p.next++
+		return true
+	}
+	return false
+}
+
+func (p *selectParser) errorAt(tok selectToken, format string, args ...interface{}) error {
+	return &SelectorError{Expr: p.expr, Pos: tok.pos, Len: tok.len, Message: fmt.Sprintf(format, args...)}
+}
+
+func (p *selectParser) parseOr() (selectNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("or") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orNode{left, right}
+	}
+	return left, nil
+}
+
+func (p *selectParser) parseAnd() (selectNode, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("and") {
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = andNode{left, right}
+	}
+	return left, nil
+}
+
+func (p *selectParser) parseUnary() (selectNode, error) {
+	if p.keyword("not") {
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{operand}, nil
+	}
+
+	tok := p.peek()
+	switch tok.kind {
+	case tokenLParen:
+		p.advance()
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.advance(); closing.kind != tokenRParen {
+			return nil, p.errorAt(closing, "expected ')'")
+		}
+		return inner, nil
+	case tokenWord:
+		return p.parseComparison()
+	case tokenEnd:
+		return nil, p.errorAt(tok, "expected a field name")
+	default:
+		return nil, p.errorAt(tok, "expected a field name, got '%s'", tok.text)
+	}
+}
+
+func (p *selectParser) parseComparison() (selectNode, error) {
+	fieldTok := p.advance()
+	field := strings.ToLower(fieldTok.text)
+
+	// Boolean fields may stand alone: "valid", "not default"
+	opTok := p.peek()
+	if opTok.kind != tokenOp {
+		if boolField, ok := selectBoolFields[field]; ok {
+			return boolNode{field: boolField, want: true}, nil
+		}
+		if _, known := selectFieldNames[field]; !known {
+			return nil, p.errorAt(fieldTok, "unknown field '%s' (valid: %s)", fieldTok.text, selectFieldList)
+		}
+		return nil, p.errorAt(opTok, "expected an operator after '%s'", fieldTok.text)
+	}
+	p.advance()
+
+	valueTok := p.advance()
+	if valueTok.kind != tokenWord && valueTok.kind != tokenString {
+		return nil, p.errorAt(valueTok, "expected a value after '%s'", opTok.text)
+	}
+	value := valueTok.text
+
+	if getter, ok := selectStringFields[field]; ok {
+		if opTok.text != "=" && opTok.text != "!=" && opTok.text != "~" {
+			return nil, p.errorAt(opTok, "operator '%s' not supported for %s (use =, != or ~)", opTok.text, field)
+		}
+		return stringNode{field: getter, op: opTok.text, value: value}, nil
+	}
+
+	if boolField, ok := selectBoolFields[field]; ok {
+		if opTok.text != "=" && opTok.text != "!=" {
+			return nil, p.errorAt(opTok, "operator '%s' not supported for %s (use = or !=)", opTok.text, field)
+		}
+		want, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, p.errorAt(valueTok, "invalid boolean '%s' (use true or false)", value)
+		}
+		return boolNode{field: boolField, want: want == (opTok.text == "=")}, nil
+	}
+
+	switch field {
+	case "size":
+		if opTok.text == "~" {
+			return nil, p.errorAt(opTok, "operator '~' not supported for size")
+		}
+		n, err := parseSizeLiteral(value)
+		if err != nil {
+			return nil, p.errorAt(valueTok, "%v", err)
+		}
+		return sizeNode{op: opTok.text, value: n}, nil
+	case "created":
+		if opTok.text == "~" || opTok.text == "=" || opTok.text == "!=" {
+			return nil, p.errorAt(opTok, "operator '%s' not supported for created (use <, <=, > or >=)", opTok.text)
+		}
+		if at, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+			return createdNode{op: opTok.text, at: at, date: true}, nil
+		}
+		if at, err := time.Parse(time.RFC3339, value); err == nil {
+			return createdNode{op: opTok.text, at: at, date: true}, nil
+		}
+		age, err := parseAgeLiteral(value)
+		if err != nil {
+			return nil, p.errorAt(valueTok, "invalid age or date '%s' (use e.g. 30d, 12h or 2006-01-02)", value)
+		}
+		return createdNode{op: opTok.text, age: age}, nil
+	}
+
+	return nil, p.errorAt(fieldTok, "unknown field '%s' (valid: %s)", fieldTok.text, selectFieldList)
+}
+
+var selectStringFields = map[string]func(*Config) string{
+	"name":        func(c *Config) string { return c.Name },
+	"id":          func(c *Config) string { return c.ID },
+	"description": func(c *Config) string { return c.Description },
+}
+
+var selectBoolFields = map[string]func(*selectRecord) bool{
+	"valid":   (*selectRecord).isValid,
+	"default": func(r *selectRecord) bool { return r.config.Default },
+}
+
+var selectFieldNames = map[string]struct{}{
+	"name": {}, "id": {}, "description": {}, "created": {}, "size": {}, "valid": {}, "default": {},
+}
+
+const selectFieldList = "name, id, description, created, size, valid, default"
+
+// parseSizeLiteral parses a size such as "512", "1kb" or "2MB" into bytes.
+// Units are powers of 1024.
+func parseSizeLiteral(s string) (int64, error) {
+	lower := strings.ToLower(s)
+	digits := strings.TrimRightFunc(lower, unicode.IsLetter)
+	multiplier := int64(1)
+	switch lower[len(digits):] {
+	case "", "b":
+	case "k", "kb":
+		multiplier = 1 << 10
+	case "m", "mb":
+		multiplier = 1 << 20
+	case "g", "gb":
+		multiplier = 1 << 30
+	default:
+		return 0, fmt.Errorf("invalid size '%s' (use e.g. 512, 1kb or 2mb)", s)
+	}
+
+	n, err := strconv.ParseFloat(digits, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid size '%s' (use e.g. 512, 1kb or 2mb)", s)
+	}
+	return int64(n * float64(multiplier)), nil
+}
+
+// parseAgeLiteral parses an age such as "30d", "2w" or "12h"
+func parseAgeLiteral(s string) (time.Duration, error) {
+	for suffix, unit := range map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour} {
+		if count, ok := strings.CutSuffix(s, suffix); ok {
+			n, err := strconv.Atoi(count)
+			if err != nil || n < 0 {
+				return 0, fmt.Errorf("invalid age '%s'", s)
+			}
+			return time.Duration(n) * unit, nil
+		}
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid age '%s'", s)
+	}
+	return d, nil
+}
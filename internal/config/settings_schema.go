@@ -0,0 +1,109 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/Xanonymous-GitHub/claude-switch/internal/validation"
+)
+
+// ErrSchemaFetch is returned when a settings schema cannot be downloaded and
+// no cached copy exists
+var ErrSchemaFetch = errors.New("failed to fetch settings schema")
+
+// maxSchemaSize limits the size of a downloaded settings schema (5 MB)
+const maxSchemaSize = 5 * 1024 * 1024
+
+// schemaFetchTimeout bounds the download of a settings schema
+const schemaFetchTimeout = 10 * time.Second
+
+// SettingsSchema is a JSON Schema for settings loaded from a URL
+type SettingsSchema struct {
+	Schema *validation.Schema
+	// CachePath is where the downloaded schema is cached in the store
+	CachePath string
+	// FromCache is set when the schema was read from the cache
+	FromCache bool
+	// FetchErr is why a stale cached schema was used instead of a fresh
+	// download; nil otherwise
+	FetchErr error
+}
+
+// LoadSettingsSchema returns the JSON Schema published at url. A cached copy
+// younger than ttl is used without downloading. When the download fails, an
+// older cached copy is used and the failure is recorded in FetchErr; without
+// one, LoadSettingsSchema fails with ErrSchemaFetch.
+func (m *Manager) LoadSettingsSchema(url string, ttl time.Duration) (*SettingsSchema, error) {
+	sum := sha256.Sum256([]byte(url))
+	loaded := &SettingsSchema{
+		CachePath: filepath.Join(m.configDir, "schemas", hex.EncodeToString(sum[:8])+".json"),
+	}
+
+	cached, cacheErr := os.ReadFile(loaded.CachePath)
+	if cacheErr == nil {
+		if info, err := os.Stat(loaded.CachePath); err == nil && time.Since(info.ModTime()) < ttl {
+			if loaded.Schema, err = validation.ParseSchema(cached); err == nil {
+				loaded.FromCache = true
+				return loaded, nil
+			}
+		}
+	}
+
+	data, fetchErr := fetchSchema(url)
+	if fetchErr == nil {
+		if loaded.Schema, fetchErr = validation.ParseSchema(data); fetchErr == nil {
+			if err := os.MkdirAll(filepath.Dir(loaded.CachePath), 0755); err == nil {
+				os.WriteFile(loaded.CachePath, data, 0644)
+			}
+			return loaded, nil
+		}
+	}
+	fetchErr = fmt.Errorf("%w from %s: %v", ErrSchemaFetch, url, fetchErr)
+
+	// Offline: a stale copy beats no schema at all
+	if cacheErr == nil {
+		if schema, err := validation.ParseSchema(cached); err == nil {
+			loaded.Schema = schema
+			loaded.FromCache = true
+			loaded.FetchErr = fetchErr
+			return loaded, nil
+		}
+	}
+	return nil, fetchErr
+}
+
+// fetchSchema downloads a schema document
+func fetchSchema(url string) ([]byte, error) {
+	client := &http.Client{Timeout: schemaFetchTimeout}
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("server returned %s", resp.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxSchemaSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxSchemaSize {
+		return nil, fmt.Errorf("schema exceeds %d bytes", maxSchemaSize)
+	}
+	return data, nil
+}
+
+// SetSettingsSchema makes validation also check configurations against
+// schema. A nil schema restores the built-in checks only.
+func (m *Manager) SetSettingsSchema(schema *validation.Schema) {
+	m.settingsSchema = schema
+}
@@ -0,0 +1,32 @@
+package config
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLoadSettingsSchemaDoesNotCacheCircularSchema(t *testing.T) {
+	manager := newTestManager(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"definitions": {"a": {"$ref": "#/definitions/a"}}, "$ref": "#/definitions/a"}`))
+	}))
+	defer server.Close()
+
+	loaded, err := manager.LoadSettingsSchema(server.URL, time.Hour)
+	if !errors.Is(err, ErrSchemaFetch) {
+		t.Fatalf("LoadSettingsSchema error = %v, want ErrSchemaFetch", err)
+	}
+	if loaded != nil {
+		t.Errorf("LoadSettingsSchema returned a schema for a circular document")
+	}
+
+	entries, err := os.ReadDir(filepath.Join(manager.GetConfigDir(), "schemas"))
+	if err == nil && len(entries) > 0 {
+		t.Errorf("circular schema was cached: %v", entries)
+	}
+}
@@ -0,0 +1,77 @@
+package config
+
+import (
+	"errors"
+	"time"
+)
+
+// StoreSnapshot is a point-in-time view of the store, see Manager.Snapshot
+type StoreSnapshot struct {
+	// TakenAt is when the snapshot was taken
+	TakenAt time.Time
+	// SchemaVersion is the config.json schema the metadata is held in
+	SchemaVersion int
+	// ConfigDir is the store directory
+	ConfigDir string
+	// ClaudeDir and SettingsPath locate Claude Code's settings; both are
+	// empty when the home directory cannot be determined
+	ClaudeDir    string
+	SettingsPath string
+	// Configs is a copy of the configurations, in store order
+	Configs []Config
+	// Active points into Configs at the configuration matching the live
+	// settings, and is nil when none does
+	Active *Config
+	// ActiveErr is set when the live settings could not be read; a
+	// missing, invalid or unmatched settings file only leaves Active nil
+	ActiveErr error
+	// Validation holds the outcome for each configuration, in the order
+	// of Configs, and Valid and Invalid count them
+	Validation []ValidationResult
+	Valid      int
+	Invalid    int
+}
+
+// Snapshot returns the configurations, directories, active configuration
+// and validation results of the store in one call. Everything is derived
+// from the metadata the Manager holds at the time of the call, and the
+// configurations are copied, so the snapshot is consistent and later
+// changes through the Manager do not alter it. Like the rest of Manager,
+// Snapshot must not run concurrently with methods that change the store.
+func (m *Manager) Snapshot() StoreSnapshot {
+	snapshot := StoreSnapshot{
+		TakenAt:       time.Now(),
+		SchemaVersion: SchemaVersion,
+		ConfigDir:     m.configDir,
+		Configs:       append([]Config(nil), m.configs...),
+	}
+
+	if claudeDir, err := m.GetClaudeDir(); err == nil {
+		snapshot.ClaudeDir = claudeDir
+		snapshot.SettingsPath, _ = m.GetClaudeSettingsPath()
+	}
+
+	active, err := m.ActiveConfig()
+	switch {
+	case err == nil:
+		for i := range snapshot.Configs {
+			if snapshot.Configs[i].ID == active.ID {
+				snapshot.Active = &snapshot.Configs[i]
+				break
+			}
+		}
+	case !errors.Is(err, ErrNoActive):
+		snapshot.ActiveErr = err
+	}
+
+	snapshot.Validation = m.ValidateConfigs()
+	for _, result := range snapshot.Validation {
+		if result.Err != nil {
+			snapshot.Invalid++
+		} else {
+			snapshot.Valid++
+		}
+	}
+
+	return snapshot
+}
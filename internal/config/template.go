@@ -0,0 +1,257 @@
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+	"text/template/parse"
+)
+
+// renderTemplate executes data as a Go template with vars as its data, so a
+// stored configuration can contain placeholders such as {{.API_URL}}.
+// Placeholders without a value are an error unless allowMissing is set, in
+// which case they render empty. Values are escaped for a JSON (or TOML)
+// string, where placeholders normally sit, so quotes and backslashes in a
+// value cannot break the settings.
+func renderTemplate(data []byte, vars map[string]string, allowMissing bool) ([]byte, error) {
+	missingKey := "missingkey=error"
+	if allowMissing {
+		missingKey = "missingkey=zero"
+	}
+
+	tmpl, err := template.New("config").Option(missingKey).Parse(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("invalid template: %w", err)
+	}
+
+	escaped := make(map[string]string, len(vars))
+	for key, value := range vars {
+		if escaped[key], err = escapeString(value); err != nil {
+			return nil, err
+		}
+	}
+
+	var rendered bytes.Buffer
+	if err := tmpl.Execute(&rendered, escaped); err != nil {
+		return nil, fmt.Errorf("failed to render template: %w", err)
+	}
+
+	return rendered.Bytes(), nil
+}
+
+// escapeString returns value as the contents of a JSON string, without the
+// surrounding quotes. HTML characters are kept as written.
+func escapeString(value string) (string, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		return "", fmt.Errorf("failed to escape template value: %w", err)
+	}
+	quoted := strings.TrimSuffix(buf.String(), "\n")
+	return quoted[1 : len(quoted)-1], nil
+}
+
+// LoadEnvFile reads KEY=VALUE pairs from a dotenv-style file. Blank lines,
+// # comments and a leading "export " are ignored, and values may be wrapped
+// in single or double quotes.
+func LoadEnvFile(path string) (map[string]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read env file: %w", err)
+	}
+	defer file.Close()
+
+	vars := make(map[string]string)
+	scanner := bufio.NewScanner(file)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+
+		key, value, ok := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("%s:%d: expected KEY=VALUE", path, lineNumber)
+		}
+
+		vars[key] = unquote(strings.TrimSpace(value))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read env file: %w", err)
+	}
+
+	return vars, nil
+}
+
+// unquote removes one pair of matching surrounding quotes from value
+func unquote(value string) string {
+	if len(value) >= 2 {
+		first, last := value[0], value[len(value)-1]
+		if first == last && (first == '"' || first == '\'') {
+			return value[1 : len(value)-1]
+		}
+	}
+	return value
+}
+
+// TemplateVar is a placeholder referenced by a configuration template
+type TemplateVar struct {
+	Name string
+	// Lines are the line numbers of each occurrence
+	Lines []int
+	// Default is the fallback given with {{or .NAME "value"}}, if any
+	Default    string
+	HasDefault bool
+}
+
+// TemplateVars returns the placeholders the configuration's template
+// references, in order of first appearance
+func (m *Manager) TemplateVars(identifier string) (*Config, []TemplateVar, error) {
+	config, err := m.GetConfig(identifier)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	data, err := os.ReadFile(config.FilePath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	vars, err := templateVars(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	return config, vars, nil
+}
+
+// templateVars parses data as a template and collects the top-level fields
+// it references. Fields inside range and with blocks refer to the rebound
+// dot rather than to a variable, so only $.NAME counts there.
+func templateVars(data []byte) ([]TemplateVar, error) {
+	tmpl, err := template.New("config").Parse(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("invalid template: %w", err)
+	}
+	if tmpl.Tree == nil {
+		return nil, nil
+	}
+
+	collector := &varCollector{data: data, index: map[string]int{}}
+	collector.walk(tmpl.Tree.Root, false)
+	return collector.vars, nil
+}
+
+// varCollector accumulates template variables while walking a parse tree
+type varCollector struct {
+	data  []byte
+	vars  []TemplateVar
+	index map[string]int
+}
+
+// add records an occurrence of name at pos
+func (c *varCollector) add(name string, pos parse.Pos) *TemplateVar {
+	i, ok := c.index[name]
+	if !ok {
+		i = len(c.vars)
+		c.index[name] = i
+		c.vars = append(c.vars, TemplateVar{Name: name})
+	}
+	v := &c.vars[i]
+	v.Lines = append(v.Lines, 1+bytes.Count(c.data[:pos], []byte("\n")))
+	return v
+}
+
+// walk visits node; rebound is set inside range and with bodies
+func (c *varCollector) walk(node parse.Node, rebound bool) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			c.walk(child, rebound)
+		}
+	case *parse.ActionNode:
+		c.walk(n.Pipe, rebound)
+	case *parse.IfNode:
+		c.walkBranch(&n.BranchNode, rebound, rebound)
+	case *parse.RangeNode:
+		c.walkBranch(&n.BranchNode, rebound, true)
+	case *parse.WithNode:
+		c.walkBranch(&n.BranchNode, rebound, true)
+	case *parse.TemplateNode:
+		c.walk(n.Pipe, rebound)
+	case *parse.PipeNode:
+		if n == nil {
+			return
+		}
+		for _, cmd := range n.Cmds {
+			c.walk(cmd, rebound)
+		}
+	case *parse.CommandNode:
+		c.walkCommand(n, rebound)
+	case *parse.ChainNode:
+		c.walk(n.Node, rebound)
+	case *parse.FieldNode:
+		if !rebound {
+			c.add(n.Ident[0], n.Position())
+		}
+	case *parse.VariableNode:
+		if len(n.Ident) > 1 && n.Ident[0] == "$" {
+			c.add(n.Ident[1], n.Position())
+		}
+	}
+}
+
+// walkBranch visits an if, range or with node; body is the rebound state of
+// its main list, while the else list keeps the outer dot
+func (c *varCollector) walkBranch(n *parse.BranchNode, rebound, body bool) {
+	c.walk(n.Pipe, rebound)
+	c.walk(n.List, body)
+	c.walk(n.ElseList, rebound)
+}
+
+// walkCommand visits a command, recognising {{or .NAME "default"}} as a
+// variable with a default value
+func (c *varCollector) walkCommand(n *parse.CommandNode, rebound bool) {
+	for _, arg := range n.Args {
+		c.walk(arg, rebound)
+	}
+
+	if len(n.Args) < 3 || rebound {
+		return
+	}
+	if ident, ok := n.Args[0].(*parse.IdentifierNode); !ok || ident.Ident != "or" {
+		return
+	}
+	fallback, ok := literal(n.Args[len(n.Args)-1])
+	if !ok {
+		return
+	}
+	for _, arg := range n.Args[1 : len(n.Args)-1] {
+		if field, ok := arg.(*parse.FieldNode); ok {
+			v := &c.vars[c.index[field.Ident[0]]]
+			v.Default, v.HasDefault = fallback, true
+		}
+	}
+}
+
+// literal returns the text of a constant template argument
+func literal(node parse.Node) (string, bool) {
+	switch n := node.(type) {
+	case *parse.StringNode:
+		return n.Text, true
+	case *parse.NumberNode:
+		return n.Text, true
+	case *parse.BoolNode:
+		return n.String(), true
+	}
+	return "", false
+}
@@ -0,0 +1,96 @@
+package config
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestRenderTemplateEscapesValues(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+	}{
+		{"plain", "https://api.example.com"},
+		{"quote", `a"b`},
+		{"backslash", `C:\src\app`},
+		{"newline", "line1\nline2"},
+		{"html", "a&b<c>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rendered, err := renderTemplate([]byte(`{"url": "{{.API_URL}}"}`), map[string]string{"API_URL": tt.value}, false)
+			if err != nil {
+				t.Fatalf("renderTemplate: %v", err)
+			}
+
+			var settings map[string]string
+			if err := json.Unmarshal(rendered, &settings); err != nil {
+				t.Fatalf("rendered settings are not valid JSON: %v\n%s", err, rendered)
+			}
+			if settings["url"] != tt.value {
+				t.Errorf("url = %q, want %q", settings["url"], tt.value)
+			}
+		})
+	}
+}
+
+func TestRenderTemplateMissingValue(t *testing.T) {
+	data := []byte(`{"url": "{{.API_URL}}"}`)
+
+	if _, err := renderTemplate(data, nil, false); err == nil {
+		t.Error("missing value rendered without error")
+	}
+
+	rendered, err := renderTemplate(data, nil, true)
+	if err != nil {
+		t.Fatalf("renderTemplate with allowMissing: %v", err)
+	}
+	if got, want := string(rendered), `{"url": ""}`; got != want {
+		t.Errorf("rendered %s, want %s", got, want)
+	}
+}
+
+func TestRenderConfigWithoutRenderKeepsBraces(t *testing.T) {
+	manager := newTestManager(t)
+	const settings = `{"statusLine": {"type": "command", "command": "echo {{branch}}"}}`
+	config := mustImport(t, manager, "status", settings)
+
+	if err := manager.ValidateConfig(config.ID); err != nil {
+		t.Fatalf("ValidateConfig: %v", err)
+	}
+
+	rendered, err := manager.RenderConfig(config.ID, ApplyOptions{})
+	if err != nil {
+		t.Fatalf("RenderConfig: %v", err)
+	}
+	if !strings.Contains(string(rendered), "echo {{branch}}") {
+		t.Errorf("rendered settings lost the braces:\n%s", rendered)
+	}
+
+	// Asking for rendering treats the braces as a template
+	if _, err := manager.RenderConfig(config.ID, ApplyOptions{Render: true}); err == nil {
+		t.Error("rendering a non-template with Render set succeeded")
+	}
+}
+
+func TestRenderConfigWithVars(t *testing.T) {
+	manager := newTestManager(t)
+	config := mustImport(t, manager, "team", `{"env": {"API_URL": "{{.API_URL}}"}}`)
+
+	rendered, err := manager.RenderConfig(config.ID, ApplyOptions{Render: true, Vars: map[string]string{"API_URL": `a"b`}})
+	if err != nil {
+		t.Fatalf("RenderConfig: %v", err)
+	}
+
+	var settings struct {
+		Env map[string]string `json:"env"`
+	}
+	if err := json.Unmarshal(rendered, &settings); err != nil {
+		t.Fatalf("rendered settings are not valid JSON: %v", err)
+	}
+	if settings.Env["API_URL"] != `a"b` {
+		t.Errorf("API_URL = %q, want %q", settings.Env["API_URL"], `a"b`)
+	}
+}
@@ -0,0 +1,78 @@
+package config
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/Xanonymous-GitHub/claude-switch/internal/jsonutil"
+)
+
+// TrackedConfig returns the configuration last applied with tracking, or
+// ErrNoTracked if the last apply was not tracked
+func (m *Manager) TrackedConfig() (*Config, error) {
+	for _, config := range m.configs {
+		if config.Tracked {
+			return &config, nil
+		}
+	}
+	return nil, ErrNoTracked
+}
+
+// setTracked marks the configuration with the given ID as tracked, clearing
+// any other; an empty ID clears tracking. Metadata is only written when the
+// marker moves.
+func (m *Manager) setTracked(id string) error {
+	changed := false
+	for i := range m.configs {
+		tracked := m.configs[i].ID == id
+		if m.configs[i].Tracked != tracked {
+			m.configs[i].Tracked = tracked
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+
+	if err := m.saveConfigs(); err != nil {
+		return fmt.Errorf("failed to update config metadata: %w", err)
+	}
+	return nil
+}
+
+// CaptureChanges compares a stored configuration with the live
+// settings.json. Changes go from the configuration (old) to the live
+// settings (new), i.e. what CaptureSettings would write.
+func (m *Manager) CaptureChanges(identifier string) (*Config, []jsonutil.Change, error) {
+	config, live, configured, err := m.LoadWithSettings(identifier)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return config, jsonutil.Diff(configured, live), nil
+}
+
+// CaptureSettings stores the live settings.json as a new revision of the
+// configuration, converted to its stored format. It returns the new revision
+// number.
+func (m *Manager) CaptureSettings(identifier string) (*Config, int, error) {
+	config, err := m.GetConfig(identifier)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	settingsPath, err := m.GetClaudeSettingsPath()
+	if err != nil {
+		return nil, 0, err
+	}
+	data, err := os.ReadFile(settingsPath)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to read current settings: %w", err)
+	}
+
+	if data, err = FromJSON(data, config.StoredFormat()); err != nil {
+		return nil, 0, err
+	}
+
+	return m.UpdateConfig(config.ID, data)
+}
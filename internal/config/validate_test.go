@@ -0,0 +1,86 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+)
+
+// populateStore adds n configurations, every third of them made invalid on
+// disk after being stored
+func populateStore(tb testing.TB, manager *Manager, n int, size int) {
+	tb.Helper()
+
+	padding := strings.Repeat("x", size)
+	for i := range n {
+		config, err := manager.ImportConfig(fmt.Sprintf("config-%03d", i), "", []byte(fmt.Sprintf(`{"model": "m%d", "note": %q}`, i, padding)))
+		if err != nil {
+			tb.Fatalf("ImportConfig: %v", err)
+		}
+		if i%3 == 0 {
+			if err := os.WriteFile(config.FilePath, []byte(`{"model": `), 0644); err != nil {
+				tb.Fatal(err)
+			}
+		}
+	}
+}
+
+// validateSerially validates every configuration one after another, as
+// ValidateConfigs did before it used a worker pool
+func validateSerially(manager *Manager) []ValidationResult {
+	results := make([]ValidationResult, len(manager.configs))
+	for i, config := range manager.configs {
+		results[i] = ValidationResult{Config: config, Err: manager.validateStored(&config)}
+	}
+	return results
+}
+
+func TestValidateConfigsMatchesSerial(t *testing.T) {
+	manager := newTestManager(t)
+	populateStore(t, manager, 40, 16)
+
+	parallel := manager.ValidateConfigs()
+	serial := validateSerially(manager)
+
+	if len(parallel) != len(serial) {
+		t.Fatalf("ValidateConfigs returned %d results, want %d", len(parallel), len(serial))
+	}
+	invalid := 0
+	for i := range serial {
+		if parallel[i].Config.ID != serial[i].Config.ID {
+			t.Errorf("result %d is for %s, want %s", i, parallel[i].Config.Name, serial[i].Config.Name)
+		}
+		if (parallel[i].Err == nil) != (serial[i].Err == nil) {
+			t.Errorf("%s: parallel error %v, serial error %v", serial[i].Config.Name, parallel[i].Err, serial[i].Err)
+		} else if parallel[i].Err != nil && parallel[i].Err.Error() != serial[i].Err.Error() {
+			t.Errorf("%s: parallel error %q, serial error %q", serial[i].Config.Name, parallel[i].Err, serial[i].Err)
+		}
+		if serial[i].Err != nil {
+			invalid++
+		}
+	}
+	if invalid != 14 {
+		t.Errorf("%d invalid configurations, want 14", invalid)
+	}
+}
+
+func BenchmarkValidateConfigs(b *testing.B) {
+	b.Setenv("HOME", b.TempDir())
+	manager, err := NewManagerWithOptions(WithDir(b.TempDir()))
+	if err != nil {
+		b.Fatal(err)
+	}
+	populateStore(b, manager, 64, 256*1024)
+
+	b.Run("serial", func(b *testing.B) {
+		for b.Loop() {
+			validateSerially(manager)
+		}
+	})
+	b.Run("parallel", func(b *testing.B) {
+		for b.Loop() {
+			manager.ValidateConfigs()
+		}
+	})
+}
@@ -0,0 +1,159 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Op identifies the kind of a diff line
+type Op int
+
+const (
+	Equal Op = iota
+	Delete
+	Insert
+)
+
+// Line is a single line of a line-based diff
+type Line struct {
+	Op   Op
+	Text string
+}
+
+// Lines computes a line-based diff between a and b using the longest common subsequence
+func Lines(a, b string) []Line {
+	aLines := splitLines(a)
+	bLines := splitLines(b)
+
+	// lcs[i][j] is the LCS length of aLines[i:] and bLines[j:]
+	lcs := make([][]int, len(aLines)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(bLines)+1)
+	}
+	for i := len(aLines) - 1; i >= 0; i-- {
+		for j := len(bLines) - 1; j >= 0; j-- {
+			if aLines[i] == bLines[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var lines []Line
+	i, j := 0, 0
+	for i < len(aLines) && j < len(bLines) {
+		switch {
+		case aLines[i] == bLines[j]:
+			lines = append(lines, Line{Equal, aLines[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			lines = append(lines, Line{Delete, aLines[i]})
+			i++
+		default:
+			lines = append(lines, Line{Insert, bLines[j]})
+			j++
+		}
+	}
+	for ; i < len(aLines); i++ {
+		lines = append(lines, Line{Delete, aLines[i]})
+	}
+	for ; j < len(bLines); j++ {
+		lines = append(lines, Line{Insert, bLines[j]})
+	}
+
+	return lines
+}
+
+// Unified renders a unified diff between a and b with the given number of
+// context lines around each change. It returns an empty string when a and b
+// are identical.
+func Unified(aName, bName, a, b string, context int) string {
+	lines := Lines(a, b)
+
+	changed := false
+	for _, line := range lines {
+		if line.Op != Equal {
+			changed = true
+			break
+		}
+	}
+	if !changed {
+		return ""
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- %s\n+++ %s\n", aName, bName)
+
+	for start := 0; start < len(lines); {
+		// Find the next change
+		first := start
+		for first < len(lines) && lines[first].Op == Equal {
+			first++
+		}
+		if first == len(lines) {
+			break
+		}
+
+		// Extend the hunk while changes are within 2*context of each other
+		last := first
+		for k := first; k < len(lines); k++ {
+			if lines[k].Op != Equal {
+				last = k
+			} else if k-last > 2*context {
+				break
+			}
+		}
+
+		hunkStart := max(first-context, start)
+		hunkEnd := min(last+context+1, len(lines))
+		writeHunk(&sb, lines, hunkStart, hunkEnd)
+		start = hunkEnd
+	}
+
+	return sb.String()
+}
+
+// writeHunk writes lines[from:to] as a unified diff hunk
+func writeHunk(sb *strings.Builder, lines []Line, from, to int) {
+	aStart, bStart := 1, 1
+	for _, line := range lines[:from] {
+		if line.Op != Insert {
+			aStart++
+		}
+		if line.Op != Delete {
+			bStart++
+		}
+	}
+
+	aCount, bCount := 0, 0
+	for _, line := range lines[from:to] {
+		if line.Op != Insert {
+			aCount++
+		}
+		if line.Op != Delete {
+			bCount++
+		}
+	}
+
+	fmt.Fprintf(sb, "@@ -%d,%d +%d,%d @@\n", aStart, aCount, bStart, bCount)
+	for _, line := range lines[from:to] {
+		switch line.Op {
+		case Equal:
+			sb.WriteString(" " + line.Text + "\n")
+		case Delete:
+			sb.WriteString("-" + line.Text + "\n")
+		case Insert:
+			sb.WriteString("+" + line.Text + "\n")
+		}
+	}
+}
+
+// splitLines splits s into lines, ignoring a single trailing newline
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
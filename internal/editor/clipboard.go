@@ -0,0 +1,63 @@
+package editor
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+)
+
+// ErrNoClipboard is returned by CopyToClipboard when no clipboard tool is found
+var ErrNoClipboard = errors.New("no clipboard tool found")
+
+// CopyToClipboard puts data on the system clipboard using the platform's
+// clipboard tool
+func CopyToClipboard(data []byte) error {
+	tool := getClipboard()
+	if tool == nil {
+		return ErrNoClipboard
+	}
+
+	cmd := exec.Command(tool[0], tool[1:]...)
+	cmd.Stdin = bytes.NewReader(data)
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to run %s: %w", tool[0], err)
+	}
+	return nil
+}
+
+// getClipboard returns the command and arguments that copy standard input to
+// the clipboard, or nil if no clipboard tool is installed
+func getClipboard() []string {
+	var candidates [][]string
+	switch runtime.GOOS {
+	case "windows":
+		candidates = [][]string{{"clip"}}
+	case "darwin":
+		candidates = [][]string{{"pbcopy"}}
+	default:
+		// Prefer the Wayland tool inside a Wayland session
+		if os.Getenv("WAYLAND_DISPLAY") != "" {
+			candidates = append(candidates, []string{"wl-copy"})
+		}
+		candidates = append(candidates,
+			[]string{"xclip", "-selection", "clipboard"},
+			[]string{"xsel", "--clipboard", "--input"},
+		)
+	}
+
+	for _, candidate := range candidates {
+		if _, err := exec.LookPath(candidate[0]); err == nil {
+			return candidate
+		}
+	}
+	return nil
+}
+
+// IsClipboardAvailable checks if a clipboard tool is available
+func IsClipboardAvailable() bool {
+	return getClipboard() != nil
+}
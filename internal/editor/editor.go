@@ -1,12 +1,17 @@
 package editor
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"runtime"
 )
 
+// ErrCancelled is returned by OpenEditor when the editor exits with a
+// non-zero status, such as :cq in vim, to abandon the edit
+var ErrCancelled = errors.New("editing cancelled")
+
 // OpenEditor opens the specified file in the user's preferred editor
 func OpenEditor(filePath string) error {
 	editor := getEditor()
@@ -19,7 +24,18 @@ func OpenEditor(filePath string) error {
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 
-	return cmd.Run()
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+		return nil
+	case errors.As(err, &exitErr):
+		return fmt.Errorf("%w (editor exited with status %d)", ErrCancelled, exitErr.ExitCode())
+	case errors.Is(err, exec.ErrNotFound), errors.Is(err, os.ErrNotExist):
+		return fmt.Errorf("editor '%s' not found: %w", editor, err)
+	default:
+		return err
+	}
 }
 
 // getEditor returns the user's preferred editor
@@ -60,6 +76,11 @@ func getEditor() string {
 	return ""
 }
 
+// Name returns the editor OpenEditor would run, or "" if none is found
+func Name() string {
+	return getEditor()
+}
+
 // IsEditorAvailable checks if an editor is available
 func IsEditorAvailable() bool {
 	return getEditor() != ""
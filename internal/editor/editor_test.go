@@ -0,0 +1,64 @@
+package editor
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// fakeEditor installs a shell script as $EDITOR for the test
+func fakeEditor(t *testing.T, script string) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("fake editor scripts need a POSIX shell")
+	}
+
+	path := filepath.Join(t.TempDir(), "editor")
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+script+"\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("EDITOR", path)
+}
+
+func TestOpenEditor(t *testing.T) {
+	fakeEditor(t, `printf '{"model": "opus"}' > "$1"`)
+	file := filepath.Join(t.TempDir(), "settings.json")
+
+	if err := OpenEditor(file); err != nil {
+		t.Fatalf("OpenEditor: %v", err)
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"model": "opus"}` {
+		t.Errorf("edited file holds %q", data)
+	}
+}
+
+func TestOpenEditorNonZeroExitCancels(t *testing.T) {
+	fakeEditor(t, `printf '{"model": ' > "$1"; exit 1`)
+
+	err := OpenEditor(filepath.Join(t.TempDir(), "settings.json"))
+	if !errors.Is(err, ErrCancelled) {
+		t.Fatalf("OpenEditor error = %v, want ErrCancelled", err)
+	}
+	if !strings.Contains(err.Error(), "status 1") {
+		t.Errorf("OpenEditor error = %q, want the exit status", err)
+	}
+}
+
+func TestOpenEditorNotFound(t *testing.T) {
+	t.Setenv("EDITOR", filepath.Join(t.TempDir(), "missing-editor"))
+
+	err := OpenEditor(filepath.Join(t.TempDir(), "settings.json"))
+	if err == nil || errors.Is(err, ErrCancelled) {
+		t.Fatalf("OpenEditor error = %v, want a not found error", err)
+	}
+	if !strings.Contains(err.Error(), "not found") {
+		t.Errorf("OpenEditor error = %q, want it to say the editor was not found", err)
+	}
+}
@@ -0,0 +1,47 @@
+package editor
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+)
+
+// OpenPath opens a file or directory with the platform's default handler,
+// e.g. a directory in Finder, Explorer or the desktop file manager
+func OpenPath(path string) error {
+	opener := getOpener()
+	if opener == "" {
+		return fmt.Errorf("no file opener found. Open %s manually", path)
+	}
+
+	cmd := exec.Command(opener, path)
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to run %s: %w", opener, err)
+	}
+
+	// The file manager outlives us; don't wait for it
+	return cmd.Process.Release()
+}
+
+// getOpener returns the platform's command for opening files and directories
+func getOpener() string {
+	var opener string
+	switch runtime.GOOS {
+	case "windows":
+		opener = "explorer"
+	case "darwin":
+		opener = "open"
+	default:
+		opener = "xdg-open"
+	}
+
+	if _, err := exec.LookPath(opener); err != nil {
+		return ""
+	}
+	return opener
+}
+
+// IsOpenerAvailable checks if a file opener is available
+func IsOpenerAvailable() bool {
+	return getOpener() != ""
+}
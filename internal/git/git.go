@@ -0,0 +1,84 @@
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// Available reports whether the git binary can be found
+func Available() bool {
+	_, err := exec.LookPath("git")
+	return err == nil
+}
+
+// IsWorkTree reports whether dir is inside a git working tree. It is false
+// when git is not installed.
+func IsWorkTree(dir string) bool {
+	if !Available() {
+		return false
+	}
+
+	out, err := run(dir, "rev-parse", "--is-inside-work-tree")
+	return err == nil && out == "true"
+}
+
+// CommitAll stages every change under dir and commits it with message. It
+// does nothing when there is nothing to commit.
+func CommitAll(dir, message string) (bool, error) {
+	if _, err := run(dir, "add", "-A", "--", "."); err != nil {
+		return false, err
+	}
+
+	// diff --quiet exits 1 when there are staged changes
+	if _, err := run(dir, "diff", "--cached", "--quiet", "--", "."); err == nil {
+		return false, nil
+	}
+
+	if _, err := run(dir, "commit", "--quiet", "-m", message, "--", "."); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// CommitPath stages path and commits it, and only it, with message. It does
+// nothing when path has no changes to commit.
+func CommitPath(dir, path, message string) (bool, error) {
+	if _, err := run(dir, "add", "--", path); err != nil {
+		return false, err
+	}
+
+	if _, err := run(dir, "diff", "--cached", "--quiet", "--", path); err == nil {
+		return false, nil
+	}
+
+	if _, err := run(dir, "commit", "--quiet", "-m", message, "--", path); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// LastCommit returns "<short hash> <date> <subject>" of the last commit that
+// touched path, or "" if it has none
+func LastCommit(dir, path string) (string, error) {
+	return run(dir, "log", "-1", "--format=%h %cs %s", "--", path)
+}
+
+// run executes git in dir and returns its trimmed standard output
+func run(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("git %s: %s", args[0], msg)
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+
+	return strings.TrimSpace(stdout.String()), nil
+}
@@ -0,0 +1,87 @@
+package jsonutil
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// ChangeKind identifies how a value differs between two JSON documents
+type ChangeKind string
+
+const (
+	Added   ChangeKind = "added"
+	Removed ChangeKind = "removed"
+	Changed ChangeKind = "changed"
+)
+
+// Change is a single difference between two JSON documents at a key path
+type Change struct {
+	Path string      `json:"path"`
+	Kind ChangeKind  `json:"kind"`
+	Old  interface{} `json:"old,omitempty"`
+	New  interface{} `json:"new,omitempty"`
+}
+
+// Diff compares two decoded JSON values and returns the differences as key
+// paths (e.g. "permissions.allow[0]"), sorted by path. Objects are compared
+// key by key and arrays element by element; key order never matters.
+func Diff(old, new interface{}) []Change {
+	var changes []Change
+	diffValues("", old, new, &changes)
+	sort.SliceStable(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+	return changes
+}
+
+// diffValues appends the differences between old and new at path to changes
+func diffValues(path string, old, new interface{}, changes *[]Change) {
+	oldObj, oldIsObj := old.(map[string]interface{})
+	newObj, newIsObj := new.(map[string]interface{})
+	if oldIsObj && newIsObj {
+		for key, oldValue := range oldObj {
+			childPath := joinPath(path, key)
+			if newValue, ok := newObj[key]; ok {
+				diffValues(childPath, oldValue, newValue, changes)
+			} else {
+				*changes = append(*changes, Change{Path: childPath, Kind: Removed, Old: oldValue})
+			}
+		}
+		for key, newValue := range newObj {
+			if _, ok := oldObj[key]; !ok {
+				*changes = append(*changes, Change{Path: joinPath(path, key), Kind: Added, New: newValue})
+			}
+		}
+		return
+	}
+
+	oldArr, oldIsArr := old.([]interface{})
+	newArr, newIsArr := new.([]interface{})
+	if oldIsArr && newIsArr {
+		for i := 0; i < max(len(oldArr), len(newArr)); i++ {
+			childPath := fmt.Sprintf("%s[%d]", path, i)
+			switch {
+			case i >= len(newArr):
+				*changes = append(*changes, Change{Path: childPath, Kind: Removed, Old: oldArr[i]})
+			case i >= len(oldArr):
+				*changes = append(*changes, Change{Path: childPath, Kind: Added, New: newArr[i]})
+			default:
+				diffValues(childPath, oldArr[i], newArr[i], changes)
+			}
+		}
+		return
+	}
+
+	if !reflect.DeepEqual(old, new) {
+		*changes = append(*changes, Change{Path: path, Kind: Changed, Old: old, New: new})
+	}
+}
+
+// joinPath appends an object key to a key path
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
@@ -0,0 +1,336 @@
+package jsonutil
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// How MergeWithStrategy combines an array in the overlay with an array at
+// the same key in the base
+const (
+	// ArraysReplace uses the overlay array
+	ArraysReplace = "replace"
+	// ArraysConcat appends the overlay items to the base items
+	ArraysConcat = "concat"
+	// ArraysUnion appends the overlay items to the base items, dropping
+	// items equal to an earlier one
+	ArraysUnion = "union"
+)
+
+// ArrayStrategies lists the array strategies MergeWithStrategy accepts
+var ArrayStrategies = []string{ArraysReplace, ArraysConcat, ArraysUnion}
+
+// utf8BOM is the byte order mark some editors write at the start of a file
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// ParseObject parses data as a top-level JSON object. Numbers are kept as
+// json.Number, so they are written back exactly as they were read.
+func ParseObject(data []byte) (map[string]interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(bytes.TrimPrefix(data, utf8BOM)))
+	decoder.UseNumber()
+
+	var obj map[string]interface{}
+	if err := decoder.Decode(&obj); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON object: %w", err)
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, fmt.Errorf("failed to parse JSON object: unexpected data after top-level value")
+	}
+	if obj == nil {
+		return nil, fmt.Errorf("expected a JSON object, got null")
+	}
+	return obj, nil
+}
+
+// MarshalIndent encodes v as two-space indented JSON followed by a newline.
+// Characters such as < and & are written as is rather than escaped.
+func MarshalIndent(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(v); err != nil {
+		return nil, fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// Project returns a copy of obj restricted to the top-level keys in only (when
+// non-empty) and without the top-level keys in drop
+func Project(obj map[string]interface{}, only, drop []string) map[string]interface{} {
+	result := make(map[string]interface{}, len(obj))
+
+	if len(only) > 0 {
+		for _, key := range only {
+			if value, ok := obj[key]; ok {
+				result[key] = value
+			}
+		}
+	} else {
+		for key, value := range obj {
+			result[key] = value
+		}
+	}
+
+	for _, key := range drop {
+		delete(result, key)
+	}
+
+	return result
+}
+
+// Merge deep-merges overlay into a copy of base. Objects are merged by key;
+// any other overlay value, including arrays, replaces the base value.
+func Merge(base, overlay map[string]interface{}) map[string]interface{} {
+	return MergeWithStrategy(base, overlay, ArraysReplace)
+}
+
+// MergeWithStrategy is Merge with arrays at the same key, at any depth,
+// combined according to arrays (one of ArrayStrategies). Other overlay
+// values replace the base value.
+func MergeWithStrategy(base, overlay map[string]interface{}, arrays string) map[string]interface{} {
+	return mergeAt("", base, overlay, arrays, nil)
+}
+
+// Conflict is a key path that both sides of a merge set to different values
+// the merge cannot combine, so one replaces the other
+type Conflict struct {
+	Path     string
+	Current  interface{}
+	Incoming interface{}
+}
+
+// MergeConflicts returns the conflicts of MergeWithStrategy(base, overlay,
+// arrays), sorted by path
+func MergeConflicts(base, overlay map[string]interface{}, arrays string) []Conflict {
+	var conflicts []Conflict
+	mergeAt("", base, overlay, arrays, func(path string, current, incoming interface{}) interface{} {
+		conflicts = append(conflicts, Conflict{Path: path, Current: current, Incoming: incoming})
+		return incoming
+	})
+	sort.Slice(conflicts, func(i, j int) bool { return conflicts[i].Path < conflicts[j].Path })
+	return conflicts
+}
+
+// MergeResolved is MergeWithStrategy with the value at each conflicting path
+// taken from resolved when it holds one; other conflicts keep the overlay
+// value
+func MergeResolved(base, overlay map[string]interface{}, arrays string, resolved map[string]interface{}) map[string]interface{} {
+	return mergeAt("", base, overlay, arrays, func(path string, current, incoming interface{}) interface{} {
+		if value, ok := resolved[path]; ok {
+			return value
+		}
+		return incoming
+	})
+}
+
+// mergeAt merges overlay into a copy of base, the object at path. Where both
+// set a key to different values it cannot combine, resolve picks the result,
+// or the overlay value wins when resolve is nil.
+func mergeAt(path string, base, overlay map[string]interface{}, arrays string, resolve func(path string, current, incoming interface{}) interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(base)+len(overlay))
+	for key, value := range base {
+		result[key] = value
+	}
+
+	for key, value := range overlay {
+		childPath := joinPath(path, key)
+		switch overlayValue := value.(type) {
+		case map[string]interface{}:
+			if baseObj, ok := result[key].(map[string]interface{}); ok {
+				result[key] = mergeAt(childPath, baseObj, overlayValue, arrays, resolve)
+				continue
+			}
+		case []interface{}:
+			if baseArr, ok := result[key].([]interface{}); ok && arrays != ArraysReplace {
+				result[key] = mergeArrays(baseArr, overlayValue, arrays == ArraysUnion)
+				continue
+			}
+		}
+
+		if current, exists := result[key]; exists && resolve != nil && !reflect.DeepEqual(current, value) {
+			result[key] = resolve(childPath, current, value)
+			continue
+		}
+		result[key] = value
+	}
+
+	return result
+}
+
+// mergeArrays concatenates base and overlay into a new array, keeping only
+// the first of equal items when dedupe is set
+func mergeArrays(base, overlay []interface{}, dedupe bool) []interface{} {
+	result := make([]interface{}, 0, len(base)+len(overlay))
+	for _, item := range append(append([]interface{}(nil), base...), overlay...) {
+		if dedupe && containsEqual(result, item) {
+			continue
+		}
+		result = append(result, item)
+	}
+	return result
+}
+
+// containsEqual reports whether items holds a value deeply equal to item
+func containsEqual(items []interface{}, item interface{}) bool {
+	for _, candidate := range items {
+		if reflect.DeepEqual(candidate, item) {
+			return true
+		}
+	}
+	return false
+}
+
+// Lookup returns the value at a dot-separated key path such as
+// "permissions.allow", and whether it exists
+func Lookup(obj map[string]interface{}, path string) (interface{}, bool) {
+	var current interface{} = obj
+	for _, key := range strings.Split(path, ".") {
+		node, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if current, ok = node[key]; !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// Set stores value at a dot-separated key path in obj, creating missing
+// intermediate objects. It fails if an intermediate value is not an object.
+func Set(obj map[string]interface{}, path string, value interface{}) error {
+	keys := strings.Split(path, ".")
+	node := obj
+	for i, key := range keys[:len(keys)-1] {
+		child, ok := node[key]
+		if !ok {
+			next := map[string]interface{}{}
+			node[key] = next
+			node = next
+			continue
+		}
+		if node, ok = child.(map[string]interface{}); !ok {
+			return fmt.Errorf("'%s' is not an object", strings.Join(keys[:i+1], "."))
+		}
+	}
+	node[keys[len(keys)-1]] = value
+	return nil
+}
+
+// Trim returns a copy of obj without keys whose value is null, "", {} or [],
+// recursing into nested objects and objects inside arrays. Objects left empty
+// by trimming are removed as well. It also returns the number of keys removed.
+func Trim(obj map[string]interface{}) (map[string]interface{}, int) {
+	result := make(map[string]interface{}, len(obj))
+	removed := 0
+	for key, value := range obj {
+		value, n := trimValue(value)
+		removed += n
+		if isEmpty(value) {
+			removed++
+			continue
+		}
+		result[key] = value
+	}
+	return result, removed
+}
+
+// trimValue trims the objects within value; array elements are kept even
+// when empty, since removing them would shift the remaining ones
+func trimValue(value interface{}) (interface{}, int) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return Trim(v)
+	case []interface{}:
+		result := make([]interface{}, len(v))
+		removed := 0
+		for i, element := range v {
+			var n int
+			result[i], n = trimValue(element)
+			removed += n
+		}
+		return result, removed
+	default:
+		return value, 0
+	}
+}
+
+// isEmpty reports whether value is null, "", {} or []
+func isEmpty(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case map[string]interface{}:
+		return len(v) == 0
+	case []interface{}:
+		return len(v) == 0
+	default:
+		return false
+	}
+}
+
+// Canonical re-encodes JSON data in a canonical form: compact, with object
+// keys sorted at every level, so documents that differ only in key order or
+// whitespace encode identically. Numbers are kept as written, so large
+// integers are not rounded together. A leading UTF-8 byte order mark is
+// ignored.
+func Canonical(data []byte) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(bytes.TrimPrefix(data, utf8BOM)))
+	decoder.UseNumber()
+
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON: %w", err)
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, fmt.Errorf("failed to parse JSON: unexpected data after top-level value")
+	}
+
+	// encoding/json writes map keys in sorted order, recursively
+	canonical, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	return canonical, nil
+}
+
+// Hash returns the hex SHA-256 of the canonical form of JSON data
+func Hash(data []byte) (string, error) {
+	canonical, err := Canonical(data)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(canonical)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// SortKeys re-encodes JSON data indented with object keys sorted at every
+// level. Numbers and strings are kept exactly as written.
+func SortKeys(data []byte) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON: %w", err)
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(value); err != nil {
+		return nil, fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,202 @@
+package jsonutil
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCanonicalIgnoresKeyOrderAndWhitespace(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+	}{
+		{"top level", `{"a": 1, "b": 2}`, `{"b":2,"a":1}`},
+		{"nested", `{"env": {"X": "1", "Y": "2"}, "model": "opus"}`, "{\n  \"model\": \"opus\",\n  \"env\": {\"Y\": \"2\", \"X\": \"1\"}\n}\n"},
+		{"objects in arrays", `{"list": [{"a": 1, "b": 2}]}`, `{"list": [{"b": 2, "a": 1}]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := Canonical([]byte(tt.a))
+			if err != nil {
+				t.Fatalf("Canonical: %v", err)
+			}
+			b, err := Canonical([]byte(tt.b))
+			if err != nil {
+				t.Fatalf("Canonical: %v", err)
+			}
+			if string(a) != string(b) {
+				t.Errorf("Canonical forms differ: %s and %s", a, b)
+			}
+
+			hashA, _ := Hash([]byte(tt.a))
+			hashB, _ := Hash([]byte(tt.b))
+			if hashA != hashB {
+				t.Errorf("Hash differs for equivalent documents: %s and %s", hashA, hashB)
+			}
+		})
+	}
+}
+
+func TestHashDistinguishesContent(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+	}{
+		{"value", `{"a": 1}`, `{"a": 2}`},
+		{"array order", `{"a": [1, 2]}`, `{"a": [2, 1]}`},
+		{"type", `{"a": "1"}`, `{"a": 1}`},
+		{"extra key", `{"a": 1}`, `{"a": 1, "b": null}`},
+		{"large integer", `{"a": 12345678901234567890}`, `{"a": 12345678901234567891}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hashA, err := Hash([]byte(tt.a))
+			if err != nil {
+				t.Fatalf("Hash: %v", err)
+			}
+			hashB, err := Hash([]byte(tt.b))
+			if err != nil {
+				t.Fatalf("Hash: %v", err)
+			}
+			if hashA == hashB {
+				t.Errorf("Hash(%s) == Hash(%s)", tt.a, tt.b)
+			}
+		})
+	}
+}
+
+func TestHashRejectsInvalidJSON(t *testing.T) {
+	if _, err := Hash([]byte(`{"a": `)); err == nil {
+		t.Error("Hash succeeded on invalid JSON")
+	}
+	if _, err := Hash([]byte(`{"a": 1} {"b": 2}`)); err == nil {
+		t.Error("Hash succeeded on trailing data")
+	}
+}
+
+func TestMergeWithStrategy(t *testing.T) {
+	base := `{
+		"permissions": {"allow": ["Read", "Edit"], "deny": ["Bash"]},
+		"mcpServers": {"list": [{"name": "fs", "port": 1}, {"name": "git"}]},
+		"model": "sonnet"
+	}`
+	overlay := `{
+		"permissions": {"allow": ["Edit", "Write"]},
+		"mcpServers": {"list": [{"name": "git"}, {"name": "web"}]},
+		"model": "opus"
+	}`
+
+	tests := []struct {
+		strategy string
+		want     string
+	}{
+		{ArraysReplace, `{
+			"permissions": {"allow": ["Edit", "Write"], "deny": ["Bash"]},
+			"mcpServers": {"list": [{"name": "git"}, {"name": "web"}]},
+			"model": "opus"
+		}`},
+		{ArraysConcat, `{
+			"permissions": {"allow": ["Read", "Edit", "Edit", "Write"], "deny": ["Bash"]},
+			"mcpServers": {"list": [{"name": "fs", "port": 1}, {"name": "git"}, {"name": "git"}, {"name": "web"}]},
+			"model": "opus"
+		}`},
+		{ArraysUnion, `{
+			"permissions": {"allow": ["Read", "Edit", "Write"], "deny": ["Bash"]},
+			"mcpServers": {"list": [{"name": "fs", "port": 1}, {"name": "git"}, {"name": "web"}]},
+			"model": "opus"
+		}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.strategy, func(t *testing.T) {
+			got := MergeWithStrategy(mustParse(t, base), mustParse(t, overlay), tt.strategy)
+			if want := mustParse(t, tt.want); !reflect.DeepEqual(got, want) {
+				t.Errorf("MergeWithStrategy(%s) = %v, want %v", tt.strategy, got, want)
+			}
+		})
+	}
+}
+
+func TestMergeArrayReplacesNonArray(t *testing.T) {
+	// An array only combines with an array; any other base value is replaced
+	got := MergeWithStrategy(mustParse(t, `{"a": "x", "b": {"c": 1}}`), mustParse(t, `{"a": [1], "b": [2]}`), ArraysUnion)
+	if want := mustParse(t, `{"a": [1], "b": [2]}`); !reflect.DeepEqual(got, want) {
+		t.Errorf("MergeWithStrategy = %v, want %v", got, want)
+	}
+}
+
+func TestMergeDoesNotModifyInputs(t *testing.T) {
+	base := mustParse(t, `{"a": [1], "b": {"c": [2]}}`)
+	overlay := mustParse(t, `{"a": [3], "b": {"c": [4]}}`)
+
+	MergeWithStrategy(base, overlay, ArraysConcat)
+	if want := mustParse(t, `{"a": [1], "b": {"c": [2]}}`); !reflect.DeepEqual(base, want) {
+		t.Errorf("base changed to %v", base)
+	}
+}
+
+// mustParse parses a JSON object for a test
+func mustParse(t *testing.T, data string) map[string]interface{} {
+	t.Helper()
+	obj, err := ParseObject([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return obj
+}
+
+func TestParseObjectRoundTrip(t *testing.T) {
+	data := "{\n  \"cmd\": \"a < b && c > d\",\n  \"id\": 12345678901234567890,\n  \"ratio\": 1.50,\n  \"timeout\": 3000000\n}\n"
+
+	obj, err := ParseObject([]byte(data))
+	if err != nil {
+		t.Fatalf("ParseObject: %v", err)
+	}
+	out, err := MarshalIndent(obj)
+	if err != nil {
+		t.Fatalf("MarshalIndent: %v", err)
+	}
+	if string(out) != data {
+		t.Errorf("round trip changed the settings:\n%s\nwant:\n%s", out, data)
+	}
+}
+
+func TestParseObject(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr bool
+	}{
+		{"object", `{"a": 1}`, false},
+		{"byte order mark", "\xEF\xBB\xBF{\"a\": 1}", false},
+		{"null", `null`, true},
+		{"array", `[1]`, true},
+		{"trailing data", `{"a": 1} {"b": 2}`, true},
+		{"invalid", `{"a": `, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseObject([]byte(tt.data))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseObject error = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestHashIgnoresByteOrderMark(t *testing.T) {
+	plain, err := Hash([]byte(`{"a": 1}`))
+	if err != nil {
+		t.Fatalf("Hash: %v", err)
+	}
+	withBOM, err := Hash([]byte("\xEF\xBB\xBF{\"a\": 1}"))
+	if err != nil {
+		t.Fatalf("Hash with a byte order mark: %v", err)
+	}
+	if plain != withBOM {
+		t.Errorf("Hash differs with a byte order mark: %s and %s", plain, withBOM)
+	}
+}
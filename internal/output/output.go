@@ -0,0 +1,167 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode/utf8"
+)
+
+// colorEnabled is the resolved color/emoji mode shared by all commands
+var colorEnabled = resolve(false, false, os.Getenv("NO_COLOR") != "", isTerminal(os.Stdout))
+
+// quiet suppresses informational output written to standard output
+var quiet bool
+
+// sortKeys sorts the keys of printed settings JSON
+var sortKeys = !isTerminal(os.Stdout)
+
+// SetQuiet enables or disables quiet mode. In quiet mode Printf, Println and
+// Print write nothing; the Fprint variants are unaffected.
+func SetQuiet(enabled bool) {
+	quiet = enabled
+}
+
+// Quiet reports whether quiet mode is enabled
+func Quiet() bool {
+	return quiet
+}
+
+// Configure resolves the color mode from the global flags. The order of
+// precedence is --force-color, then --no-color / NO_COLOR, then TTY detection.
+func Configure(forceColor, noColor bool) {
+	colorEnabled = resolve(forceColor, noColor, os.Getenv("NO_COLOR") != "", isTerminal(os.Stdout))
+}
+
+// resolve applies the color mode precedence rules
+func resolve(forceColor, noColor, noColorEnv, tty bool) bool {
+	if forceColor {
+		return true
+	}
+	if noColor || noColorEnv {
+		return false
+	}
+	return tty
+}
+
+// ConfigureSortKeys resolves whether printed settings JSON has its keys
+// sorted. --sort-keys wins over --no-sort-keys; by default keys are sorted
+// when standard output is not a terminal.
+func ConfigureSortKeys(sort, noSort bool) {
+	sortKeys = resolve(sort, noSort, false, !isTerminal(os.Stdout))
+}
+
+// SortKeys reports whether printed settings JSON should have sorted keys
+func SortKeys() bool {
+	return sortKeys
+}
+
+// ColorEnabled reports whether color and emoji output is enabled
+func ColorEnabled() bool {
+	return colorEnabled
+}
+
+// Format returns s unchanged when color is enabled, or otherwise without the
+// emoji that start its lines. Emoji elsewhere, such as in configuration
+// names, are kept.
+func Format(s string) string {
+	if colorEnabled {
+		return s
+	}
+	return stripEmoji(s)
+}
+
+// Printf formats according to a format specifier and writes to standard output
+func Printf(format string, a ...any) {
+	if quiet {
+		return
+	}
+	Fprintf(os.Stdout, format, a...)
+}
+
+// Println formats using the default formats and writes a line to standard output
+func Println(a ...any) {
+	if quiet {
+		return
+	}
+	Fprintln(os.Stdout, a...)
+}
+
+// Print formats using the default formats and writes to standard output
+func Print(a ...any) {
+	if quiet {
+		return
+	}
+	Fprint(os.Stdout, a...)
+}
+
+// Fprintf formats according to a format specifier and writes to w. Emoji
+// are stripped from the format only, so the arguments are written as given.
+func Fprintf(w io.Writer, format string, a ...any) {
+	fmt.Fprintf(w, Format(format), a...)
+}
+
+// Fprintln formats using the default formats and writes a line to w
+func Fprintln(w io.Writer, a ...any) {
+	fmt.Fprint(w, Format(fmt.Sprintln(a...)))
+}
+
+// Fprint formats using the default formats and writes to w
+func Fprint(w io.Writer, a ...any) {
+	fmt.Fprint(w, Format(fmt.Sprint(a...)))
+}
+
+// Interactive reports whether standard input is a terminal, i.e. whether
+// prompts can be answered
+func Interactive() bool {
+	return isTerminal(os.Stdin)
+}
+
+// Terminal reports whether standard output is a terminal, i.e. whether it
+// can be redrawn in place
+func Terminal() bool {
+	return isTerminal(os.Stdout)
+}
+
+// isTerminal reports whether f is connected to a terminal
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// isEmoji reports whether r is a pictographic symbol
+func isEmoji(r rune) bool {
+	switch {
+	case r >= 0x1F000 && r <= 0x1FAFF: // pictographs, emoticons, symbols
+		return true
+	case r >= 0x2600 && r <= 0x27BF: // misc symbols and dingbats
+		return true
+	case r >= 0x2300 && r <= 0x23FF: // misc technical (⏪, ⏳, ...)
+		return true
+	case r >= 0x2B00 && r <= 0x2BFF: // misc symbols and arrows
+		return true
+	}
+	return false
+}
+
+// stripEmoji removes the emoji that start each line of s, after any
+// indentation, and the spacing that follows them
+func stripEmoji(s string) string {
+	lines := strings.SplitAfter(s, "\n")
+	for i, line := range lines {
+		indented := strings.TrimLeft(line, " ")
+		if first, _ := utf8.DecodeRuneInString(indented); !isEmoji(first) {
+			continue
+		}
+		// Variation selectors and joiners only count inside an emoji sequence
+		rest := strings.TrimLeftFunc(indented, func(r rune) bool {
+			return isEmoji(r) || r == 0xFE0F || r == 0x200D
+		})
+		lines[i] = line[:len(line)-len(indented)] + strings.TrimLeft(rest, " ")
+	}
+	return strings.Join(lines, "")
+}
@@ -0,0 +1,45 @@
+package output
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestStripEmoji(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"leading", "✅ Configuration is valid\n", "Configuration is valid\n"},
+		{"variation selector", "⚠️  Missing key\n", "Missing key\n"},
+		{"indented", "   💾 Backup saved\n", "   Backup saved\n"},
+		{"each line", "🔍 Dry run\n✅ Done\n", "Dry run\nDone\n"},
+		{"joined sequence", "👩‍💻 Developer\n", "Developer\n"},
+		{"emoji inside text", "Name: rocket 🚀 launch\n", "Name: rocket 🚀 launch\n"},
+		{"symbols inside text", "a ☀ b ⌘ c ⬆ d\n", "a ☀ b ⌘ c ⬆ d\n"},
+		{"indic joiner", "क्‍ष\n", "क्‍ष\n"},
+		{"plain", "   ID: abc\n", "   ID: abc\n"},
+		{"empty", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := stripEmoji(tt.in); got != tt.want {
+				t.Errorf("stripEmoji(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFprintfKeepsArguments(t *testing.T) {
+	enabled := colorEnabled
+	colorEnabled = false
+	defer func() { colorEnabled = enabled }()
+
+	var buf bytes.Buffer
+	Fprintf(&buf, "✅ %s - Valid\n", "🚀 launch")
+	if got, want := buf.String(), "🚀 launch - Valid\n"; got != want {
+		t.Errorf("Fprintf wrote %q, want %q", got, want)
+	}
+}
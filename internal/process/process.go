@@ -0,0 +1,267 @@
+package process
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// ErrUnsupported is returned when processes cannot be enumerated on this system
+var ErrUnsupported = errors.New("process enumeration is not available")
+
+// FindClaude returns the IDs of running Claude Code processes. Detection is
+// best-effort: it matches a "claude" executable or a node process whose
+// script is the Claude Code package's cli.js.
+func FindClaude() ([]int, error) {
+	var (
+		procs []proc
+		err   error
+	)
+
+	switch runtime.GOOS {
+	case "linux":
+		procs, err = listProc()
+	case "windows":
+		procs, err = listTasklist()
+	default:
+		procs, err = listPS()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	self := os.Getpid()
+	var pids []int
+	for _, p := range procs {
+		if p.pid != self && isClaude(p) {
+			pids = append(pids, p.pid)
+		}
+	}
+	return pids, nil
+}
+
+// claudeScript is the script npm installs of Claude Code run with node
+const claudeScript = "@anthropic-ai/claude-code/cli.js"
+
+// psScript finds the script of a node command line as ps prints it: after
+// any options, up to and including the Claude Code script, so a path with
+// spaces stays whole
+var psScript = regexp.MustCompile(`^\s*(?:-\S+\s+)*(.+?` + regexp.QuoteMeta(claudeScript) + `)(?:\s|$)`)
+
+// proc is a running process: its executable and, for interpreters, the
+// script it runs
+type proc struct {
+	pid    int
+	name   string
+	script string
+}
+
+// isClaude reports whether a process is Claude Code: the claude executable,
+// or node running the Claude Code package
+func isClaude(p proc) bool {
+	// Windows paths may reach here on any system through ps or tasklist
+	name := strings.ToLower(path.Base(strings.ReplaceAll(p.name, `\`, "/")))
+	switch name {
+	case "claude", "claude.exe":
+		return true
+	case "node", "node.exe":
+		return strings.HasSuffix(strings.ReplaceAll(p.script, `\`, "/"), claudeScript)
+	default:
+		return false
+	}
+}
+
+// firstOperand returns the first argument that is not an option, which for
+// an interpreter is the script it runs
+func firstOperand(args []string) string {
+	for _, arg := range args {
+		if !strings.HasPrefix(arg, "-") {
+			return arg
+		}
+	}
+	return ""
+}
+
+// listProc reads command lines from /proc
+func listProc() ([]proc, error) {
+	entries, err := os.ReadDir("/proc")
+	if err != nil {
+		return nil, ErrUnsupported
+	}
+
+	var procs []proc
+	for _, entry := range entries {
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil {
+			continue
+		}
+
+		// Processes can exit while we scan
+		cmdline, err := os.ReadFile(filepath.Join("/proc", entry.Name(), "cmdline"))
+		if err != nil || len(cmdline) == 0 {
+			continue
+		}
+
+		args := strings.Split(strings.TrimRight(string(cmdline), "\x00"), "\x00")
+		procs = append(procs, proc{pid: pid, name: args[0], script: firstOperand(args[1:])})
+	}
+	return procs, nil
+}
+
+// listPS lists processes with ps on macOS and other Unix systems. The
+// executable comes from comm, which is not split on spaces; args only
+// supplies the script.
+func listPS() ([]proc, error) {
+	comm, err := exec.Command("ps", "-axo", "pid=,comm=").Output()
+	if err != nil {
+		return nil, ErrUnsupported
+	}
+	args, err := exec.Command("ps", "-axo", "pid=,args=").Output()
+	if err != nil {
+		return nil, ErrUnsupported
+	}
+	return parsePS(comm, args), nil
+}
+
+// parsePS combines the output of ps -o pid=,comm= and ps -o pid=,args=
+func parsePS(comm, args []byte) []proc {
+	commandLines := psColumn(args)
+
+	var procs []proc
+	for _, entry := range psEntries(comm) {
+		p := proc{pid: entry.pid, name: entry.value}
+
+		// Drop the executable from the command line, then find the script
+		line := commandLines[entry.pid]
+		if rest, ok := strings.CutPrefix(line, p.name); ok {
+			line = rest
+		} else if _, rest, ok := strings.Cut(strings.TrimSpace(line), " "); ok {
+			line = rest
+		} else {
+			line = ""
+		}
+		if match := psScript.FindStringSubmatch(line); match != nil {
+			p.script = match[1]
+		} else {
+			p.script = firstOperand(strings.Fields(line))
+		}
+
+		procs = append(procs, p)
+	}
+	return procs
+}
+
+// psEntry is one line of ps output: a PID and the rest of the line
+type psEntry struct {
+	pid   int
+	value string
+}
+
+// psEntries parses lines of a PID followed by one column, keeping the
+// column's spaces
+func psEntries(out []byte) []psEntry {
+	var entries []psEntry
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		pidField, value, ok := strings.Cut(strings.TrimSpace(scanner.Text()), " ")
+		if !ok {
+			continue
+		}
+		pid, err := strconv.Atoi(pidField)
+		if err != nil {
+			continue
+		}
+		entries = append(entries, psEntry{pid: pid, value: strings.TrimSpace(value)})
+	}
+	return entries
+}
+
+// psColumn maps each PID to its column value
+func psColumn(out []byte) map[int]string {
+	column := map[int]string{}
+	for _, entry := range psEntries(out) {
+		column[entry.pid] = entry.value
+	}
+	return column
+}
+
+// listTasklist lists process images with tasklist on Windows
+func listTasklist() ([]proc, error) {
+	out, err := exec.Command("tasklist", "/FO", "CSV", "/NH").Output()
+	if err != nil {
+		return nil, ErrUnsupported
+	}
+
+	reader := csv.NewReader(bytes.NewReader(out))
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, ErrUnsupported
+	}
+
+	var procs []proc
+	for _, record := range records {
+		if len(record) < 2 {
+			continue
+		}
+		pid, err := strconv.Atoi(record[1])
+		if err != nil {
+			continue
+		}
+		procs = append(procs, proc{pid: pid, name: record[0]})
+	}
+	return procs, nil
+}
+
+// Terminate asks each process to exit (SIGTERM, or a hard kill on Windows)
+// and waits up to timeout for the Claude Code processes among them to go
+func Terminate(pids []int, timeout time.Duration) error {
+	for _, pid := range pids {
+		p, err := os.FindProcess(pid)
+		if err != nil {
+			continue
+		}
+		if runtime.GOOS == "windows" {
+			err = p.Kill()
+		} else {
+			err = p.Signal(syscall.SIGTERM)
+		}
+		if err != nil && !errors.Is(err, os.ErrProcessDone) {
+			return fmt.Errorf("failed to stop process %d: %w", pid, err)
+		}
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		running, err := FindClaude()
+		if err != nil {
+			return nil
+		}
+		remaining := 0
+		for _, pid := range running {
+			if slices.Contains(pids, pid) {
+				remaining++
+			}
+		}
+		if remaining == 0 {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("%d process(es) still running after %s", remaining, timeout)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
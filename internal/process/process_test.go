@@ -0,0 +1,69 @@
+package process
+
+import "testing"
+
+func TestIsClaude(t *testing.T) {
+	tests := []struct {
+		name string
+		proc proc
+		want bool
+	}{
+		{"claude executable", proc{name: "/usr/local/bin/claude"}, true},
+		{"claude on windows", proc{name: `C:\Tools\Claude.exe`}, true},
+		{"npm install", proc{name: "node", script: "/usr/lib/node_modules/@anthropic-ai/claude-code/cli.js"}, true},
+		{"npm install on windows", proc{name: "node.exe", script: `C:\npm\node_modules\@anthropic-ai\claude-code\cli.js`}, true},
+		{"editor on a claude-code file", proc{name: "vim", script: "/home/u/src/claude-code/notes.md"}, false},
+		{"tail of a log", proc{name: "tail", script: "claude-code.log"}, false},
+		{"grep", proc{name: "grep", script: "claude-code"}, false},
+		{"node running another script", proc{name: "node", script: "/home/u/claude-code/server.js"}, false},
+		{"claude-switch", proc{name: "/usr/local/bin/claude-switch"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isClaude(tt.proc); got != tt.want {
+				t.Errorf("isClaude(%+v) = %v, want %v", tt.proc, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParsePS(t *testing.T) {
+	comm := []byte(`  101 /Applications/My Tools/claude
+  102 /usr/local/bin/node
+  103 /usr/bin/vim
+  104 /usr/local/bin/node
+`)
+	args := []byte(`  101 /Applications/My Tools/claude --continue
+  102 /usr/local/bin/node --no-warnings /Users/Jane Doe/.npm/lib/node_modules/@anthropic-ai/claude-code/cli.js --resume
+  103 /usr/bin/vim /Users/jane/src/claude-code/notes.md
+  104 node /srv/app/server.js
+`)
+
+	procs := parsePS(comm, args)
+	if len(procs) != 4 {
+		t.Fatalf("parsePS returned %d processes, want 4: %+v", len(procs), procs)
+	}
+
+	want := []struct {
+		name, script string
+		claude       bool
+	}{
+		{"/Applications/My Tools/claude", "", true},
+		{"/usr/local/bin/node", "/Users/Jane Doe/.npm/lib/node_modules/@anthropic-ai/claude-code/cli.js", true},
+		{"/usr/bin/vim", "/Users/jane/src/claude-code/notes.md", false},
+		{"/usr/local/bin/node", "/srv/app/server.js", false},
+	}
+	for i, w := range want {
+		p := procs[i]
+		if p.name != w.name {
+			t.Errorf("process %d name = %q, want %q", p.pid, p.name, w.name)
+		}
+		if w.claude != isClaude(p) {
+			t.Errorf("isClaude(%+v) = %v, want %v", p, !w.claude, w.claude)
+		}
+		if p.script != w.script {
+			t.Errorf("process %d script = %q, want %q", p.pid, p.script, w.script)
+		}
+	}
+}
@@ -1,10 +1,14 @@
 package storage
 
 import (
+	"bytes"
+	"compress/gzip"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"strings"
 )
 
 // EnsureDir creates a directory if it doesn't exist
@@ -38,15 +42,41 @@ func IsValidJSON(filePath string) error {
 
 // AtomicWrite writes data to a file atomically by writing to a temporary file first
 func AtomicWrite(filePath string, data []byte) error {
+	return AtomicWriteMode(filePath, data, 0644)
+}
+
+// AtomicWriteMode is AtomicWrite with the file created with perm
+func AtomicWriteMode(filePath string, data []byte, perm os.FileMode) error {
+	return AtomicWriteFrom(filePath, bytes.NewReader(data), perm)
+}
+
+// AtomicWriteFrom streams r into a temporary file next to filePath, which
+// then replaces filePath, so memory use does not grow with the file size.
+// The file gets exactly perm, whatever the umask.
+func AtomicWriteFrom(filePath string, r io.Reader, perm os.FileMode) error {
 	dir := filepath.Dir(filePath)
 	if err := EnsureDir(dir); err != nil {
 		return err
 	}
 
-	tempFile := filePath + ".tmp"
+	temp, err := os.CreateTemp(dir, filepath.Base(filePath)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	tempFile := temp.Name()
 
-	// Write to temporary file
-	if err := os.WriteFile(tempFile, data, 0644); err != nil {
+	_, err = io.Copy(temp, r)
+	if err == nil {
+		err = temp.Chmod(perm)
+	}
+	if err == nil {
+		err = temp.Sync()
+	}
+	if closeErr := temp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tempFile)
 		return fmt.Errorf("failed to write temporary file: %w", err)
 	}
 
@@ -59,27 +89,69 @@ func AtomicWrite(filePath string, data []byte) error {
 	return nil
 }
 
-// SafeCopy copies a file with validation
+// SafeCopy copies a file atomically, keeping its permissions
 func SafeCopy(src, dst string) error {
-	// Validate source file exists and is readable
-	if !FileExists(src) {
-		return fmt.Errorf("source file does not exist: %s", src)
-	}
+	return CopyFile(src, dst, 0)
+}
 
-	// Read source file
-	data, err := os.ReadFile(src)
+// CopyFile copies src to dst atomically, streaming the contents. dst gets
+// perm, or the permissions of src when perm is 0.
+func CopyFile(src, dst string, perm os.FileMode) error {
+	source, perm, err := openSource(src, perm)
 	if err != nil {
-		return fmt.Errorf("failed to read source file: %w", err)
+		return err
 	}
+	defer source.Close()
 
-	// Write to destination atomically
-	if err := AtomicWrite(dst, data); err != nil {
+	if err := AtomicWriteFrom(dst, source, perm); err != nil {
 		return fmt.Errorf("failed to write destination file: %w", err)
 	}
 
 	return nil
 }
 
+// openSource opens a file to copy, resolving perm 0 to its permissions
+func openSource(src string, perm os.FileMode) (*os.File, os.FileMode, error) {
+	source, err := os.Open(src)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, 0, fmt.Errorf("source file does not exist: %s", src)
+		}
+		return nil, 0, fmt.Errorf("failed to read source file: %w", err)
+	}
+
+	if perm == 0 {
+		info, err := source.Stat()
+		if err != nil {
+			source.Close()
+			return nil, 0, fmt.Errorf("failed to read source file: %w", err)
+		}
+		perm = info.Mode().Perm()
+	}
+	return source, perm, nil
+}
+
+// FileMode returns the permissions of filePath, or fallback when it cannot
+// be read, so a rewritten file keeps the mode it had
+func FileMode(filePath string, fallback os.FileMode) os.FileMode {
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return fallback
+	}
+	return info.Mode().Perm()
+}
+
+// FormatSize returns a human-readable representation of a byte count
+func FormatSize(size int64) string {
+	if size < 1024 {
+		return fmt.Sprintf("%d B", size)
+	} else if size < 1024*1024 {
+		return fmt.Sprintf("%.1f KB", float64(size)/1024)
+	} else {
+		return fmt.Sprintf("%.1f MB", float64(size)/(1024*1024))
+	}
+}
+
 // GetFileSize returns the size of a file in bytes
 func GetFileSize(filePath string) (int64, error) {
 	info, err := os.Stat(filePath)
@@ -88,3 +160,72 @@ func GetFileSize(filePath string) (int64, error) {
 	}
 	return info.Size(), nil
 }
+
+// IsCompressed reports whether a file is gzip-compressed, judged by its extension
+func IsCompressed(filePath string) bool {
+	return strings.HasSuffix(filePath, ".gz")
+}
+
+// ReadFile reads a file, transparently decompressing it when it has a .gz extension
+func ReadFile(filePath string) ([]byte, error) {
+	if !IsCompressed(filePath) {
+		data, err := os.ReadFile(filePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read file: %w", err)
+		}
+		return data, nil
+	}
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	defer file.Close()
+
+	reader, err := gzip.NewReader(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open compressed file: %w", err)
+	}
+	defer reader.Close()
+
+	decompressed, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress file: %w", err)
+	}
+
+	return decompressed, nil
+}
+
+// CompressCopy copies src to dst gzip-compressed, keeping its permissions.
+// Like CopyFile it streams the contents and writes atomically.
+func CompressCopy(src, dst string) error {
+	source, perm, err := openSource(src, 0)
+	if err != nil {
+		return err
+	}
+	defer source.Close()
+
+	return CompressFrom(dst, source, perm)
+}
+
+// CompressFrom atomically writes the contents of r to filePath
+// gzip-compressed, streaming them
+func CompressFrom(filePath string, r io.Reader, perm os.FileMode) error {
+	reader, writer := io.Pipe()
+	go func() {
+		compressor := gzip.NewWriter(writer)
+		_, err := io.Copy(compressor, r)
+		if closeErr := compressor.Close(); err == nil {
+			err = closeErr
+		}
+		writer.CloseWithError(err)
+	}()
+
+	if err := AtomicWriteFrom(filePath, reader, perm); err != nil {
+		// Unblock the compressor if writing stopped early
+		reader.CloseWithError(err)
+		return fmt.Errorf("failed to write compressed file: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,144 @@
+package storage
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCopyFileLarge(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "large.json")
+	data := bytes.Repeat([]byte("0123456789abcdef"), 1<<20) // 16 MiB
+	if err := os.WriteFile(src, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	dst := filepath.Join(dir, "copy.json")
+	if err := CopyFile(src, dst, 0); err != nil {
+		t.Fatalf("CopyFile: %v", err)
+	}
+
+	copied, err := os.ReadFile(dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(copied, data) {
+		t.Errorf("copy holds %d bytes that differ from the %d bytes of the source", len(copied), len(data))
+	}
+	assertNoTempFiles(t, dir)
+}
+
+func TestCopyFileMode(t *testing.T) {
+	tests := []struct {
+		name    string
+		srcMode os.FileMode
+		perm    os.FileMode
+		want    os.FileMode
+	}{
+		{"keeps private mode", 0600, 0, 0600},
+		{"keeps group mode", 0640, 0, 0640},
+		{"sets explicit mode", 0644, 0600, 0600},
+		{"widens only when asked", 0600, 0644, 0644},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			src := filepath.Join(dir, "settings.json")
+			if err := os.WriteFile(src, []byte(`{}`), tt.srcMode); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.Chmod(src, tt.srcMode); err != nil {
+				t.Fatal(err)
+			}
+
+			dst := filepath.Join(dir, "copy.json")
+			if err := CopyFile(src, dst, tt.perm); err != nil {
+				t.Fatalf("CopyFile: %v", err)
+			}
+			info, err := os.Stat(dst)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if info.Mode().Perm() != tt.want {
+				t.Errorf("copy mode = %v, want %v", info.Mode().Perm(), tt.want)
+			}
+		})
+	}
+}
+
+func TestCopyFileReplacesDestination(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "src.json")
+	dst := filepath.Join(dir, "dst.json")
+	if err := os.WriteFile(src, []byte(`{"new": true}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(dst, []byte(`{"old": true, "longer": "than the new file"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := SafeCopy(src, dst); err != nil {
+		t.Fatalf("SafeCopy: %v", err)
+	}
+	copied, err := os.ReadFile(dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(copied) != `{"new": true}` {
+		t.Errorf("destination holds %q after the copy", copied)
+	}
+	assertNoTempFiles(t, dir)
+}
+
+func TestCopyFileMissingSource(t *testing.T) {
+	dir := t.TempDir()
+	dst := filepath.Join(dir, "dst.json")
+
+	err := CopyFile(filepath.Join(dir, "missing.json"), dst, 0)
+	if err == nil || !strings.Contains(err.Error(), "does not exist") {
+		t.Errorf("CopyFile error = %v, want a missing source error", err)
+	}
+	if FileExists(dst) {
+		t.Error("CopyFile created the destination for a missing source")
+	}
+}
+
+func TestCompressCopyRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "settings.json")
+	data := []byte(`{"model": "opus"}`)
+	if err := os.WriteFile(src, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	dst := filepath.Join(dir, "settings.json.gz")
+	if err := CompressCopy(src, dst); err != nil {
+		t.Fatalf("CompressCopy: %v", err)
+	}
+	if !IsCompressed(dst) {
+		t.Errorf("%s is not reported as compressed", dst)
+	}
+	read, err := ReadFile(dst)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if !bytes.Equal(read, data) {
+		t.Errorf("ReadFile = %q, want %q", read, data)
+	}
+}
+
+// assertNoTempFiles fails when an atomic write left a temporary file in dir
+func assertNoTempFiles(t *testing.T, dir string) {
+	t.Helper()
+	matches, err := filepath.Glob(filepath.Join(dir, "*.tmp"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(matches) > 0 {
+		t.Errorf("temporary files left behind: %v", matches)
+	}
+}
@@ -0,0 +1,92 @@
+package validation
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// DuplicateKey is an object key that appears more than once in the same
+// object. encoding/json silently keeps the last value for such keys.
+type DuplicateKey struct {
+	// Path is the JSON path of the repeated key, e.g. "editor.theme"
+	Path string
+	Key  string
+}
+
+// FindDuplicateKeys scans data with a token stream and reports every object
+// key that repeats within its object, in document order. A leading UTF-8
+// byte order mark is ignored.
+func FindDuplicateKeys(data []byte) ([]DuplicateKey, error) {
+	data, err := NormalizeEncoding(data)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var duplicates []DuplicateKey
+	if err := scanValue(decoder, "", &duplicates); err != nil {
+		return nil, fmt.Errorf("invalid JSON format: %w", err)
+	}
+
+	// Trailing data means the input was not a single JSON value
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, fmt.Errorf("invalid JSON format: unexpected data after top-level value")
+	}
+
+	return duplicates, nil
+}
+
+// scanValue consumes one value from the decoder, recording repeated keys of
+// any object it contains
+func scanValue(decoder *json.Decoder, path string, duplicates *[]DuplicateKey) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+
+	switch token {
+	case json.Delim('{'):
+		seen := make(map[string]bool)
+		for decoder.More() {
+			keyToken, err := decoder.Token()
+			if err != nil {
+				return err
+			}
+			key := keyToken.(string)
+
+			keyPath := joinPath(path, key)
+			if seen[key] {
+				*duplicates = append(*duplicates, DuplicateKey{Path: keyPath, Key: key})
+			}
+			seen[key] = true
+
+			if err := scanValue(decoder, keyPath, duplicates); err != nil {
+				return err
+			}
+		}
+		_, err = decoder.Token() // closing '}'
+		return err
+	case json.Delim('['):
+		for index := 0; decoder.More(); index++ {
+			if err := scanValue(decoder, fmt.Sprintf("%s[%d]", path, index), duplicates); err != nil {
+				return err
+			}
+		}
+		_, err = decoder.Token() // closing ']'
+		return err
+	default:
+		return nil
+	}
+}
+
+// joinPath appends an object key to a JSON path
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
@@ -0,0 +1,82 @@
+package validation
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestFindDuplicateKeys(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want []DuplicateKey
+	}{
+		{"none", `{"a": 1, "b": 2}`, nil},
+		{"top level", `{"a": 1, "a": 2}`, []DuplicateKey{{Path: "a", Key: "a"}}},
+		{"nested", `{"env": {"X": "1", "Y": "2", "X": "3"}}`, []DuplicateKey{{Path: "env.X", Key: "X"}}},
+		{"in array", `{"list": [{"k": 1, "k": 2}]}`, []DuplicateKey{{Path: "list[0].k", Key: "k"}}},
+		{"same key in sibling objects", `{"a": {"k": 1}, "b": {"k": 2}}`, nil},
+		{"byte order mark", "\xEF\xBB\xBF{\"a\": 1, \"a\": 2}", []DuplicateKey{{Path: "a", Key: "a"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FindDuplicateKeys([]byte(tt.data))
+			if err != nil {
+				t.Fatalf("FindDuplicateKeys: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FindDuplicateKeys = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindDuplicateKeysErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"invalid", `{"a": `},
+		{"trailing data", `{"a": 1} {}`},
+		{"UTF-16", "\xFF\xFE{\x00}\x00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := FindDuplicateKeys([]byte(tt.data)); err == nil {
+				t.Error("FindDuplicateKeys succeeded, want an error")
+			}
+		})
+	}
+
+	if _, err := FindDuplicateKeys([]byte("\xFE\xFF\x00{\x00}")); !errors.Is(err, ErrUTF16) {
+		t.Errorf("FindDuplicateKeys error = %v, want ErrUTF16", err)
+	}
+}
+
+func TestFindDuplicateKeysDeeplyNested(t *testing.T) {
+	data := `{
+		"permissions": {
+			"allow": ["Read"],
+			"rules": [
+				{"tool": "Bash", "match": {"command": "ls", "command": "rm"}},
+				{"tool": "Edit"}
+			],
+			"allow": ["Write"]
+		}
+	}`
+
+	got, err := FindDuplicateKeys([]byte(data))
+	if err != nil {
+		t.Fatalf("FindDuplicateKeys: %v", err)
+	}
+	want := []DuplicateKey{
+		{Path: "permissions.rules[0].match.command", Key: "command"},
+		{Path: "permissions.allow", Key: "allow"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FindDuplicateKeys = %v, want %v", got, want)
+	}
+}
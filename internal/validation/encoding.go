@@ -0,0 +1,26 @@
+package validation
+
+import (
+	"bytes"
+	"errors"
+)
+
+// ErrUTF16 is returned for settings saved as UTF-16, which JSON parsers
+// here do not read
+var ErrUTF16 = errors.New("file is UTF-16, expected UTF-8")
+
+var (
+	bomUTF8    = []byte{0xEF, 0xBB, 0xBF}
+	bomUTF16LE = []byte{0xFF, 0xFE}
+	bomUTF16BE = []byte{0xFE, 0xFF}
+)
+
+// NormalizeEncoding returns data without a leading UTF-8 byte order mark,
+// as some editors such as Notepad add one. Data starting with a UTF-16 byte
+// order mark is rejected with ErrUTF16.
+func NormalizeEncoding(data []byte) ([]byte, error) {
+	if bytes.HasPrefix(data, bomUTF16LE) || bytes.HasPrefix(data, bomUTF16BE) {
+		return nil, ErrUTF16
+	}
+	return bytes.TrimPrefix(data, bomUTF8), nil
+}
@@ -0,0 +1,52 @@
+package validation
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNormalizeEncoding(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{"plain", `{"a": 1}`, `{"a": 1}`},
+		{"UTF-8 byte order mark", "\xEF\xBB\xBF{\"a\": 1}", `{"a": 1}`},
+		{"byte order mark inside", "{\"a\": \"\xEF\xBB\xBF\"}", "{\"a\": \"\xEF\xBB\xBF\"}"},
+		{"empty", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NormalizeEncoding([]byte(tt.data))
+			if err != nil {
+				t.Fatalf("NormalizeEncoding: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("NormalizeEncoding(%q) = %q, want %q", tt.data, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRejectUTF16(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"little endian", "\xFF\xFE{\x00}\x00"},
+		{"big endian", "\xFE\xFF\x00{\x00}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NormalizeEncoding([]byte(tt.data)); !errors.Is(err, ErrUTF16) {
+				t.Errorf("NormalizeEncoding error = %v, want ErrUTF16", err)
+			}
+			if err := ValidateClaudeSettings([]byte(tt.data)); !errors.Is(err, ErrUTF16) {
+				t.Errorf("ValidateClaudeSettings error = %v, want ErrUTF16", err)
+			}
+		})
+	}
+}
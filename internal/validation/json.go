@@ -1,6 +1,7 @@
 package validation
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -18,6 +19,11 @@ func ValidateJSONFile(filePath string) error {
 
 // ValidateJSON validates that the provided data is valid JSON
 func ValidateJSON(data []byte) error {
+	data, err := NormalizeEncoding(data)
+	if err != nil {
+		return err
+	}
+
 	var jsonData interface{}
 	if err := json.Unmarshal(data, &jsonData); err != nil {
 		return fmt.Errorf("invalid JSON format: %w", err)
@@ -27,20 +33,24 @@ func ValidateJSON(data []byte) error {
 
 // ValidateClaudeSettings validates that the JSON contains valid Claude Code settings
 func ValidateClaudeSettings(data []byte) error {
-	// First validate it's valid JSON
-	if err := ValidateJSON(data); err != nil {
+	data, err := NormalizeEncoding(data)
+	if err != nil {
 		return err
 	}
 
-	// Parse as a generic map to check structure
-	var settings map[string]interface{}
-	if err := json.Unmarshal(data, &settings); err != nil {
-		return fmt.Errorf("failed to parse JSON as object: %w", err)
+	if len(bytes.TrimSpace(data)) == 0 {
+		return fmt.Errorf("settings file is empty, expected a JSON object")
 	}
 
-	// Basic validation - should be a JSON object (map)
-	if settings == nil {
-		return fmt.Errorf("settings file must contain a JSON object, not null")
+	// First validate it's valid JSON
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return fmt.Errorf("invalid JSON format: %w", err)
+	}
+
+	// Settings must be a JSON object at the top level
+	if _, ok := value.(map[string]interface{}); !ok {
+		return fmt.Errorf("settings must be a JSON object, got %s", jsonKind(value))
 	}
 
 	// Optional: Add more specific validation for Claude Code settings
@@ -48,6 +58,24 @@ func ValidateClaudeSettings(data []byte) error {
 	return nil
 }
 
+// jsonKind names the JSON type of a decoded value for error messages
+func jsonKind(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "boolean"
+	default:
+		return "object"
+	}
+}
+
 // ValidateClaudeSettingsFile validates a Claude Code settings file
 func ValidateClaudeSettingsFile(filePath string) error {
 	data, err := os.ReadFile(filePath)
@@ -0,0 +1,41 @@
+package validation
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateClaudeSettings(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr string
+	}{
+		{"object", `{"model": "opus"}`, ""},
+		{"empty object", `{}`, ""},
+		{"object with byte order mark", "\xEF\xBB\xBF{}", ""},
+		{"empty", "", "settings file is empty"},
+		{"whitespace", " \n\t", "settings file is empty"},
+		{"null", `null`, "settings must be a JSON object, got null"},
+		{"array", `[{"model": "opus"}]`, "settings must be a JSON object, got array"},
+		{"string", `"opus"`, "settings must be a JSON object, got string"},
+		{"number", `42`, "settings must be a JSON object, got number"},
+		{"boolean", `true`, "settings must be a JSON object, got boolean"},
+		{"invalid", `{"model": }`, "invalid JSON format"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateClaudeSettings([]byte(tt.data))
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("ValidateClaudeSettings: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ValidateClaudeSettings error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package validation
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// StripJSONC removes // and /* */ comments and trailing commas from data,
+// leaving string literals untouched
+func StripJSONC(data []byte) []byte {
+	return stripTrailingCommas(stripComments(data))
+}
+
+// Repair attempts to salvage hand-edited settings by stripping comments and
+// trailing commas. It returns the result formatted with two-space indentation,
+// or an error if the data is still not a valid settings object.
+func Repair(data []byte) ([]byte, error) {
+	cleaned := StripJSONC(data)
+	if err := ValidateClaudeSettings(cleaned); err != nil {
+		return nil, fmt.Errorf("cannot repair: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, bytes.TrimSpace(cleaned), "", "  "); err != nil {
+		return nil, fmt.Errorf("failed to format JSON: %w", err)
+	}
+	buf.WriteByte('\n')
+
+	return buf.Bytes(), nil
+}
+
+// stripComments removes line and block comments outside of string literals
+func stripComments(data []byte) []byte {
+	out := make([]byte, 0, len(data))
+	inString := false
+
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+
+		if inString {
+			out = append(out, c)
+			if c == '\\' && i+1 < len(data) {
+				i++
+				out = append(out, data[i])
+			} else if c == '"' {
+				inString = false
+			}
+			continue
+		}
+
+		switch {
+		case c == '"':
+			inString = true
+			out = append(out, c)
+		case c == '/' && i+1 < len(data) && data[i+1] == '/':
+			for i < len(data) && data[i] != '\n' {
+				i++
+			}
+			if i < len(data) {
+				out = append(out, '\n')
+			}
+		case c == '/' && i+1 < len(data) && data[i+1] == '*':
+			i += 2
+			for i+1 < len(data) && !(data[i] == '*' && data[i+1] == '/') {
+				i++
+			}
+			i++
+		default:
+			out = append(out, c)
+		}
+	}
+
+	return out
+}
+
+// stripTrailingCommas removes commas that directly precede a closing } or ]
+func stripTrailingCommas(data []byte) []byte {
+	out := make([]byte, 0, len(data))
+	inString := false
+
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+
+		if inString {
+			out = append(out, c)
+			if c == '\\' && i+1 < len(data) {
+				i++
+				out = append(out, data[i])
+			} else if c == '"' {
+				inString = false
+			}
+			continue
+		}
+
+		if c == '"' {
+			inString = true
+		} else if c == ',' {
+			j := i + 1
+			for j < len(data) && isSpace(data[j]) {
+				j++
+			}
+			if j < len(data) && (data[j] == '}' || data[j] == ']') {
+				continue
+			}
+		}
+		out = append(out, c)
+	}
+
+	return out
+}
+
+// isSpace reports whether c is JSON insignificant whitespace
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
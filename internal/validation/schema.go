@@ -0,0 +1,356 @@
+package validation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// ErrSchemaViolation is returned by Schema.Validate when settings do not
+// match the schema
+var ErrSchemaViolation = errors.New("settings do not match the schema")
+
+// maxReportedViolations caps the violations listed in a validation error
+const maxReportedViolations = 5
+
+// Schema is a JSON Schema for settings files. It supports the keywords
+// settings schemas use: type, enum, const, properties, required,
+// additionalProperties, patternProperties, items, minItems, maxItems,
+// minimum, maximum, exclusiveMinimum, exclusiveMaximum, minLength,
+// maxLength, pattern, allOf, anyOf, oneOf, not and local $ref. Other
+// keywords, including remote references, are ignored.
+type Schema struct {
+	root interface{}
+}
+
+// ParseSchema parses a JSON Schema document. A local $ref that does not
+// resolve, or a cycle of references that never descends into the value,
+// makes the schema invalid.
+func ParseSchema(data []byte) (*Schema, error) {
+	var root interface{}
+	if err := json.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("invalid JSON schema: %w", err)
+	}
+
+	switch root.(type) {
+	case map[string]interface{}, bool:
+	default:
+		return nil, fmt.Errorf("invalid JSON schema: expected an object, got %s", jsonKind(root))
+	}
+
+	schema := &Schema{root: root}
+	if err := schema.checkRefs(); err != nil {
+		return nil, err
+	}
+	return schema, nil
+}
+
+// Validate checks JSON settings against the schema. The error lists the
+// first few violations by key path.
+func (s *Schema) Validate(data []byte) error {
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return fmt.Errorf("invalid JSON format: %w", err)
+	}
+
+	var violations []string
+	s.check(s.root, value, "", &violations)
+	if len(violations) == 0 {
+		return nil
+	}
+
+	message := strings.Join(violations[:min(len(violations), maxReportedViolations)], "; ")
+	if extra := len(violations) - maxReportedViolations; extra > 0 {
+		message += fmt.Sprintf(" (and %d more)", extra)
+	}
+	return fmt.Errorf("%w: %s", ErrSchemaViolation, message)
+}
+
+// check validates value at path against schema, appending violations
+func (s *Schema) check(schema, value interface{}, path string, violations *[]string) {
+	report := func(format string, args ...interface{}) {
+		location := path
+		if location == "" {
+			location = "(root)"
+		}
+		*violations = append(*violations, location+": "+fmt.Sprintf(format, args...))
+	}
+
+	rules, ok := schema.(map[string]interface{})
+	if !ok {
+		if allowed, isBool := schema.(bool); isBool && !allowed {
+			report("not allowed")
+		}
+		return
+	}
+
+	if ref, ok := rules["$ref"].(string); ok {
+		if target, found := s.resolve(ref); found {
+			s.check(target, value, path, violations)
+		}
+	}
+
+	if types, ok := rules["type"]; ok && !matchesType(types, value) {
+		report("expected %s, got %s", describeTypes(types), jsonKind(value))
+		return
+	}
+
+	if enum, ok := rules["enum"].([]interface{}); ok && !containsValue(enum, value) {
+		report("%s is not one of the allowed values", compact(value))
+	}
+	if constant, ok := rules["const"]; ok && !reflect.DeepEqual(constant, value) {
+		report("must be %s", compact(constant))
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		s.checkObject(rules, v, path, violations, report)
+	case []interface{}:
+		s.checkArray(rules, v, path, violations, report)
+	case string:
+		checkString(rules, v, report)
+	case float64:
+		checkNumber(rules, v, report)
+	}
+
+	if all, ok := rules["allOf"].([]interface{}); ok {
+		for _, sub := range all {
+			s.check(sub, value, path, violations)
+		}
+	}
+	if anyOf, ok := rules["anyOf"].([]interface{}); ok && s.countMatches(anyOf, value, path) == 0 {
+		report("does not match any of the allowed schemas")
+	}
+	if oneOf, ok := rules["oneOf"].([]interface{}); ok {
+		if n := s.countMatches(oneOf, value, path); n != 1 {
+			report("must match exactly one of the allowed schemas, matches %d", n)
+		}
+	}
+	if not, ok := rules["not"]; ok && s.countMatches([]interface{}{not}, value, path) == 1 {
+		report("matches a disallowed schema")
+	}
+}
+
+// checkObject applies the object keywords of rules to obj
+func (s *Schema) checkObject(rules, obj map[string]interface{}, path string, violations *[]string, report func(string, ...interface{})) {
+	if required, ok := rules["required"].([]interface{}); ok {
+		for _, key := range required {
+			if name, ok := key.(string); ok {
+				if _, present := obj[name]; !present {
+					report("missing required key '%s'", name)
+				}
+			}
+		}
+	}
+
+	properties, _ := rules["properties"].(map[string]interface{})
+	patterns, _ := rules["patternProperties"].(map[string]interface{})
+
+	keys := make([]string, 0, len(obj))
+	for key := range obj {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		child := joinPath(path, key)
+		matched := false
+
+		if sub, ok := properties[key]; ok {
+			s.check(sub, obj[key], child, violations)
+			matched = true
+		}
+		for pattern, sub := range patterns {
+			if re, err := regexp.Compile(pattern); err == nil && re.MatchString(key) {
+				s.check(sub, obj[key], child, violations)
+				matched = true
+			}
+		}
+
+		if matched {
+			continue
+		}
+		if additional, ok := rules["additionalProperties"]; ok {
+			if allowed, isBool := additional.(bool); isBool && !allowed {
+				*violations = append(*violations, child+": unknown key")
+				continue
+			}
+			s.check(additional, obj[key], child, violations)
+		}
+	}
+}
+
+// checkArray applies the array keywords of rules to items
+func (s *Schema) checkArray(rules map[string]interface{}, items []interface{}, path string, violations *[]string, report func(string, ...interface{})) {
+	if n, ok := number(rules["minItems"]); ok && float64(len(items)) < n {
+		report("must have at least %s items", formatNumber(n))
+	}
+	if n, ok := number(rules["maxItems"]); ok && float64(len(items)) > n {
+		report("must have at most %s items", formatNumber(n))
+	}
+
+	switch itemSchema := rules["items"].(type) {
+	case []interface{}:
+		// A list of schemas validates items by position
+		for i, sub := range itemSchema {
+			if i < len(items) {
+				s.check(sub, items[i], fmt.Sprintf("%s[%d]", path, i), violations)
+			}
+		}
+	case nil:
+	default:
+		for i, item := range items {
+			s.check(itemSchema, item, fmt.Sprintf("%s[%d]", path, i), violations)
+		}
+	}
+}
+
+// checkString applies the string keywords of rules to str
+func checkString(rules map[string]interface{}, str string, report func(string, ...interface{})) {
+	length := float64(utf8.RuneCountInString(str))
+	if n, ok := number(rules["minLength"]); ok && length < n {
+		report("must be at least %s characters", formatNumber(n))
+	}
+	if n, ok := number(rules["maxLength"]); ok && length > n {
+		report("must be at most %s characters", formatNumber(n))
+	}
+	if pattern, ok := rules["pattern"].(string); ok {
+		if re, err := regexp.Compile(pattern); err == nil && !re.MatchString(str) {
+			report("%q does not match the pattern %s", str, pattern)
+		}
+	}
+}
+
+// checkNumber applies the numeric keywords of rules to n
+func checkNumber(rules map[string]interface{}, n float64, report func(string, ...interface{})) {
+	if limit, ok := number(rules["minimum"]); ok && n < limit {
+		report("must be at least %s", formatNumber(limit))
+	}
+	if limit, ok := number(rules["maximum"]); ok && n > limit {
+		report("must be at most %s", formatNumber(limit))
+	}
+	if limit, ok := number(rules["exclusiveMinimum"]); ok && n <= limit {
+		report("must be greater than %s", formatNumber(limit))
+	}
+	if limit, ok := number(rules["exclusiveMaximum"]); ok && n >= limit {
+		report("must be less than %s", formatNumber(limit))
+	}
+}
+
+// countMatches returns how many of schemas value matches without violations
+func (s *Schema) countMatches(schemas []interface{}, value interface{}, path string) int {
+	matches := 0
+	for _, sub := range schemas {
+		var violations []string
+		s.check(sub, value, path, &violations)
+		if len(violations) == 0 {
+			matches++
+		}
+	}
+	return matches
+}
+
+// resolve follows a local reference such as "#/definitions/permissions"
+func (s *Schema) resolve(ref string) (interface{}, bool) {
+	pointer, ok := strings.CutPrefix(ref, "#")
+	if !ok {
+		return nil, false
+	}
+
+	current := s.root
+	for _, token := range strings.Split(strings.TrimPrefix(pointer, "/"), "/") {
+		if token == "" {
+			continue
+		}
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+
+		switch node := current.(type) {
+		case map[string]interface{}:
+			if current, ok = node[token]; !ok {
+				return nil, false
+			}
+		case []interface{}:
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i >= len(node) {
+				return nil, false
+			}
+			current = node[i]
+		default:
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// matchesType reports whether value has one of the JSON Schema types given
+// as a string or a list of strings
+func matchesType(types, value interface{}) bool {
+	names, ok := types.([]interface{})
+	if !ok {
+		names = []interface{}{types}
+	}
+
+	kind := jsonKind(value)
+	for _, name := range names {
+		switch name {
+		case kind:
+			return true
+		case "integer":
+			if n, ok := value.(float64); ok && n == math.Trunc(n) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// describeTypes renders a type keyword for error messages
+func describeTypes(types interface{}) string {
+	names, ok := types.([]interface{})
+	if !ok {
+		return fmt.Sprint(types)
+	}
+
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = fmt.Sprint(name)
+	}
+	return strings.Join(parts, " or ")
+}
+
+// containsValue reports whether values holds a value equal to value
+func containsValue(values []interface{}, value interface{}) bool {
+	for _, candidate := range values {
+		if reflect.DeepEqual(candidate, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// number returns a numeric keyword value
+func number(value interface{}) (float64, bool) {
+	n, ok := value.(float64)
+	return n, ok
+}
+
+// formatNumber renders a number without a trailing ".0"
+func formatNumber(n float64) string {
+	return strconv.FormatFloat(n, 'f', -1, 64)
+}
+
+// compact renders a decoded JSON value on one line
+func compact(value interface{}) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
@@ -0,0 +1,66 @@
+package validation
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseSchemaRejectsCircularRefs(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema string
+	}{
+		{"self", `{"definitions": {"a": {"$ref": "#/definitions/a"}}, "$ref": "#/definitions/a"}`},
+		{"root", `{"$ref": "#"}`},
+		{"pair", `{"definitions": {"a": {"$ref": "#/definitions/b"}, "b": {"$ref": "#/definitions/a"}}}`},
+		{"allOf", `{"definitions": {"a": {"allOf": [{"$ref": "#/definitions/a"}]}}}`},
+		{"not", `{"definitions": {"a": {"not": {"$ref": "#/definitions/a"}}}, "properties": {"x": {"$ref": "#/definitions/a"}}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseSchema([]byte(tt.schema))
+			if err == nil || !strings.Contains(err.Error(), "circular $ref") {
+				t.Errorf("ParseSchema error = %v, want a circular $ref error", err)
+			}
+		})
+	}
+}
+
+func TestParseSchemaRejectsUnresolvedRef(t *testing.T) {
+	_, err := ParseSchema([]byte(`{"properties": {"a": {"$ref": "#/definitions/missing"}}}`))
+	if err == nil || !strings.Contains(err.Error(), "does not resolve") {
+		t.Errorf("ParseSchema error = %v, want an unresolved $ref error", err)
+	}
+}
+
+func TestRecursiveSchemaThroughProperties(t *testing.T) {
+	// A tree schema refers to itself only for nested values, which is finite
+	schema, err := ParseSchema([]byte(`{
+		"definitions": {
+			"node": {
+				"type": "object",
+				"properties": {
+					"name": {"type": "string"},
+					"children": {"type": "array", "items": {"$ref": "#/definitions/node"}}
+				}
+			}
+		},
+		"$ref": "#/definitions/node"
+	}`))
+	if err != nil {
+		t.Fatalf("ParseSchema: %v", err)
+	}
+
+	if err := schema.Validate([]byte(`{"name": "a", "children": [{"name": "b", "children": []}]}`)); err != nil {
+		t.Errorf("Validate valid tree: %v", err)
+	}
+	err = schema.Validate([]byte(`{"name": "a", "children": [{"name": 1}]}`))
+	if !errors.Is(err, ErrSchemaViolation) {
+		t.Fatalf("Validate invalid tree error = %v, want ErrSchemaViolation", err)
+	}
+	if !strings.Contains(err.Error(), "children[0].name") {
+		t.Errorf("violation %q does not name children[0].name", err)
+	}
+}
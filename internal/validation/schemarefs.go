@@ -0,0 +1,152 @@
+package validation
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Keywords whose subschemas apply to the same value as the schema holding
+// them, and keywords whose subschemas apply to a nested value
+var (
+	sameValueKeywords   = []string{"allOf", "anyOf", "oneOf"}
+	schemaMapKeywords   = []string{"properties", "patternProperties", "definitions", "$defs"}
+	nestedValueKeywords = []string{"items", "additionalProperties"}
+)
+
+// checkRefs rejects a schema whose local $ref does not resolve, or whose
+// references and combinators lead back to a schema already being applied
+// to the same value. Validation would recurse forever on such a cycle, so
+// it is caught once here rather than while validating. Cycles that pass
+// through properties or items are fine: each step descends into the value.
+func (s *Schema) checkRefs() error {
+	checker := refChecker{schema: s, done: map[string]bool{}, active: map[string]bool{}}
+	return checker.walk(s.root, "")
+}
+
+// refChecker tracks the schemas a cycle search has visited, by JSON pointer
+type refChecker struct {
+	schema *Schema
+	// done holds schemas known to start no cycle, active the chain of
+	// schemas being applied to the current value
+	done   map[string]bool
+	active map[string]bool
+}
+
+// walk checks the schema at pointer and every subschema it contains
+func (c *refChecker) walk(node interface{}, pointer string) error {
+	rules, ok := node.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	if err := c.follow(rules, pointer); err != nil {
+		return err
+	}
+
+	for _, keyword := range sameValueKeywords {
+		subs, _ := rules[keyword].([]interface{})
+		for i, sub := range subs {
+			if err := c.walk(sub, pointer+"/"+keyword+"/"+strconv.Itoa(i)); err != nil {
+				return err
+			}
+		}
+	}
+	if not, ok := rules["not"]; ok {
+		if err := c.walk(not, pointer+"/not"); err != nil {
+			return err
+		}
+	}
+
+	for _, keyword := range schemaMapKeywords {
+		subs, _ := rules[keyword].(map[string]interface{})
+		keys := make([]string, 0, len(subs))
+		for key := range subs {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if err := c.walk(subs[key], pointer+"/"+keyword+"/"+escapePointer(key)); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, keyword := range nestedValueKeywords {
+		switch sub := rules[keyword].(type) {
+		case []interface{}:
+			for i, item := range sub {
+				if err := c.walk(item, pointer+"/"+keyword+"/"+strconv.Itoa(i)); err != nil {
+					return err
+				}
+			}
+		default:
+			if err := c.walk(sub, pointer+"/"+keyword); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// follow searches the schemas applied to the same value as the schema at
+// pointer, through $ref, allOf, anyOf, oneOf and not, for a cycle
+func (c *refChecker) follow(node interface{}, pointer string) error {
+	if c.done[pointer] {
+		return nil
+	}
+	if c.active[pointer] {
+		return fmt.Errorf("invalid JSON schema: circular $ref at #%s", pointer)
+	}
+
+	rules, ok := node.(map[string]interface{})
+	if !ok {
+		c.done[pointer] = true
+		return nil
+	}
+	c.active[pointer] = true
+
+	if ref, ok := rules["$ref"].(string); ok && strings.HasPrefix(ref, "#") {
+		target, found := c.schema.resolve(ref)
+		if !found {
+			return fmt.Errorf("invalid JSON schema: $ref %s at #%s does not resolve", ref, pointer)
+		}
+		if err := c.follow(target, normalizePointer(strings.TrimPrefix(ref, "#"))); err != nil {
+			return err
+		}
+	}
+	for _, keyword := range sameValueKeywords {
+		subs, _ := rules[keyword].([]interface{})
+		for i, sub := range subs {
+			if err := c.follow(sub, pointer+"/"+keyword+"/"+strconv.Itoa(i)); err != nil {
+				return err
+			}
+		}
+	}
+	if not, ok := rules["not"]; ok {
+		if err := c.follow(not, pointer+"/not"); err != nil {
+			return err
+		}
+	}
+
+	delete(c.active, pointer)
+	c.done[pointer] = true
+	return nil
+}
+
+// normalizePointer drops the empty tokens resolve skips, so a reference
+// names a schema the same way walk does
+func normalizePointer(pointer string) string {
+	var b strings.Builder
+	for _, token := range strings.Split(pointer, "/") {
+		if token != "" {
+			b.WriteString("/" + token)
+		}
+	}
+	return b.String()
+}
+
+// escapePointer escapes a key for use as a JSON pointer token
+func escapePointer(key string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(key, "~", "~0"), "/", "~1")
+}